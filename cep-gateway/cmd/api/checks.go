@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/platform/health"
+)
+
+// readinessChecks returns the readiness checks of the cep-gateway: configuration, the
+// weather-engine over the configured transport and, when traces leave the
+// process, the exporter endpoint
+func readinessChecks(cfg *config.Config) []health.Check {
+	checks := []health.Check{
+		{Name: "config", Run: func(context.Context) error { return cfg.Validate() }},
+	}
+
+	if cfg.EngineTransport == "grpc" {
+		checks = append(checks, health.Check{Name: "weather-engine", Run: health.Dial(cfg.WeatherEngineGRPC)})
+	} else {
+		liveness := strings.TrimSuffix(cfg.WeatherEngineURL, "/") + "/health/live"
+		checks = append(checks, health.Check{Name: "weather-engine", Run: health.HTTP(&http.Client{}, liveness)})
+	}
+
+	switch cfg.TracesExporter {
+	case "otlp", "":
+		checks = append(checks, health.Check{Name: "exporter", Run: health.Dial(health.Address(cfg.OtelExporterURL))})
+	case "zipkin":
+		checks = append(checks, health.Check{Name: "exporter", Run: health.Dial(health.Address(cfg.ZipkinEndpoint))})
+	}
+	return checks
+}
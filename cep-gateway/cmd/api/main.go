@@ -1,7 +1,168 @@
 package main
 
-import "fmt"
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/auth"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/client"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/handler"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/metrics"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/ratelimit"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/service"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/stream"
+	"github.com/alexduzi/laboteldistributedtracing/platform/health"
+	"github.com/alexduzi/laboteldistributedtracing/platform/logging"
+	"github.com/alexduzi/laboteldistributedtracing/platform/server"
+	"github.com/alexduzi/laboteldistributedtracing/platform/telemetry"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+)
 
 func main() {
-	fmt.Println("hello")
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fatal("Failed to load config", err)
+	}
+
+	gin.SetMode(cfg.GinMode)
+
+	level, err := logging.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		fatal("Invalid log level", err)
+	}
+
+	ctx := context.Background()
+	telemetryCfg := cfg.Telemetry()
+
+	var loggerProvider otellog.LoggerProvider
+	var shutdownLogs telemetry.ShutdownFunc
+	if cfg.LogExport {
+		shutdownLogs, err = telemetry.InitLoggerProvider(ctx, telemetryCfg)
+		if err != nil {
+			fatal("Failed to initialize logger provider", err)
+		}
+		loggerProvider = global.GetLoggerProvider()
+	}
+	slog.SetDefault(logging.New(os.Stdout, level, cfg.ServiceName, loggerProvider))
+
+	shutdown, err := telemetry.InitTracerProvider(ctx, telemetryCfg)
+	if err != nil {
+		fatal("Failed to initialize tracer provider", err)
+	}
+
+	shutdownMetrics, metricsHandler, err := telemetry.InitMeterProvider(ctx, telemetryCfg)
+	if err != nil {
+		fatal("Failed to initialize meter provider", err)
+	}
+
+	appMetrics, err := metrics.New(otel.Meter(metrics.MeterName))
+	if err != nil {
+		fatal("Failed to create metrics instruments", err)
+	}
+
+	var engineClient client.WeatherEngineClientInterface = client.NewWeatherEngineClient(cfg)
+	if cfg.EngineTransport == "grpc" {
+		grpcClient, err := client.NewWeatherEngineGRPCClient(cfg)
+		if err != nil {
+			fatal("Failed to create weather-engine gRPC client", err)
+		}
+		defer grpcClient.Close()
+		engineClient = grpcClient
+	}
+
+	temperatureService := service.NewTemperatureService(cfg, engineClient)
+
+	hub := stream.NewHub(temperatureService, cfg.StreamInterval)
+	streamHandler := handler.NewStreamHandler(hub)
+
+	routeLimits, err := ratelimit.ParseRoutes(cfg.RateLimitRoutes)
+	if err != nil {
+		fatal("Invalid RATE_LIMIT_ROUTES", err)
+	}
+	var failedAuthLimit ratelimit.Limit
+	if cfg.RateLimitAuthFailures != "" {
+		if failedAuthLimit, err = ratelimit.ParseLimit(cfg.RateLimitAuthFailures); err != nil {
+			fatal("Invalid RATE_LIMIT_AUTH_FAILURES", err)
+		}
+	}
+	var rateLimitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.RateLimitStore == config.RateLimitStoreRedis {
+		redisOptions, err := redis.ParseURL(cfg.RedisURL)
+		if err != nil {
+			fatal("Invalid REDIS_URL", err)
+		}
+		redisClient := redis.NewClient(redisOptions)
+		defer redisClient.Close()
+		rateLimitStore = ratelimit.NewRedisStore(redisClient)
+	}
+	keys, err := auth.Load(cfg.APIKeys, cfg.APIKeysFile)
+	if err != nil {
+		fatal("Failed to load API keys", err)
+	}
+	go keys.Watch(ctx, cfg.APIKeysReload)
+
+	tokens, err := auth.NewVerifier(auth.VerifierConfig{
+		Issuer:   cfg.OIDCIssuer,
+		Audience: cfg.OIDCAudience,
+		Scopes:   cfg.OIDCRequiredScopes,
+		JWKSURL:  cfg.OIDCJWKSURL,
+		JWKS:     cfg.OIDCJWKS,
+		CacheTTL: cfg.OIDCJWKSCacheTTL,
+	})
+	if err != nil {
+		fatal("Invalid OIDC settings", err)
+	}
+
+	limiter := ratelimit.New(rateLimitStore, ratelimit.Limit{Rate: cfg.RateLimit, Burst: cfg.RateLimitBurst}, routeLimits, failedAuthLimit, appMetrics)
+
+	checker := health.NewChecker(cfg.HealthCheckTTL, cfg.HealthCheckTimeout, readinessChecks(cfg)...)
+
+	router := handler.NewRouter(cfg.ServiceName, appMetrics, limiter, &auth.Authenticator{Keys: keys, Tokens: tokens}, cfg.LegacyErrors, cfg.TraceDebugHeader, handler.Handlers{
+		Temperature: handler.NewTemperatureHandler(cfg, temperatureService),
+		Stream:      streamHandler,
+		Health:      health.NewHandler(cfg.ServiceName, checker),
+		Metrics:     metricsHandler,
+	})
+
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		fatal("Invalid TRUSTED_PROXIES", err)
+	}
+
+	listener, err := net.Listen("tcp", ":"+cfg.Port)
+	if err != nil {
+		fatal("Failed to listen on HTTP port", err)
+	}
+
+	// telemetry is flushed once the server has drained, logs last so the shutdown logs are exported too
+	flush := []server.ShutdownFunc{server.ShutdownFunc(shutdown), server.ShutdownFunc(shutdownMetrics)}
+	if shutdownLogs != nil {
+		flush = append(flush, server.ShutdownFunc(shutdownLogs))
+	}
+
+	graceful := &server.Graceful{
+		Server:      &http.Server{Handler: router},
+		GracePeriod: cfg.ShutdownGracePeriod,
+		NotReady:    checker.Drain,
+		Drain:       []server.ShutdownFunc{streamHandler.Shutdown},
+		Flush:       flush,
+	}
+
+	slog.Info("Starting cep-gateway", "port", cfg.Port)
+	if err := graceful.Serve(ctx, listener); err != nil {
+		fatal("Failed to shut down cleanly", err)
+	}
+}
+
+// fatal logs the error and terminates the process
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/docs"
+)
+
+// openapi writes the OpenAPI document of the cep-gateway to stdout as JSON
+func main() {
+	if _, err := os.Stdout.Write(docs.SpecJSON()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
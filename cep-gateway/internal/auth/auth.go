@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/codec"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/ratelimit"
+	"github.com/alexduzi/laboteldistributedtracing/platform/problem"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HeaderAPIKey carries the API key; it may also be sent as a bearer token
+const HeaderAPIKey = "X-API-Key"
+
+// KeyNameAttribute records the name of the API key on the server span, never the key itself
+const KeyNameAttribute = attribute.Key("auth.key_name")
+
+// SubjectBaggageKey is the baggage member propagating the token subject to the weather-engine
+const SubjectBaggageKey = "enduser.id"
+
+const (
+	CodeUnauthorized      = "unauthorized"
+	CodeForbidden         = "forbidden"
+	CodeInvalidToken      = "invalid_token"
+	CodeInsufficientScope = "insufficient_scope"
+
+	MsgUnauthorized      = "missing or invalid API key"
+	MsgForbidden         = "API key not allowed on this route"
+	MsgInvalidToken      = "invalid or expired token"
+	MsgInsufficientScope = "token lacks the required scopes"
+)
+
+// Authenticator accepts API keys from Keys and JWT bearer tokens verified by Tokens;
+// either may be nil. A nil *Authenticator, or one with neither, lets every request through.
+type Authenticator struct {
+	Keys   *Keyring
+	Tokens *Verifier
+}
+
+type (
+	clientContextKey   struct{}
+	identityContextKey struct{}
+)
+
+// FromContext returns the client authenticated for the request
+func FromContext(ctx context.Context) (Client, bool) {
+	client, ok := ctx.Value(clientContextKey{}).(Client)
+	return client, ok
+}
+
+// Identity returns who authenticated the request: the name of the API key, or "sub:"
+// followed by the token subject, so keys and subjects never share an identity
+func Identity(ctx context.Context) (string, bool) {
+	identity, ok := ctx.Value(identityContextKey{}).(string)
+	return identity, ok
+}
+
+// authenticated returns a copy of ctx carrying the client and its identity, which the
+// rate limiter keys the client's bucket on
+func authenticated(ctx context.Context, client Client, identity string) context.Context {
+	ctx = context.WithValue(ctx, clientContextKey{}, client)
+	ctx = context.WithValue(ctx, identityContextKey{}, identity)
+	return ratelimit.WithClient(ctx, identity, client.Limit)
+}
+
+// Middleware authenticates API routes. A bearer token that looks like a JWT goes to
+// Tokens, whose subject is put into the request baggage and on the server span; any other
+// credential, in HeaderAPIKey or as a bearer token, must be a key from Keys. Missing or
+// invalid credentials get 401, and keys not allowed on the route or tokens without the
+// required scopes get 403.
+// The caller's name and limit are passed on to the rate limiter. A subject sent by the
+// caller in its own baggage is always dropped, so only verified subjects reach the engine.
+func Middleware(a *Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(withoutSubject(c.Request.Context()))
+
+		if a == nil || (a.Keys == nil && a.Tokens == nil) {
+			c.Next()
+			return
+		}
+
+		if token, ok := bearerToken(c.Request); a.Tokens != nil && (a.Keys == nil || ok && isJWT(token)) {
+			a.authenticateToken(c, token)
+			return
+		}
+		a.authenticateKey(c)
+	}
+}
+
+func (a *Authenticator) authenticateKey(c *gin.Context) {
+	client, ok := a.Keys.Lookup(credential(c.Request))
+	if !ok {
+		c.Header("WWW-Authenticate", `Bearer realm="cep-gateway"`)
+		codec.Abort(c, problem.New(http.StatusUnauthorized, CodeUnauthorized, MsgUnauthorized))
+		return
+	}
+
+	ctx := c.Request.Context()
+	trace.SpanFromContext(ctx).SetAttributes(KeyNameAttribute.String(client.Name))
+
+	if !client.Allows(c.FullPath()) {
+		codec.Abort(c, problem.New(http.StatusForbidden, CodeForbidden, MsgForbidden))
+		return
+	}
+
+	c.Request = c.Request.WithContext(authenticated(ctx, client, client.Name))
+	c.Next()
+}
+
+func (a *Authenticator) authenticateToken(c *gin.Context, token string) {
+	ctx := c.Request.Context()
+	span := trace.SpanFromContext(ctx)
+
+	principal, err := a.Tokens.Verify(ctx, token)
+	switch {
+	case errors.Is(err, ErrInsufficientScope):
+		span.AddEvent("token rejected", trace.WithAttributes(attribute.String("error", err.Error())))
+		c.Header("WWW-Authenticate", `Bearer realm="cep-gateway", error="insufficient_scope"`)
+		codec.Abort(c, problem.New(http.StatusForbidden, CodeInsufficientScope, MsgInsufficientScope))
+		return
+	case err != nil:
+		span.AddEvent("token rejected", trace.WithAttributes(attribute.String("error", err.Error())))
+		c.Header("WWW-Authenticate", `Bearer realm="cep-gateway", error="invalid_token"`)
+		codec.Abort(c, problem.New(http.StatusUnauthorized, CodeInvalidToken, MsgInvalidToken))
+		return
+	}
+
+	ctx, err = withSubject(ctx, principal.Subject)
+	if err != nil {
+		codec.Abort(c, problem.New(http.StatusUnauthorized, CodeInvalidToken, MsgInvalidToken))
+		return
+	}
+	span.SetAttributes(attribute.String(SubjectBaggageKey, principal.Subject))
+
+	c.Request = c.Request.WithContext(authenticated(ctx, Client{Name: principal.Subject}, "sub:"+principal.Subject))
+	c.Next()
+}
+
+// withSubject returns a copy of ctx whose baggage carries the token subject
+func withSubject(ctx context.Context, subject string) (context.Context, error) {
+	member, err := baggage.NewMemberRaw(SubjectBaggageKey, subject)
+	if err != nil {
+		return ctx, err
+	}
+	bag, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return ctx, err
+	}
+	return baggage.ContextWithBaggage(ctx, bag), nil
+}
+
+// withoutSubject returns a copy of ctx whose baggage has no subject
+func withoutSubject(ctx context.Context) context.Context {
+	bag := baggage.FromContext(ctx)
+	if bag.Member(SubjectBaggageKey).Key() == "" {
+		return ctx
+	}
+	return baggage.ContextWithBaggage(ctx, bag.DeleteMember(SubjectBaggageKey))
+}
+
+// credential returns the API key sent by the caller, or "" when there is none
+func credential(r *http.Request) string {
+	if key := r.Header.Get(HeaderAPIKey); key != "" {
+		return key
+	}
+	token, _ := bearerToken(r)
+	return token
+}
+
+// bearerToken returns the token of an Authorization: Bearer header
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// isJWT reports whether token has the three dot-separated parts of a signed JWT
+func isJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
@@ -0,0 +1,290 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	ErrInvalidToken      = errors.New("invalid token")
+	ErrInsufficientScope = errors.New("insufficient scope")
+)
+
+// signatureAlgorithms are the asymmetric algorithms accepted on tokens; HMAC is never
+// accepted since the key set is public
+var signatureAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+const (
+	// jwksMinRefresh bounds how often a token signed by an unknown key triggers a JWKS fetch
+	jwksMinRefresh = 10 * time.Second
+	// jwksMaxBackoff caps the wait between fetches while the JWKS keeps failing
+	jwksMaxBackoff = 5 * time.Minute
+)
+
+// VerifierConfig describes the tokens a Verifier accepts and where their keys come from:
+// JWKSURL is fetched and cached for CacheTTL, while JWKS is a static key set in JSON.
+type VerifierConfig struct {
+	Issuer   string
+	Audience string
+	Scopes   []string
+	JWKSURL  string
+	JWKS     string
+	CacheTTL time.Duration
+}
+
+// Principal is the caller a token authenticates
+type Principal struct {
+	Subject string
+	Scopes  []string
+}
+
+// Verifier validates JWT bearer tokens: signature, issuer, audience, expiry and the
+// required scopes. A nil *Verifier rejects every token.
+type Verifier struct {
+	issuer   string
+	audience string
+	scopes   []string
+	keys     *keySet
+	now      func() time.Time
+}
+
+// NewVerifier returns nil, which disables token authentication, when no key set is configured
+func NewVerifier(cfg VerifierConfig) (*Verifier, error) {
+	if cfg.JWKSURL == "" && cfg.JWKS == "" {
+		return nil, nil
+	}
+	if cfg.Issuer == "" || cfg.Audience == "" {
+		return nil, errors.New("token issuer and audience are required")
+	}
+	if cfg.JWKS == "" && cfg.CacheTTL <= 0 {
+		return nil, errors.New("JWKS cache TTL must be positive")
+	}
+
+	keys := &keySet{
+		url:    cfg.JWKSURL,
+		ttl:    cfg.CacheTTL,
+		now:    time.Now,
+		client: &http.Client{Timeout: 5 * time.Second, Transport: otelhttp.NewTransport(http.DefaultTransport)},
+	}
+	keys.cached.Store(&cachedKeys{})
+	if cfg.JWKS != "" {
+		var set jose.JSONWebKeySet
+		if err := json.Unmarshal([]byte(cfg.JWKS), &set); err != nil {
+			return nil, fmt.Errorf("invalid JWKS: %w", err)
+		}
+		keys.cached.Store(&cachedKeys{set: set})
+		keys.url = ""
+	}
+
+	return &Verifier{
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		scopes:   cfg.Scopes,
+		keys:     keys,
+		now:      time.Now,
+	}, nil
+}
+
+type tokenClaims struct {
+	jwt.Claims
+	Scope scopeList `json:"scope"`
+	Scp   scopeList `json:"scp"`
+}
+
+// Verify returns the principal of a valid token. It fails with ErrInvalidToken, or
+// with ErrInsufficientScope when the token is valid but lacks a required scope.
+func (v *Verifier) Verify(ctx context.Context, raw string) (Principal, error) {
+	if v == nil {
+		return Principal{}, ErrInvalidToken
+	}
+
+	token, err := jwt.ParseSigned(raw, signatureAlgorithms)
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	var kid string
+	if len(token.Headers) > 0 {
+		kid = token.Headers[0].KeyID
+	}
+	candidates, err := v.keys.lookup(ctx, kid)
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	var claims tokenClaims
+	verified := false
+	for _, key := range candidates {
+		if err := token.Claims(key.Public().Key, &claims); err == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return Principal{}, fmt.Errorf("%w: bad signature", ErrInvalidToken)
+	}
+
+	expected := jwt.Expected{Issuer: v.issuer, AnyAudience: jwt.Audience{v.audience}, Time: v.now()}
+	if err := claims.ValidateWithLeeway(expected, jwt.DefaultLeeway); err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if claims.Expiry == nil {
+		return Principal{}, fmt.Errorf("%w: missing exp", ErrInvalidToken)
+	}
+	if claims.Subject == "" {
+		return Principal{}, fmt.Errorf("%w: missing sub", ErrInvalidToken)
+	}
+
+	scopes := append(claims.Scope, claims.Scp...)
+	for _, required := range v.scopes {
+		if !slices.Contains(scopes, required) {
+			return Principal{}, fmt.Errorf("%w: missing %q", ErrInsufficientScope, required)
+		}
+	}
+
+	return Principal{Subject: claims.Subject, Scopes: scopes}, nil
+}
+
+// keySet serves the verification keys, refreshing them from url, when set, once they
+// are older than ttl or a token names a key they do not have. Lookups read the cached
+// keys without locking; a refresh replaces them, and concurrent refreshes share one fetch.
+// After a failed fetch the next one waits jwksMinRefresh, doubling with every further
+// failure up to jwksMaxBackoff, so an unreachable issuer does not slow every request.
+type keySet struct {
+	url    string
+	ttl    time.Duration
+	now    func() time.Time
+	client *http.Client
+
+	cached atomic.Pointer[cachedKeys]
+	group  singleflight.Group
+}
+
+// cachedKeys is a fetched key set, never modified once stored
+type cachedKeys struct {
+	set     jose.JSONWebKeySet
+	fetched time.Time
+	// attempted is when the last fetch, successful or not, was made
+	attempted time.Time
+	// failures counts the fetches that failed since the last successful one
+	failures int
+}
+
+// lookup returns the keys with kid, or every key when the token names none
+func (k *keySet) lookup(ctx context.Context, kid string) ([]jose.JSONWebKey, error) {
+	cached := k.cached.Load()
+	if k.url != "" && (cached.fetched.IsZero() || k.now().Sub(cached.fetched) > k.ttl) && cached.mayRetry(k.now()) {
+		cached = k.refresh(ctx, cached)
+	}
+
+	keys := cached.find(kid)
+	if len(keys) == 0 && k.url != "" && k.now().Sub(cached.attempted) > jwksMinRefresh && cached.mayRetry(k.now()) {
+		// the issuer may have rotated its keys since the last fetch
+		cached = k.refresh(ctx, cached)
+		keys = cached.find(kid)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return keys, nil
+}
+
+// mayRetry reports whether the backoff after the failed fetches has elapsed
+func (c *cachedKeys) mayRetry(now time.Time) bool {
+	if c.failures == 0 {
+		return true
+	}
+	backoff := min(jwksMinRefresh<<min(c.failures-1, 10), jwksMaxBackoff)
+	return now.Sub(c.attempted) >= backoff
+}
+
+func (c *cachedKeys) find(kid string) []jose.JSONWebKey {
+	if kid == "" {
+		return c.set.Keys
+	}
+	return c.set.Key(kid)
+}
+
+// refresh fetches the key set, keeping the cached keys when the fetch fails, and returns
+// the keys now cached. A caller that read seen before another refresh replaced it gets
+// the replacement without fetching again. The fetch outlives the cancellation of ctx,
+// since callers that joined it may still be waiting.
+func (k *keySet) refresh(ctx context.Context, seen *cachedKeys) *cachedKeys {
+	cached, _, _ := k.group.Do(k.url, func() (any, error) {
+		current := k.cached.Load()
+		if current != seen {
+			return current, nil
+		}
+
+		set, err := k.fetch(context.WithoutCancel(ctx))
+		now := k.now()
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to refresh JWKS, keeping the cached keys", "url", k.url, "error", err, "failures", current.failures+1)
+			failed := &cachedKeys{set: current.set, fetched: current.fetched, attempted: now, failures: current.failures + 1}
+			k.cached.Store(failed)
+			return failed, nil
+		}
+		fetched := &cachedKeys{set: set, fetched: now, attempted: now}
+		k.cached.Store(fetched)
+		return fetched, nil
+	})
+	return cached.(*cachedKeys)
+}
+
+func (k *keySet) fetch(ctx context.Context) (jose.JSONWebKeySet, error) {
+	var set jose.JSONWebKeySet
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.url, nil)
+	if err != nil {
+		return set, err
+	}
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return set, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return set, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return set, err
+	}
+	return set, nil
+}
+
+// scopeList reads the scopes of a token, written either as a space-separated
+// string ("scope", RFC 8693) or as a list ("scp")
+type scopeList []string
+
+func (s *scopeList) UnmarshalJSON(data []byte) error {
+	var joined string
+	if err := json.Unmarshal(data, &joined); err == nil {
+		*s = strings.Fields(joined)
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*s = list
+	return nil
+}
@@ -0,0 +1,406 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIssuer   = "https://issuer.example.com"
+	testAudience = "cep-gateway"
+)
+
+// testKey is a locally generated signing key published in the test JWKS
+type testKey struct {
+	kid     string
+	private any
+	alg     jose.SignatureAlgorithm
+}
+
+func newRSAKey(t *testing.T, kid string) testKey {
+	t.Helper()
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return testKey{kid: kid, private: private, alg: jose.RS256}
+}
+
+func newECKey(t *testing.T, kid string) testKey {
+	t.Helper()
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return testKey{kid: kid, private: private, alg: jose.ES256}
+}
+
+func (k testKey) public() jose.JSONWebKey {
+	key := jose.JSONWebKey{Key: k.private, KeyID: k.kid, Algorithm: string(k.alg), Use: "sig"}
+	return key.Public()
+}
+
+func jwksJSON(t *testing.T, keys ...testKey) string {
+	t.Helper()
+	set := jose.JSONWebKeySet{}
+	for _, key := range keys {
+		set.Keys = append(set.Keys, key.public())
+	}
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+	return string(data)
+}
+
+// sign issues a token with the standard claims of a valid token, overridden by extra
+func (k testKey) sign(t *testing.T, extra map[string]any) string {
+	t.Helper()
+
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: k.alg, Key: k.private},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader(jose.HeaderKey("kid"), k.kid),
+	)
+	require.NoError(t, err)
+
+	claims := map[string]any{
+		"iss":   testIssuer,
+		"aud":   testAudience,
+		"sub":   "user-123",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"iat":   time.Now().Unix(),
+		"scope": "temperature:read profile",
+	}
+	for name, value := range extra {
+		if value == nil {
+			delete(claims, name)
+			continue
+		}
+		claims[name] = value
+	}
+
+	token, err := jwt.Signed(signer).Claims(claims).Serialize()
+	require.NoError(t, err)
+	return token
+}
+
+// serveJWKS publishes keys from an httptest server, counting the fetches
+func serveJWKS(t *testing.T, keys *atomic.Pointer[string]) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(*keys.Load()))
+	}))
+	t.Cleanup(server.Close)
+	return server, &fetches
+}
+
+func newTestVerifier(t *testing.T, cfg VerifierConfig) *Verifier {
+	t.Helper()
+	cfg.Issuer, cfg.Audience = testIssuer, testAudience
+	verifier, err := NewVerifier(cfg)
+	require.NoError(t, err)
+	return verifier
+}
+
+func TestVerifier_ValidToken(t *testing.T) {
+	// arrange
+	key := newRSAKey(t, "key-1")
+	var published atomic.Pointer[string]
+	jwks := jwksJSON(t, key)
+	published.Store(&jwks)
+	server, _ := serveJWKS(t, &published)
+
+	verifier := newTestVerifier(t, VerifierConfig{JWKSURL: server.URL, Scopes: []string{"temperature:read"}, CacheTTL: time.Hour})
+
+	// act
+	principal, err := verifier.Verify(context.Background(), key.sign(t, nil))
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, "user-123", principal.Subject)
+	assert.ElementsMatch(t, []string{"temperature:read", "profile"}, principal.Scopes)
+}
+
+func TestVerifier_RejectsInvalidTokens(t *testing.T) {
+	key := newRSAKey(t, "key-1")
+	other := newRSAKey(t, "key-1")
+
+	testCases := []struct {
+		name  string
+		token func(t *testing.T) string
+		err   error
+	}{
+		{"Emissor diferente", func(t *testing.T) string { return key.sign(t, map[string]any{"iss": "https://evil.example.com"}) }, ErrInvalidToken},
+		{"Audiência diferente", func(t *testing.T) string { return key.sign(t, map[string]any{"aud": "other-api"}) }, ErrInvalidToken},
+		{"Expirado", func(t *testing.T) string {
+			return key.sign(t, map[string]any{"exp": time.Now().Add(-time.Hour).Unix(), "iat": time.Now().Add(-2 * time.Hour).Unix()})
+		}, ErrInvalidToken},
+		{"Sem expiração", func(t *testing.T) string { return key.sign(t, map[string]any{"exp": nil}) }, ErrInvalidToken},
+		{"Sem sujeito", func(t *testing.T) string { return key.sign(t, map[string]any{"sub": nil}) }, ErrInvalidToken},
+		{"Assinado por outra chave", func(t *testing.T) string { return other.sign(t, nil) }, ErrInvalidToken},
+		{"Malformado", func(t *testing.T) string { return "not.a.jwt" }, ErrInvalidToken},
+		{"Sem escopo exigido", func(t *testing.T) string { return key.sign(t, map[string]any{"scope": "profile"}) }, ErrInsufficientScope},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			verifier := newTestVerifier(t, VerifierConfig{JWKS: jwksJSON(t, key), Scopes: []string{"temperature:read"}})
+
+			// act
+			_, err := verifier.Verify(context.Background(), tc.token(t))
+
+			// assert
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestVerifier_RejectsHMAC(t *testing.T) {
+	// arrange
+	key := newRSAKey(t, "key-1")
+	verifier := newTestVerifier(t, VerifierConfig{JWKS: jwksJSON(t, key)})
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte("0123456789abcdef0123456789abcdef")}, nil)
+	require.NoError(t, err)
+	token, err := jwt.Signed(signer).Claims(jwt.Claims{
+		Issuer:   testIssuer,
+		Audience: jwt.Audience{testAudience},
+		Subject:  "user-123",
+		Expiry:   jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).Serialize()
+	require.NoError(t, err)
+
+	// act
+	_, err = verifier.Verify(context.Background(), token)
+
+	// assert
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestVerifier_ScpClaimList(t *testing.T) {
+	// arrange
+	key := newECKey(t, "ec-1")
+	verifier := newTestVerifier(t, VerifierConfig{JWKS: jwksJSON(t, key), Scopes: []string{"temperature:read"}})
+
+	// act
+	principal, err := verifier.Verify(context.Background(), key.sign(t, map[string]any{"scope": nil, "scp": []string{"temperature:read"}}))
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"temperature:read"}, principal.Scopes)
+}
+
+func TestVerifier_CachesJWKS(t *testing.T) {
+	// arrange
+	key := newRSAKey(t, "key-1")
+	var published atomic.Pointer[string]
+	jwks := jwksJSON(t, key)
+	published.Store(&jwks)
+	server, fetches := serveJWKS(t, &published)
+
+	verifier := newTestVerifier(t, VerifierConfig{JWKSURL: server.URL, CacheTTL: time.Hour})
+	now := time.Now()
+	verifier.keys.now = func() time.Time { return now }
+
+	// act
+	for range 3 {
+		_, err := verifier.Verify(context.Background(), key.sign(t, nil))
+		require.NoError(t, err)
+	}
+	cached := fetches.Load()
+
+	now = now.Add(2 * time.Hour)
+	_, err := verifier.Verify(context.Background(), key.sign(t, nil))
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), cached, "o JWKS deve ser buscado uma única vez dentro do TTL")
+	assert.Equal(t, int32(2), fetches.Load(), "o JWKS deve ser renovado após o TTL")
+}
+
+func TestVerifier_RefreshesJWKSOnKeyRotation(t *testing.T) {
+	// arrange
+	oldKey, newKey := newRSAKey(t, "key-1"), newRSAKey(t, "key-2")
+	var published atomic.Pointer[string]
+	jwks := jwksJSON(t, oldKey)
+	published.Store(&jwks)
+	server, fetches := serveJWKS(t, &published)
+
+	verifier := newTestVerifier(t, VerifierConfig{JWKSURL: server.URL, CacheTTL: time.Hour})
+	now := time.Now()
+	verifier.keys.now = func() time.Time { return now }
+
+	_, err := verifier.Verify(context.Background(), oldKey.sign(t, nil))
+	require.NoError(t, err)
+
+	rotated := jwksJSON(t, oldKey, newKey)
+	published.Store(&rotated)
+
+	// act
+	_, tooSoon := verifier.Verify(context.Background(), newKey.sign(t, nil))
+	now = now.Add(jwksMinRefresh + time.Second)
+	_, afterRefresh := verifier.Verify(context.Background(), newKey.sign(t, nil))
+
+	// assert
+	assert.ErrorIs(t, tooSoon, ErrInvalidToken, "chave desconhecida não deve forçar buscas seguidas")
+	assert.NoError(t, afterRefresh)
+	assert.Equal(t, int32(2), fetches.Load())
+}
+
+func TestVerifier_KeepsCachedKeysWhenJWKSFails(t *testing.T) {
+	// arrange
+	key := newRSAKey(t, "key-1")
+	var published atomic.Pointer[string]
+	jwks := jwksJSON(t, key)
+	published.Store(&jwks)
+	server, _ := serveJWKS(t, &published)
+
+	verifier := newTestVerifier(t, VerifierConfig{JWKSURL: server.URL, CacheTTL: time.Minute})
+	now := time.Now()
+	verifier.keys.now = func() time.Time { return now }
+
+	_, err := verifier.Verify(context.Background(), key.sign(t, nil))
+	require.NoError(t, err)
+
+	// act
+	server.Close()
+	now = now.Add(time.Hour)
+	_, err = verifier.Verify(context.Background(), key.sign(t, nil))
+
+	// assert
+	assert.NoError(t, err)
+}
+
+func TestVerifier_BacksOffWhileJWKSFails(t *testing.T) {
+	// arrange
+	key := newRSAKey(t, "key-1")
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	verifier := newTestVerifier(t, VerifierConfig{JWKSURL: server.URL, CacheTTL: time.Hour})
+	var now atomic.Pointer[time.Time]
+	start := time.Now()
+	now.Store(&start)
+	verifier.keys.now = func() time.Time { return *now.Load() }
+	advance := func(d time.Duration) {
+		next := now.Load().Add(d)
+		now.Store(&next)
+	}
+	token := key.sign(t, nil)
+
+	// act
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := verifier.Verify(context.Background(), token)
+			assert.ErrorIs(t, err, ErrInvalidToken)
+		}()
+	}
+	wg.Wait()
+	concurrent := fetches.Load()
+
+	for range 5 {
+		_, _ = verifier.Verify(context.Background(), token)
+	}
+	withinBackoff := fetches.Load()
+
+	advance(jwksMinRefresh)
+	_, _ = verifier.Verify(context.Background(), token)
+	afterFirstBackoff := fetches.Load()
+
+	advance(jwksMinRefresh)
+	_, _ = verifier.Verify(context.Background(), token)
+	doubledBackoff := fetches.Load()
+
+	advance(jwksMinRefresh)
+	_, _ = verifier.Verify(context.Background(), token)
+	afterSecondBackoff := fetches.Load()
+
+	// assert
+	assert.Equal(t, int32(1), concurrent, "requisições simultâneas devem compartilhar uma busca")
+	assert.Equal(t, int32(1), withinBackoff, "falhas não podem gerar uma busca por requisição")
+	assert.Equal(t, int32(2), afterFirstBackoff)
+	assert.Equal(t, int32(2), doubledBackoff, "a espera dobra a cada falha seguida")
+	assert.Equal(t, int32(3), afterSecondBackoff)
+}
+
+func TestVerifier_VerifiesWhileJWKSRefreshes(t *testing.T) {
+	// arrange
+	oldKey, newKey := newRSAKey(t, "key-1"), newRSAKey(t, "key-2")
+	jwks := jwksJSON(t, oldKey)
+	release := make(chan struct{})
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fetches.Add(1) > 1 {
+			<-release
+		}
+		_, _ = w.Write([]byte(jwks))
+	}))
+	t.Cleanup(server.Close)
+
+	verifier := newTestVerifier(t, VerifierConfig{JWKSURL: server.URL, CacheTTL: time.Hour})
+	now := time.Now()
+	verifier.keys.now = func() time.Time { return now }
+
+	_, err := verifier.Verify(context.Background(), oldKey.sign(t, nil))
+	require.NoError(t, err)
+	now = now.Add(jwksMinRefresh + time.Second)
+
+	refreshed := make(chan error, 1)
+	go func() {
+		_, err := verifier.Verify(context.Background(), newKey.sign(t, nil))
+		refreshed <- err
+	}()
+	require.Eventually(t, func() bool { return fetches.Load() == 2 }, time.Second, time.Millisecond)
+
+	// act
+	verified := make(chan error, 1)
+	go func() {
+		_, err := verifier.Verify(context.Background(), oldKey.sign(t, nil))
+		verified <- err
+	}()
+
+	// assert
+	select {
+	case err := <-verified:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Error("a verificação com chave em cache não pode esperar a busca do JWKS")
+	}
+	close(release)
+	assert.ErrorIs(t, <-refreshed, ErrInvalidToken, "a chave nova não foi publicada")
+}
+
+func TestNewVerifier(t *testing.T) {
+	// act
+	disabled, disabledErr := NewVerifier(VerifierConfig{Issuer: testIssuer, Audience: testAudience})
+	_, missingAudienceErr := NewVerifier(VerifierConfig{Issuer: testIssuer, JWKS: `{"keys":[]}`})
+	_, invalidJWKSErr := NewVerifier(VerifierConfig{Issuer: testIssuer, Audience: testAudience, JWKS: `{"keys":`})
+	_, zeroTTLErr := NewVerifier(VerifierConfig{Issuer: testIssuer, Audience: testAudience, JWKSURL: "https://issuer.example.com/jwks"})
+
+	// assert
+	assert.NoError(t, disabledErr)
+	assert.Nil(t, disabled)
+	assert.Error(t, missingAudienceErr)
+	assert.Error(t, invalidJWKSErr)
+	assert.Error(t, zeroTTLErr, "um TTL não positivo buscaria o JWKS a cada token")
+}
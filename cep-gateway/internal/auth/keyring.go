@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/ratelimit"
+)
+
+// Key is an API key as written in API_KEYS or the API_KEYS_FILE, e.g.
+//
+//	[{"name": "mobile-app", "key": "s3cr3t", "rate_limit": "5:10", "routes": ["/api/v1/temperature/:cep"]}]
+//
+// RateLimit ("rate:burst") replaces the default rate limit for the key, and Routes,
+// gin paths, restricts the key to them; both are optional.
+type Key struct {
+	Name      string   `json:"name"`
+	Key       string   `json:"key"`
+	RateLimit string   `json:"rate_limit,omitempty"`
+	Routes    []string `json:"routes,omitempty"`
+}
+
+// Client is the caller authenticated by an API key or, with only a name, by a token
+type Client struct {
+	Name   string
+	Limit  ratelimit.Limit
+	routes map[string]bool
+}
+
+// Allows reports whether the client may call route; clients without routes may call any
+func (c Client) Allows(route string) bool {
+	return len(c.routes) == 0 || c.routes[route]
+}
+
+// Keyring holds the accepted API keys, indexed by their SHA-256 so the lookup does not
+// leak the keys through timing. Keys loaded from a file are swapped atomically on Reload.
+// A nil *Keyring is valid and disables API key authentication.
+type Keyring struct {
+	path string
+	keys atomic.Pointer[map[[sha256.Size]byte]Client]
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+}
+
+// Load reads the API keys from path when set, or else from the JSON in inline.
+// It returns nil, which disables authentication, when both are empty.
+func Load(inline, path string) (*Keyring, error) {
+	if path == "" && inline == "" {
+		return nil, nil
+	}
+
+	k := &Keyring{path: path}
+	if path != "" {
+		if _, err := k.Reload(); err != nil {
+			return nil, err
+		}
+		return k, nil
+	}
+
+	keys, err := parseKeys([]byte(inline))
+	if err != nil {
+		return nil, err
+	}
+	k.keys.Store(&keys)
+	return k, nil
+}
+
+// Lookup returns the client that secret authenticates
+func (k *Keyring) Lookup(secret string) (Client, bool) {
+	if k == nil {
+		return Client{}, false
+	}
+	keys := k.keys.Load()
+	if keys == nil {
+		return Client{}, false
+	}
+	client, ok := (*keys)[sha256.Sum256([]byte(secret))]
+	return client, ok
+}
+
+// Reload re-reads the keys file when it changed since the last load and reports
+// whether the keys were replaced. On error the keys in use are kept.
+func (k *Keyring) Reload() (bool, error) {
+	if k == nil || k.path == "" {
+		return false, nil
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	info, err := os.Stat(k.path)
+	if err != nil {
+		return false, err
+	}
+	if k.keys.Load() != nil && info.ModTime().Equal(k.modTime) && info.Size() == k.size {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(k.path)
+	if err != nil {
+		return false, err
+	}
+	keys, err := parseKeys(data)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", k.path, err)
+	}
+
+	k.keys.Store(&keys)
+	k.modTime, k.size = info.ModTime(), info.Size()
+	return true, nil
+}
+
+// Watch reloads the keys file every interval until ctx is done
+func (k *Keyring) Watch(ctx context.Context, interval time.Duration) {
+	if k == nil || k.path == "" || interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := k.Reload()
+			if err != nil {
+				slog.ErrorContext(ctx, "Failed to reload API keys, keeping the current ones", "error", err)
+			} else if reloaded {
+				slog.InfoContext(ctx, "Reloaded API keys", "path", k.path)
+			}
+		}
+	}
+}
+
+func parseKeys(data []byte) (map[[sha256.Size]byte]Client, error) {
+	var entries []Key
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid API keys: %w", err)
+	}
+
+	keys := make(map[[sha256.Size]byte]Client, len(entries))
+	for i, entry := range entries {
+		if entry.Name == "" || entry.Key == "" {
+			return nil, fmt.Errorf("API key %d: name and key are required", i)
+		}
+
+		client := Client{Name: entry.Name}
+		if entry.RateLimit != "" {
+			limit, err := ratelimit.ParseLimit(entry.RateLimit)
+			if err != nil {
+				return nil, fmt.Errorf("API key %q: invalid rate_limit: %w", entry.Name, err)
+			}
+			client.Limit = limit
+		}
+		if len(entry.Routes) > 0 {
+			client.routes = make(map[string]bool, len(entry.Routes))
+			for _, route := range entry.Routes {
+				client.routes[route] = true
+			}
+		}
+
+		hash := sha256.Sum256([]byte(entry.Key))
+		if _, ok := keys[hash]; ok {
+			return nil, fmt.Errorf("API key %q: duplicate key", entry.Name)
+		}
+		keys[hash] = client
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no API keys configured")
+	}
+	return keys, nil
+}
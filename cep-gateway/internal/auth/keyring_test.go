@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKeys = `[
+	{"name": "mobile-app", "key": "mobile-secret", "rate_limit": "5:10"},
+	{"name": "batch-job", "key": "batch-secret", "routes": ["/api/v1/temperature:batch"]}
+]`
+
+func writeKeys(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestLoad_Disabled(t *testing.T) {
+	// act
+	keys, err := Load("", "")
+
+	// assert
+	require.NoError(t, err)
+	assert.Nil(t, keys)
+}
+
+func TestLoad_Inline(t *testing.T) {
+	// act
+	keys, err := Load(testKeys, "")
+
+	// assert
+	require.NoError(t, err)
+
+	client, ok := keys.Lookup("mobile-secret")
+	require.True(t, ok)
+	assert.Equal(t, "mobile-app", client.Name)
+	assert.Equal(t, ratelimit.Limit{Rate: 5, Burst: 10}, client.Limit)
+	assert.True(t, client.Allows("/api/v1/temperature/:cep"), "chave sem rotas pode chamar qualquer rota")
+
+	client, ok = keys.Lookup("batch-secret")
+	require.True(t, ok)
+	assert.True(t, client.Allows("/api/v1/temperature:batch"))
+	assert.False(t, client.Allows("/api/v1/temperature/:cep"))
+
+	_, ok = keys.Lookup("unknown")
+	assert.False(t, ok)
+	_, ok = keys.Lookup("")
+	assert.False(t, ok)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	testCases := []struct {
+		name   string
+		inline string
+	}{
+		{"JSON inválido", `{"name":`},
+		{"Lista vazia", `[]`},
+		{"Sem nome", `[{"key": "secret"}]`},
+		{"Sem chave", `[{"name": "app"}]`},
+		{"Limite inválido", `[{"name": "app", "key": "secret", "rate_limit": "fast"}]`},
+		{"Chave duplicada", `[{"name": "a", "key": "secret"}, {"name": "b", "key": "secret"}]`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			keys, err := Load(tc.inline, "")
+
+			// assert
+			assert.Error(t, err)
+			assert.Nil(t, keys)
+		})
+	}
+}
+
+func TestKeyring_ReloadFile(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "keys.json")
+	start := time.Now().Add(-time.Hour)
+	writeKeys(t, path, testKeys, start)
+
+	keys, err := Load("", path)
+	require.NoError(t, err)
+
+	// act
+	unchanged, err := keys.Reload()
+	require.NoError(t, err)
+
+	writeKeys(t, path, `[{"name": "rotated", "key": "new-secret"}]`, start.Add(time.Minute))
+	reloaded, err := keys.Reload()
+	require.NoError(t, err)
+
+	writeKeys(t, path, `not json`, start.Add(2*time.Minute))
+	_, invalidErr := keys.Reload()
+
+	// assert
+	assert.False(t, unchanged, "arquivo sem alterações não deve ser relido")
+	assert.True(t, reloaded)
+	assert.Error(t, invalidErr)
+
+	_, ok := keys.Lookup("mobile-secret")
+	assert.False(t, ok, "chave removida do arquivo deve ser rejeitada")
+	client, ok := keys.Lookup("new-secret")
+	require.True(t, ok, "arquivo inválido mantém as chaves em uso")
+	assert.Equal(t, "rotated", client.Name)
+}
+
+func TestKeyring_Watch(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "keys.json")
+	start := time.Now().Add(-time.Hour)
+	writeKeys(t, path, testKeys, start)
+
+	keys, err := Load("", path)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		keys.Watch(ctx, 5*time.Millisecond)
+		close(done)
+	}()
+
+	// act
+	writeKeys(t, path, `[{"name": "rotated", "key": "new-secret"}]`, start.Add(time.Minute))
+
+	// assert
+	assert.Eventually(t, func() bool {
+		_, ok := keys.Lookup("new-secret")
+		return ok
+	}, time.Second, 5*time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Watch não terminou após o cancelamento do contexto")
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	// act
+	keys, err := Load("", filepath.Join(t.TempDir(), "missing.json"))
+
+	// assert
+	assert.Error(t, err)
+	assert.Nil(t, keys)
+}
@@ -0,0 +1,135 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	cErrors "github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/client/error"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/model"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+type WeatherEngineClientInterface interface {
+	GetTemperature(ctx context.Context, cep string) (*model.TemperatureResponse, error)
+	// GetTemperatures looks up several valid, distinct CEPs in one call. Its results follow
+	// the order of ceps; the error is set only when the whole call failed.
+	GetTemperatures(ctx context.Context, ceps []string) ([]TemperatureResult, error)
+}
+
+// TemperatureResult holds the outcome of one CEP of a batch lookup
+type TemperatureResult struct {
+	Cep         string
+	Temperature *model.TemperatureResponse
+	Err         error
+}
+
+type WeatherEngineClient struct {
+	config *config.Config
+	client *http.Client
+}
+
+func NewWeatherEngineClient(cfg *config.Config) *WeatherEngineClient {
+	return &WeatherEngineClient{
+		config: cfg,
+		client: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+		},
+	}
+}
+
+func (w WeatherEngineClient) GetTemperature(ctx context.Context, cep string) (*model.TemperatureResponse, error) {
+	engineUrl := fmt.Sprintf("%s/api/v1/temperature/%s", w.config.WeatherEngineURL, url.PathEscape(cep))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", engineUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, engineError(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var temperatureRes model.TemperatureResponse
+	err = json.Unmarshal(body, &temperatureRes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &temperatureRes, nil
+}
+
+func (w WeatherEngineClient) GetTemperatures(ctx context.Context, ceps []string) ([]TemperatureResult, error) {
+	engineUrl := fmt.Sprintf("%s/api/v1/temperature:batch", w.config.WeatherEngineURL)
+
+	payload, err := json.Marshal(model.BatchTemperatureRequest{Ceps: ceps})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", engineUrl, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, engineError(resp)
+	}
+
+	var batch model.BatchTemperatureResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		return nil, err
+	}
+	if len(batch.Results) != len(ceps) {
+		return nil, fmt.Errorf("%w: %d results for %d ceps", cErrors.EngineClientUnexpectedError, len(batch.Results), len(ceps))
+	}
+
+	results := make([]TemperatureResult, len(ceps))
+	for i, item := range batch.Results {
+		results[i] = TemperatureResult{Cep: ceps[i]}
+		if item.Status == http.StatusOK {
+			results[i].Temperature = item.Temperature
+		} else {
+			results[i].Err = cErrors.NewEngineClientItemError(item.Status)
+		}
+	}
+	return results, nil
+}
+
+// maxProblemBytes bounds how much of a failed response is read looking for its problem
+const maxProblemBytes = 64 << 10
+
+// engineError maps a failed weather-engine response into a client error, by the code of
+// its problem body when it has one
+func engineError(resp *http.Response) error {
+	var p model.Problem
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxProblemBytes)).Decode(&p); err != nil {
+		p.Code = ""
+	}
+	return cErrors.NewEngineClientHTTPError(resp.StatusCode, resp.Header, p.Code)
+}
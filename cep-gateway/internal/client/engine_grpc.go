@@ -0,0 +1,159 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	cErrors "github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/client/error"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/model"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/pb"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// WeatherEngineGRPCClient reaches the weather-engine through its WeatherEngine gRPC service
+type WeatherEngineGRPCClient struct {
+	conn   *grpc.ClientConn
+	client pb.WeatherEngineClient
+}
+
+func NewWeatherEngineGRPCClient(cfg *config.Config, opts ...grpc.DialOption) (*WeatherEngineGRPCClient, error) {
+	opts = append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	}, opts...)
+
+	conn, err := grpc.NewClient(cfg.WeatherEngineGRPC, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WeatherEngineGRPCClient{
+		conn:   conn,
+		client: pb.NewWeatherEngineClient(conn),
+	}, nil
+}
+
+func (w *WeatherEngineGRPCClient) GetTemperature(ctx context.Context, cep string) (*model.TemperatureResponse, error) {
+	res, err := w.client.GetTemperature(ctx, &pb.GetTemperatureRequest{Cep: cep})
+	if err != nil {
+		return nil, newEngineClientGRPCError(err)
+	}
+
+	return fromProto(res.GetTemperature()), nil
+}
+
+// GetTemperatures calls BatchGetTemperature, placing each streamed result by its index
+func (w *WeatherEngineGRPCClient) GetTemperatures(ctx context.Context, ceps []string) ([]TemperatureResult, error) {
+	stream, err := w.client.BatchGetTemperature(ctx, &pb.BatchGetTemperatureRequest{Ceps: ceps})
+	if err != nil {
+		return nil, newEngineClientGRPCError(err)
+	}
+
+	results := make([]TemperatureResult, len(ceps))
+	received := 0
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, newEngineClientGRPCError(err)
+		}
+
+		i := int(msg.GetIndex())
+		if i < 0 || i >= len(ceps) || results[i].Cep != "" {
+			return nil, fmt.Errorf("%w: unexpected result index %d for %d ceps", cErrors.EngineClientUnexpectedError, i, len(ceps))
+		}
+		results[i] = TemperatureResult{Cep: ceps[i]}
+		if item := msg.GetError(); item != nil {
+			results[i].Err = newEngineClientItemGRPCError(item)
+		} else {
+			results[i].Temperature = fromProto(msg.GetTemperature())
+		}
+		received++
+	}
+
+	if received != len(ceps) {
+		return nil, fmt.Errorf("%w: %d results for %d ceps", cErrors.EngineClientUnexpectedError, received, len(ceps))
+	}
+	return results, nil
+}
+
+func fromProto(temperature *pb.Temperature) *model.TemperatureResponse {
+	return &model.TemperatureResponse{
+		Celsius:    temperature.GetCelsius(),
+		Fahrenheit: temperature.GetFahrenheit(),
+		Kelvin:     temperature.GetKelvin(),
+		Degraded:   temperature.GetDegraded(),
+		Resolution: temperature.GetResolution(),
+	}
+}
+
+// Close releases the underlying gRPC connection
+func (w *WeatherEngineGRPCClient) Close() error {
+	return w.conn.Close()
+}
+
+// newEngineClientGRPCError maps gRPC status codes into the same errors returned over HTTP.
+// Unavailable is a quota exhaustion when its ErrorInfo reason says so or, without one,
+// when its RetryInfo tells when to retry.
+func newEngineClientGRPCError(err error) error {
+	st := status.Convert(err)
+	retryAfter := retryDelay(st)
+	reason := errorReason(st)
+
+	switch {
+	case st.Code() == codes.InvalidArgument:
+		return cErrors.EngineClientInvalidZipcode
+	case st.Code() == codes.NotFound:
+		return cErrors.EngineClientNotFound
+	case st.Code() == codes.ResourceExhausted, reason == cErrors.EngineCodeRateLimited:
+		return &cErrors.ThrottledError{StatusCode: http.StatusTooManyRequests, RetryAfter: retryAfter}
+	case reason == cErrors.EngineCodeQuotaExceeded, st.Code() == codes.Unavailable && reason == "" && retryAfter > 0:
+		return &cErrors.ThrottledError{StatusCode: http.StatusServiceUnavailable, RetryAfter: retryAfter}
+	case st.Code() == codes.Canceled, st.Code() == codes.DeadlineExceeded:
+		return err
+	default:
+		return cErrors.EngineClientInternalError
+	}
+}
+
+// newEngineClientItemGRPCError maps the error of a failed batch item; items are
+// Unavailable only when the weather provider quota is exhausted
+func newEngineClientItemGRPCError(item *pb.Error) error {
+	code := codes.Code(item.GetCode())
+	if code == codes.Unavailable {
+		return &cErrors.ThrottledError{StatusCode: http.StatusServiceUnavailable}
+	}
+	return newEngineClientGRPCError(status.Error(code, item.GetMessage()))
+}
+
+// retryDelay returns the delay of the RetryInfo detail of st, or zero when it has none
+func retryDelay(st *status.Status) time.Duration {
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			return info.GetRetryDelay().AsDuration()
+		}
+	}
+	return 0
+}
+
+// errorReason returns the reason of the ErrorInfo detail of st, or "" when it has none
+func errorReason(st *status.Status) string {
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.GetReason()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,249 @@
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	cErrors "github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/client/error"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// fakeWeatherEngine simula o serviço gRPC do weather-engine
+type fakeWeatherEngine struct {
+	pb.UnimplementedWeatherEngineServer
+	err         error
+	temperature *pb.Temperature
+	traceparent string
+	// batch are the results streamed by BatchGetTemperature, in sending order
+	batch []*pb.BatchGetTemperatureResponse
+	ceps  []string
+}
+
+func (f *fakeWeatherEngine) GetTemperature(ctx context.Context, req *pb.GetTemperatureRequest) (*pb.GetTemperatureResponse, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("traceparent")) > 0 {
+		f.traceparent = md.Get("traceparent")[0]
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.temperature != nil {
+		return &pb.GetTemperatureResponse{Temperature: f.temperature}, nil
+	}
+	return &pb.GetTemperatureResponse{Temperature: &pb.Temperature{Celsius: 28.5, Fahrenheit: 83.3, Kelvin: 301.65}}, nil
+}
+
+func (f *fakeWeatherEngine) BatchGetTemperature(req *pb.BatchGetTemperatureRequest, stream grpc.ServerStreamingServer[pb.BatchGetTemperatureResponse]) error {
+	f.ceps = req.GetCeps()
+	if f.err != nil {
+		return f.err
+	}
+	for _, msg := range f.batch {
+		if err := stream.Send(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setupGRPCClient(t *testing.T, fake *fakeWeatherEngine) *WeatherEngineGRPCClient {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb.RegisterWeatherEngineServer(server, fake)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	client, err := NewWeatherEngineGRPCClient(&config.Config{WeatherEngineGRPC: "passthrough:///bufnet"},
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client
+}
+
+func TestWeatherEngineGRPCClient_GetTemperature_Success(t *testing.T) {
+	// arrange
+	client := setupGRPCClient(t, &fakeWeatherEngine{})
+
+	// act
+	result, err := client.GetTemperature(context.Background(), "01310100")
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, 28.5, result.Celsius)
+	assert.Equal(t, 83.3, result.Fahrenheit)
+	assert.Equal(t, 301.65, result.Kelvin)
+}
+
+func TestWeatherEngineGRPCClient_GetTemperature_Degraded(t *testing.T) {
+	// arrange
+	client := setupGRPCClient(t, &fakeWeatherEngine{
+		temperature: &pb.Temperature{Celsius: 28.5, Fahrenheit: 83.3, Kelvin: 301.65, Degraded: true, Resolution: "prefix"},
+	})
+
+	// act
+	result, err := client.GetTemperature(context.Background(), "17500000")
+
+	// assert
+	require.NoError(t, err)
+	assert.True(t, result.Degraded)
+	assert.Equal(t, "prefix", result.Resolution)
+}
+
+func TestWeatherEngineGRPCClient_GetTemperature_StatusMapping(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{"CEP inválido", status.Error(codes.InvalidArgument, "invalid zipcode"), cErrors.EngineClientInvalidZipcode},
+		{"CEP não encontrado", status.Error(codes.NotFound, "can not find zipcode"), cErrors.EngineClientNotFound},
+		{"Erro interno", status.Error(codes.Internal, "boom"), cErrors.EngineClientInternalError},
+		{"Indisponível", status.Error(codes.Unavailable, "down"), cErrors.EngineClientInternalError},
+		{"Tenant limitado", status.Error(codes.ResourceExhausted, "rate limit exceeded"), cErrors.EngineClientThrottled},
+		{"Cota do provedor esgotada", retryableError(t, codes.Unavailable, time.Minute), cErrors.EngineClientThrottled},
+		{"Cota do provedor esgotada sem RetryInfo", quotaError(t), cErrors.EngineClientThrottled},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			client := setupGRPCClient(t, &fakeWeatherEngine{err: tc.err})
+
+			// act
+			result, err := client.GetTemperature(context.Background(), "01310100")
+
+			// assert
+			assert.Nil(t, result)
+			assert.ErrorIs(t, err, tc.expected)
+		})
+	}
+}
+
+func retryableError(t *testing.T, code codes.Code, retryAfter time.Duration) error {
+	t.Helper()
+	st, err := status.New(code, "quota exceeded").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+	require.NoError(t, err)
+	return st.Err()
+}
+
+// quotaError is the Unavailable status of a quota exhaustion told only by its ErrorInfo reason
+func quotaError(t *testing.T) error {
+	t.Helper()
+	st, err := status.New(codes.Unavailable, "quota exceeded").WithDetails(&errdetails.ErrorInfo{Reason: cErrors.EngineCodeQuotaExceeded})
+	require.NoError(t, err)
+	return st.Err()
+}
+
+func TestWeatherEngineGRPCClient_GetTemperature_Throttled(t *testing.T) {
+	// arrange
+	client := setupGRPCClient(t, &fakeWeatherEngine{err: retryableError(t, codes.Unavailable, time.Minute)})
+
+	// act
+	_, err := client.GetTemperature(context.Background(), "01310100")
+
+	// assert
+	var throttled *cErrors.ThrottledError
+	require.ErrorAs(t, err, &throttled)
+	assert.Equal(t, http.StatusServiceUnavailable, throttled.StatusCode)
+	assert.Equal(t, time.Minute, throttled.RetryAfter)
+}
+
+func TestWeatherEngineGRPCClient_GetTemperatures_PlacesResultsByIndex(t *testing.T) {
+	// arrange
+	fake := &fakeWeatherEngine{batch: []*pb.BatchGetTemperatureResponse{
+		{Index: 2, Cep: "30130010", Result: &pb.BatchGetTemperatureResponse_Error{Error: &pb.Error{Code: int32(codes.Unavailable), Message: "quota exceeded"}}},
+		{Index: 0, Cep: "01310100", Result: &pb.BatchGetTemperatureResponse_Temperature{Temperature: &pb.Temperature{Celsius: 28.5}}},
+		{Index: 1, Cep: "99999999", Result: &pb.BatchGetTemperatureResponse_Error{Error: &pb.Error{Code: int32(codes.NotFound), Message: "can not find zipcode"}}},
+	}}
+	client := setupGRPCClient(t, fake)
+
+	// act
+	results, err := client.GetTemperatures(context.Background(), []string{"01310100", "99999999", "30130010"})
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"01310100", "99999999", "30130010"}, fake.ceps)
+	require.Len(t, results, 3)
+	assert.Equal(t, "01310100", results[0].Cep)
+	assert.Equal(t, 28.5, results[0].Temperature.Celsius)
+	assert.Equal(t, "99999999", results[1].Cep)
+	assert.ErrorIs(t, results[1].Err, cErrors.EngineClientNotFound)
+
+	var throttled *cErrors.ThrottledError
+	require.ErrorAs(t, results[2].Err, &throttled, "itens indisponíveis indicam cota esgotada")
+	assert.Equal(t, http.StatusServiceUnavailable, throttled.StatusCode)
+}
+
+func TestWeatherEngineGRPCClient_GetTemperatures_Errors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fake     *fakeWeatherEngine
+		expected error
+	}{
+		{"Tenant limitado", &fakeWeatherEngine{err: status.Error(codes.ResourceExhausted, "rate limit exceeded")}, cErrors.EngineClientThrottled},
+		{"Resultado faltando", &fakeWeatherEngine{batch: []*pb.BatchGetTemperatureResponse{
+			{Index: 0, Cep: "01310100", Result: &pb.BatchGetTemperatureResponse_Temperature{Temperature: &pb.Temperature{}}},
+		}}, cErrors.EngineClientUnexpectedError},
+		{"Índice fora da lista", &fakeWeatherEngine{batch: []*pb.BatchGetTemperatureResponse{
+			{Index: 5, Cep: "01310100", Result: &pb.BatchGetTemperatureResponse_Temperature{Temperature: &pb.Temperature{}}},
+		}}, cErrors.EngineClientUnexpectedError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			client := setupGRPCClient(t, tc.fake)
+
+			// act
+			results, err := client.GetTemperatures(context.Background(), []string{"01310100", "20040002"})
+
+			// assert
+			assert.Nil(t, results)
+			assert.ErrorIs(t, err, tc.expected)
+		})
+	}
+}
+
+func TestWeatherEngineGRPCClient_GetTemperature_PropagatesTraceContext(t *testing.T) {
+	// arrange
+	previousProvider := otel.GetTracerProvider()
+	previousPropagator := otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(tracetest.NewSpanRecorder())))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	fake := &fakeWeatherEngine{}
+	client := setupGRPCClient(t, fake)
+
+	ctx, span := otel.Tracer("test").Start(context.Background(), "parent")
+	defer span.End()
+
+	// act
+	_, err := client.GetTemperature(ctx, "01310100")
+
+	// assert
+	require.NoError(t, err)
+	assert.Contains(t, fake.traceparent, span.SpanContext().TraceID().String())
+}
@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cErrors "github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/client/error"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWeatherEngineClient_GetTemperature_Success(t *testing.T) {
+	// arrange
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_ = json.NewEncoder(w).Encode(model.TemperatureResponse{Celsius: 28.5, Fahrenheit: 83.3, Kelvin: 301.65})
+	}))
+	defer server.Close()
+
+	client := NewWeatherEngineClient(&config.Config{WeatherEngineURL: server.URL})
+
+	// act
+	result, err := client.GetTemperature(context.Background(), "01310100")
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/temperature/01310100", path)
+	assert.Equal(t, &model.TemperatureResponse{Celsius: 28.5, Fahrenheit: 83.3, Kelvin: 301.65}, result)
+}
+
+func TestWeatherEngineClient_GetTemperature_HTTPErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		status   int
+		body     string
+		expected error
+	}{
+		{"CEP inválido", http.StatusUnprocessableEntity, "", cErrors.EngineClientInvalidZipcode},
+		{"CEP não encontrado", http.StatusNotFound, "", cErrors.EngineClientNotFound},
+		{"Erro interno", http.StatusBadGateway, "", cErrors.EngineClientInternalError},
+		{"Indisponível", http.StatusServiceUnavailable, `{"code":"internal_error"}`, cErrors.EngineClientInternalError},
+		{"Tenant limitado", http.StatusTooManyRequests, "", cErrors.EngineClientThrottled},
+		{"Cota do provedor esgotada sem Retry-After", http.StatusServiceUnavailable, `{"status":503,"code":"quota_exceeded"}`, cErrors.EngineClientThrottled},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			client := NewWeatherEngineClient(&config.Config{WeatherEngineURL: server.URL})
+
+			// act
+			result, err := client.GetTemperature(context.Background(), "01310100")
+
+			// assert
+			assert.Nil(t, result)
+			assert.ErrorIs(t, err, tc.expected)
+		})
+	}
+}
+
+func TestWeatherEngineClient_GetTemperatures_ForwardsBatch(t *testing.T) {
+	// arrange
+	var path, contentType string
+	var received model.BatchTemperatureRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, contentType = r.URL.Path, r.Header.Get("Content-Type")
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		_, _ = w.Write([]byte(`{"results": [
+			{"cep": "01310100", "status": 200, "temperature": {"temp_C": 28.5, "temp_F": 83.3, "temp_K": 301.65}},
+			{"cep": "99999999", "status": 404, "error": {"message": "can not find zipcode"}},
+			{"cep": "30130010", "status": 503, "error": {"message": "weather provider quota exceeded, try again later"}}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := NewWeatherEngineClient(&config.Config{WeatherEngineURL: server.URL})
+
+	// act
+	results, err := client.GetTemperatures(context.Background(), []string{"01310100", "99999999", "30130010"})
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/temperature:batch", path)
+	assert.Equal(t, "application/json", contentType)
+	assert.Equal(t, []string{"01310100", "99999999", "30130010"}, received.Ceps)
+
+	require.Len(t, results, 3)
+	assert.Equal(t, TemperatureResult{Cep: "01310100", Temperature: &model.TemperatureResponse{Celsius: 28.5, Fahrenheit: 83.3, Kelvin: 301.65}}, results[0])
+	assert.Equal(t, "99999999", results[1].Cep)
+	assert.ErrorIs(t, results[1].Err, cErrors.EngineClientNotFound)
+	assert.ErrorIs(t, results[2].Err, cErrors.EngineClientThrottled, "itens 503 indicam cota esgotada")
+}
+
+func TestWeatherEngineClient_GetTemperatures_Errors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		status   int
+		body     string
+		expected error
+	}{
+		{"Tenant limitado", http.StatusTooManyRequests, "", cErrors.EngineClientThrottled},
+		{"Erro interno", http.StatusInternalServerError, "", cErrors.EngineClientInternalError},
+		{"Resultados faltando", http.StatusOK, `{"results": [{"cep": "01310100", "status": 200, "temperature": {}}]}`, cErrors.EngineClientUnexpectedError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			client := NewWeatherEngineClient(&config.Config{WeatherEngineURL: server.URL})
+
+			// act
+			results, err := client.GetTemperatures(context.Background(), []string{"01310100", "20040002"})
+
+			// assert
+			assert.Nil(t, results)
+			assert.ErrorIs(t, err, tc.expected)
+		})
+	}
+}
+
+func TestWeatherEngineClient_GetTemperature_PropagatesTraceContext(t *testing.T) {
+	// arrange
+	previousProvider := otel.GetTracerProvider()
+	previousPropagator := otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(tracetest.NewSpanRecorder())))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		_ = json.NewEncoder(w).Encode(model.TemperatureResponse{})
+	}))
+	defer server.Close()
+
+	ctx, span := otel.Tracer("test").Start(context.Background(), "parent")
+	defer span.End()
+
+	client := NewWeatherEngineClient(&config.Config{WeatherEngineURL: server.URL})
+
+	// act
+	_, err := client.GetTemperature(ctx, "01310100")
+
+	// assert
+	require.NoError(t, err)
+	assert.Contains(t, traceparent, span.SpanContext().TraceID().String())
+}
@@ -0,0 +1,80 @@
+package error
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	EngineClientInvalidZipcode  = errors.New("weather engine rejected the zipcode")
+	EngineClientNotFound        = errors.New("weather engine returned not found")
+	EngineClientThrottled       = errors.New("weather engine throttled the request")
+	EngineClientInternalError   = errors.New("weather engine internal error")
+	EngineClientUnexpectedError = errors.New("unexpected error from weather engine")
+)
+
+// Problem codes, also the gRPC ErrorInfo reasons, with which the weather-engine reports
+// that it rate limited the tenant or that its weather provider quota is exhausted
+const (
+	EngineCodeRateLimited   = "rate_limited"
+	EngineCodeQuotaExceeded = "quota_exceeded"
+)
+
+// ThrottledError is returned when the weather-engine rate limited the tenant (429) or
+// its weather provider quota is exhausted (503); it matches EngineClientThrottled
+type ThrottledError struct {
+	StatusCode int
+	// RetryAfter is how long the weather-engine asked to wait; zero when it did not say
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("%s: status code %d", EngineClientThrottled, e.StatusCode)
+}
+
+func (e *ThrottledError) Unwrap() error {
+	return EngineClientThrottled
+}
+
+// NewEngineClientHTTPError maps a weather-engine response status and the code of its
+// problem body, empty when the body is not a problem, into a client error. Without a
+// code, as in the legacy body, a 503 is a quota exhaustion only when it tells when to
+// retry in the Retry-After header.
+func NewEngineClientHTTPError(statusCode int, header http.Header, code string) error {
+	retryAfter := parseRetryAfter(header.Get("Retry-After"))
+
+	switch {
+	case statusCode == 422:
+		return EngineClientInvalidZipcode
+	case statusCode == 404:
+		return EngineClientNotFound
+	case code == EngineCodeRateLimited, code == EngineCodeQuotaExceeded,
+		statusCode == 429, statusCode == 503 && code == "" && retryAfter > 0:
+		return &ThrottledError{StatusCode: statusCode, RetryAfter: retryAfter}
+	case statusCode == 500, statusCode == 502, statusCode == 503, statusCode == 504:
+		return EngineClientInternalError
+	default:
+		return fmt.Errorf("%w: status code %d", EngineClientUnexpectedError, statusCode)
+	}
+}
+
+// NewEngineClientItemError maps the status of a failed item of a weather-engine batch into a
+// client error. Items answer 503 only when the weather provider quota is exhausted.
+func NewEngineClientItemError(statusCode int) error {
+	if statusCode == http.StatusServiceUnavailable {
+		return &ThrottledError{StatusCode: statusCode}
+	}
+	return NewEngineClientHTTPError(statusCode, http.Header{}, "")
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds, returning zero when it is absent or malformed
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
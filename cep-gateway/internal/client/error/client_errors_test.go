@@ -0,0 +1,84 @@
+package error
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEngineClientHTTPError_InvalidZipcode(t *testing.T) {
+	// act
+	err := NewEngineClientHTTPError(422, nil, "")
+
+	// assert
+	assert.ErrorIs(t, err, EngineClientInvalidZipcode)
+	assert.Equal(t, "weather engine rejected the zipcode", err.Error())
+}
+
+func TestNewEngineClientHTTPError_NotFound(t *testing.T) {
+	// act
+	err := NewEngineClientHTTPError(404, nil, "")
+
+	// assert
+	assert.ErrorIs(t, err, EngineClientNotFound)
+	assert.Equal(t, "weather engine returned not found", err.Error())
+}
+
+func TestNewEngineClientHTTPError_Throttled(t *testing.T) {
+	testCases := []struct {
+		name       string
+		status     int
+		retryAfter string
+		code       string
+		expected   time.Duration
+	}{
+		{"Tenant limitado", 429, "3", EngineCodeRateLimited, 3 * time.Second},
+		{"Tenant limitado sem Retry-After", 429, "", "", 0},
+		{"Cota do provedor esgotada", 503, "60", EngineCodeQuotaExceeded, time.Minute},
+		{"Cota esgotada sem Retry-After", 503, "", EngineCodeQuotaExceeded, 0},
+		{"Cota esgotada no corpo legado", 503, "60", "", time.Minute},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			header := http.Header{}
+			if tc.retryAfter != "" {
+				header.Set("Retry-After", tc.retryAfter)
+			}
+
+			// act
+			err := NewEngineClientHTTPError(tc.status, header, tc.code)
+
+			// assert
+			var throttled *ThrottledError
+			require.ErrorAs(t, err, &throttled)
+			assert.ErrorIs(t, err, EngineClientThrottled)
+			assert.Equal(t, tc.status, throttled.StatusCode)
+			assert.Equal(t, tc.expected, throttled.RetryAfter)
+		})
+	}
+}
+
+func TestNewEngineClientHTTPError_InternalErrors(t *testing.T) {
+	for _, status := range []int{500, 502, 503, 504} {
+		// act
+		err := NewEngineClientHTTPError(status, nil, "")
+
+		// assert
+		assert.ErrorIs(t, err, EngineClientInternalError)
+	}
+}
+
+func TestNewEngineClientHTTPError_UnexpectedStatusCode(t *testing.T) {
+	// act
+	err := NewEngineClientHTTPError(418, nil, "")
+
+	// assert
+	assert.True(t, errors.Is(err, EngineClientUnexpectedError))
+	assert.Contains(t, err.Error(), "status code 418")
+}
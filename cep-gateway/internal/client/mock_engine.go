@@ -0,0 +1,32 @@
+package client
+
+import (
+	"context"
+
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type WeatherEngineClientStub struct {
+	mock.Mock
+}
+
+func NewWeatherEngineClientStub() *WeatherEngineClientStub {
+	return &WeatherEngineClientStub{}
+}
+
+func (w *WeatherEngineClientStub) GetTemperature(ctx context.Context, cep string) (*model.TemperatureResponse, error) {
+	args := w.Called(ctx, cep)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TemperatureResponse), nil
+}
+
+func (w *WeatherEngineClientStub) GetTemperatures(ctx context.Context, ceps []string) ([]TemperatureResult, error) {
+	args := w.Called(ctx, ceps)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]TemperatureResult), nil
+}
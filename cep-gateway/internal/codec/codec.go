@@ -0,0 +1,159 @@
+// Package codec decodes API request bodies strictly and renders API responses in the media
+// type negotiated from the Accept header.
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/model"
+	"github.com/alexduzi/laboteldistributedtracing/platform/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// Offered lists the media types API responses are rendered in; the first is the default
+var Offered = []string{gin.MIMEJSON, gin.MIMEXML, gin.MIMEPlain}
+
+const (
+	CodeNotAcceptable        = "not_acceptable"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeBodyTooLarge         = "body_too_large"
+	CodeMalformedBody        = "malformed_body"
+	CodeValidationFailed     = "validation_failed"
+
+	MsgNotAcceptable        = "response can only be rendered as application/json, application/xml or text/plain"
+	MsgUnsupportedMediaType = "request body must be application/json"
+	MsgBodyTooLarge         = "request body is too large"
+	MsgMalformedBody        = "request body is not valid JSON"
+	MsgValidationFailed     = "request failed validation"
+)
+
+// Middleware answers 406 when the Accept header allows none of the Offered media types,
+// and 415 when a request that carries a body does not declare it as JSON
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.NegotiateFormat(Offered...) == "" {
+			Abort(c, problem.New(http.StatusNotAcceptable, CodeNotAcceptable, MsgNotAcceptable))
+			return
+		}
+
+		if hasBody(c.Request.Method) && !isJSON(c.ContentType()) {
+			Abort(c, problem.New(http.StatusUnsupportedMediaType, CodeUnsupportedMediaType, MsgUnsupportedMediaType))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func hasBody(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch
+}
+
+// isJSON accepts application/json and the structured +json types
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == gin.MIMEJSON || strings.HasSuffix(mediaType, "+json")
+}
+
+// Render writes body in the media type negotiated from the Accept header, falling back to
+// JSON when none of the Offered types is acceptable
+func Render(c *gin.Context, status int, body any) {
+	c.Header("Vary", "Accept")
+	switch c.NegotiateFormat(Offered...) {
+	case gin.MIMEXML:
+		c.XML(status, body)
+	case gin.MIMEPlain:
+		c.Data(status, gin.MIMEPlain+"; charset=utf-8", Text(body))
+	default:
+		c.JSON(status, body)
+	}
+}
+
+// Error renders p for the request in the negotiated media type: application/problem+json,
+// application/problem+xml or text/plain. Requests served in the legacy format get p as a
+// problem.ErrorResponse instead.
+func Error(c *gin.Context, p model.Problem) {
+	if problem.Legacy(c) {
+		Render(c, p.Status, p.ErrorResponse())
+		return
+	}
+
+	p = problem.ForRequest(c, p)
+	switch c.NegotiateFormat(Offered...) {
+	case gin.MIMEXML:
+		c.Header("Content-Type", problem.ContentTypeXML+"; charset=utf-8")
+	case gin.MIMEPlain:
+		// text/plain has no problem media type
+	default:
+		c.Header("Content-Type", problem.ContentType)
+	}
+	Render(c, p.Status, p)
+}
+
+// Abort renders p like Error and stops the remaining handlers
+func Abort(c *gin.Context, p model.Problem) {
+	c.Abort()
+	Error(c, p)
+}
+
+// Text renders body as one field=value line per scalar of its JSON form, in key order,
+// with the path of nested fields joined by dots and array elements by their index
+func Text(body any) []byte {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return []byte(err.Error() + "\n")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return []byte(err.Error() + "\n")
+	}
+
+	var buf bytes.Buffer
+	writeText(&buf, "", value)
+	return buf.Bytes()
+}
+
+func writeText(buf *bytes.Buffer, path string, value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			writeText(buf, join(path, key), v[key])
+		}
+	case []any:
+		for i, item := range v {
+			writeText(buf, join(path, strconv.Itoa(i)), item)
+		}
+	case nil:
+	default:
+		if path != "" {
+			buf.WriteString(path)
+			buf.WriteByte('=')
+		}
+		fmt.Fprintln(buf, v)
+	}
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,170 @@
+package codec
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/model"
+	"github.com/alexduzi/laboteldistributedtracing/platform/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRouter(legacy bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(problem.Middleware(legacy), Middleware())
+	router.GET("/error", func(c *gin.Context) {
+		Error(c, problem.New(http.StatusUnprocessableEntity, CodeValidationFailed, MsgValidationFailed,
+			model.ErrorDetail{Field: "ceps", Code: RuleRequired, Message: "is required"}))
+	})
+	router.POST("/echo", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	return router
+}
+
+func doRequest(router http.Handler, method, target string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequestWithContext(context.Background(), method, target, strings.NewReader(`{}`))
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestError_NegotiatesFormat(t *testing.T) {
+	const typ = problem.TypeBase + CodeValidationFailed
+
+	testCases := []struct {
+		name        string
+		accept      string
+		contentType string
+		body        string
+	}{
+		{"Sem Accept", "", problem.ContentType,
+			`{"type":"` + typ + `","title":"Unprocessable Entity","status":422,"detail":"request failed validation","instance":"/error",` +
+				`"code":"validation_failed","details":[{"field":"ceps","code":"required","message":"is required"}]}`},
+		{"Qualquer formato", "*/*", problem.ContentType,
+			`{"type":"` + typ + `","title":"Unprocessable Entity","status":422,"detail":"request failed validation","instance":"/error",` +
+				`"code":"validation_failed","details":[{"field":"ceps","code":"required","message":"is required"}]}`},
+		{"XML", "application/xml", problem.ContentTypeXML,
+			`<problem xmlns="urn:ietf:rfc:7807"><type>` + typ + `</type><title>Unprocessable Entity</title><status>422</status>` +
+				`<detail>request failed validation</detail><instance>/error</instance><code>validation_failed</code>` +
+				`<details><detail><field>ceps</field><code>required</code><message>is required</message></detail></details></problem>`},
+		{"Texto preferido", "text/plain, application/json;q=0.5", gin.MIMEPlain,
+			"code=validation_failed\ndetail=request failed validation\ndetails.0.code=required\ndetails.0.field=ceps\n" +
+				"details.0.message=is required\ninstance=/error\nstatus=422\ntitle=Unprocessable Entity\ntype=" + typ + "\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			router := setupRouter(false)
+			header := http.Header{}
+			if tc.accept != "" {
+				header.Set("Accept", tc.accept)
+			}
+
+			// act
+			rec := doRequest(router, http.MethodGet, "/error", header)
+
+			// assert
+			assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+			assert.Contains(t, rec.Header().Get("Content-Type"), tc.contentType)
+			assert.Equal(t, "Accept", rec.Header().Get("Vary"), "a resposta varia com o Accept")
+			assert.Equal(t, tc.body, rec.Body.String())
+		})
+	}
+}
+
+func TestError_Legacy(t *testing.T) {
+	testCases := []struct {
+		name        string
+		accept      string
+		contentType string
+		body        string
+	}{
+		{"JSON", "", gin.MIMEJSON, `{"message":"request failed validation"}`},
+		{"XML", "application/xml", gin.MIMEXML, `<ErrorResponse><message>request failed validation</message></ErrorResponse>`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			router := setupRouter(true)
+			header := http.Header{}
+			if tc.accept != "" {
+				header.Set("Accept", tc.accept)
+			}
+
+			// act
+			rec := doRequest(router, http.MethodGet, "/error", header)
+
+			// assert
+			assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+			assert.Contains(t, rec.Header().Get("Content-Type"), tc.contentType)
+			assert.Equal(t, tc.body, rec.Body.String())
+		})
+	}
+}
+
+func TestMiddleware_NotAcceptable(t *testing.T) {
+	// arrange
+	router := setupRouter(false)
+
+	// act
+	rec := doRequest(router, http.MethodGet, "/error", http.Header{"Accept": {"text/html"}})
+
+	// assert
+	assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+	assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `"code":"not_acceptable"`)
+	assert.Contains(t, rec.Body.String(), `"detail":"`+MsgNotAcceptable+`"`)
+}
+
+func TestMiddleware_ContentType(t *testing.T) {
+	testCases := []struct {
+		name        string
+		contentType string
+		expected    int
+	}{
+		{"JSON", "application/json", http.StatusNoContent},
+		{"JSON com charset", "application/json; charset=utf-8", http.StatusNoContent},
+		{"Tipo +json", "application/merge-patch+json", http.StatusNoContent},
+		{"Sem Content-Type", "", http.StatusUnsupportedMediaType},
+		{"Formulário", "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+		{"Content-Type inválido", "application/", http.StatusUnsupportedMediaType},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			router := setupRouter(false)
+			header := http.Header{}
+			if tc.contentType != "" {
+				header.Set("Content-Type", tc.contentType)
+			}
+
+			// act
+			rec := doRequest(router, http.MethodPost, "/echo", header)
+
+			// assert
+			assert.Equal(t, tc.expected, rec.Code)
+		})
+	}
+}
+
+func TestText_SkipsEmptyValues(t *testing.T) {
+	// arrange
+	body := model.BatchTemperatureItem{Cep: "01310100", Status: http.StatusOK}
+
+	// act
+	text := Text(body)
+
+	// assert
+	assert.Equal(t, "cep=01310100\nstatus=200\n", string(text))
+}
@@ -0,0 +1,136 @@
+package codec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+// Validation rules reported in model.ErrorDetail.Code
+const (
+	RuleRequired     = "required"
+	RuleUnknownField = "unknown_field"
+	RuleInvalidType  = "invalid_type"
+	RuleMaxItems     = "max_items"
+	RuleFormat       = "format"
+	RuleUnassigned   = "unassigned"
+	RuleSyntax       = "syntax"
+)
+
+var ErrMalformedBody = errors.New("malformed request body")
+
+// MalformedError tells where and why the body stopped being a single valid JSON value; it
+// wraps ErrMalformedBody and is answered with 422
+type MalformedError struct {
+	Offset int64
+	Reason string
+}
+
+func (e *MalformedError) Error() string {
+	return fmt.Sprintf("%s: %s at offset %d", ErrMalformedBody, e.Reason, e.Offset)
+}
+
+func (e *MalformedError) Unwrap() error {
+	return ErrMalformedBody
+}
+
+// Details reports the syntax failure as a detail about the whole body
+func (e *MalformedError) Details() []model.ErrorDetail {
+	return []model.ErrorDetail{{Code: RuleSyntax, Message: e.Reason, Offset: e.Offset}}
+}
+
+// ValidationError lists every problem that made a request invalid; it is answered with 422
+type ValidationError struct {
+	Details []model.ErrorDetail
+}
+
+func (e *ValidationError) Error() string {
+	problems := make([]string, 0, len(e.Details))
+	for _, detail := range e.Details {
+		problems = append(problems, fmt.Sprintf("%s: %s", detail.Field, detail.Message))
+	}
+	return "invalid request: " + strings.Join(problems, "; ")
+}
+
+// Invalid returns a ValidationError holding a single detail
+func Invalid(field, rule, message string) *ValidationError {
+	return &ValidationError{Details: []model.ErrorDetail{{Field: field, Code: rule, Message: message}}}
+}
+
+// DecodeJSON decodes the request body into dst, rejecting unknown fields and values of the
+// wrong type with a *ValidationError, and invalid JSON or anything after the first JSON value
+// with a *MalformedError. Bodies over maxBytes fail with *http.MaxBytesError.
+func DecodeJSON(c *gin.Context, maxBytes int64, dst any) error {
+	var body io.Reader = c.Request.Body
+	if maxBytes > 0 {
+		body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+	}
+	counter := &countingReader{r: body}
+
+	decoder := json.NewDecoder(counter)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(dst); err != nil {
+		return decodeError(err, counter.n)
+	}
+	end := decoder.InputOffset()
+	if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return err
+		}
+		return &MalformedError{Offset: end, Reason: "more than one JSON value"}
+	}
+	return nil
+}
+
+// countingReader counts the bytes read, which is where a truncated body ended
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// decodeError classifies the error of decoding a body of which read bytes were read
+func decodeError(err error, read int64) error {
+	var (
+		tooLarge  *http.MaxBytesError
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+
+	// encoding/json reports unknown fields only through the error text
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		if unquoted, unquoteErr := strconv.Unquote(field); unquoteErr == nil {
+			field = unquoted
+		}
+		return Invalid(field, RuleUnknownField, "is not a known field")
+	}
+
+	switch {
+	case errors.As(err, &tooLarge):
+		return err
+	case errors.Is(err, io.EOF):
+		return Invalid("", RuleRequired, "request body is required")
+	case errors.As(err, &syntaxErr):
+		return &MalformedError{Offset: syntaxErr.Offset, Reason: syntaxErr.Error()}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return &MalformedError{Offset: read, Reason: "unexpected end of JSON input"}
+	case errors.As(err, &typeErr):
+		return Invalid(typeErr.Field, RuleInvalidType, fmt.Sprintf("must not be a JSON %s", typeErr.Value))
+	default:
+		return &MalformedError{Offset: read, Reason: err.Error()}
+	}
+}
@@ -0,0 +1,107 @@
+package codec
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decode(body string, maxBytes int64) (model.BatchTemperatureRequest, error) {
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/", strings.NewReader(body))
+
+	var req model.BatchTemperatureRequest
+	err := DecodeJSON(c, maxBytes, &req)
+	return req, err
+}
+
+func TestDecodeJSON_Success(t *testing.T) {
+	// act
+	req, err := decode(`{"ceps":["01310100","20040-002"]}`, 1<<10)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"01310100", "20040-002"}, req.Ceps)
+}
+
+func TestDecodeJSON_ValidationErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		body     string
+		expected model.ErrorDetail
+	}{
+		{"Corpo vazio", ``, model.ErrorDetail{Field: "", Code: RuleRequired, Message: "request body is required"}},
+		{"Campo desconhecido", `{"ceps":[],"zip":"x"}`, model.ErrorDetail{Field: "zip", Code: RuleUnknownField, Message: "is not a known field"}},
+		{"Tipo inválido", `{"ceps":[1]}`, model.ErrorDetail{Field: "ceps.0", Code: RuleInvalidType, Message: "must not be a JSON number"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			_, err := decode(tc.body, 1<<10)
+
+			// assert
+			var validation *ValidationError
+			require.ErrorAs(t, err, &validation)
+			assert.Equal(t, []model.ErrorDetail{tc.expected}, validation.Details)
+		})
+	}
+}
+
+func TestDecodeJSON_MalformedBody(t *testing.T) {
+	testCases := []struct {
+		name     string
+		body     string
+		expected MalformedError
+	}{
+		{"Sintaxe inválida", `{"ceps":}`, MalformedError{Offset: 9, Reason: "invalid character '}' looking for beginning of value"}},
+		{"JSON incompleto", `{"ceps":["01310100"`, MalformedError{Offset: 19, Reason: "unexpected end of JSON input"}},
+		{"Valor após o objeto", `{"ceps":["01310100"]} {"ceps":[]}`, MalformedError{Offset: 21, Reason: "more than one JSON value"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			_, err := decode(tc.body, 1<<10)
+
+			// assert
+			assert.ErrorIs(t, err, ErrMalformedBody)
+			var malformed *MalformedError
+			require.ErrorAs(t, err, &malformed)
+			assert.Equal(t, tc.expected, *malformed)
+		})
+	}
+}
+
+func TestDecodeJSON_BodyTooLarge(t *testing.T) {
+	// arrange
+	body := `{"ceps":["` + strings.Repeat("0", 64) + `"]}`
+
+	// act
+	_, err := decode(body, 32)
+
+	// assert
+	var tooLarge *http.MaxBytesError
+	require.ErrorAs(t, err, &tooLarge)
+	assert.Equal(t, int64(32), tooLarge.Limit)
+}
+
+func TestDecodeJSON_TrailingDataOverLimit(t *testing.T) {
+	// arrange
+	body := `{"ceps":[]}` + strings.Repeat(" ", 64)
+
+	// act
+	_, err := decode(body, 32)
+
+	// assert
+	var tooLarge *http.MaxBytesError
+	assert.ErrorAs(t, err, &tooLarge, "espaços após o objeto também contam para o limite")
+}
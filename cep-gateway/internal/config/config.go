@@ -0,0 +1,283 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alexduzi/laboteldistributedtracing/platform/telemetry"
+	"github.com/spf13/viper"
+)
+
+type Config struct {
+	Port                  string
+	WeatherEngineURL      string
+	WeatherEngineGRPC     string
+	EngineTransport       string
+	GinMode               string
+	ServiceName           string
+	OtelExporterURL       string
+	OtelExporterProtocol  string
+	TracesExporter        string
+	ZipkinEndpoint        string
+	TracesSampler         string
+	TracesSamplerArg      float64
+	TraceDebugHeader      bool
+	MetricsMode           string
+	LogLevel              string
+	LogExport             bool
+	BatchMaxSize          int
+	MaxBodyBytes          int64
+	LegacyErrors          bool
+	StreamInterval        time.Duration
+	RateLimit             float64
+	RateLimitBurst        int
+	RateLimitRoutes       string
+	RateLimitStore        string
+	RateLimitAuthFailures string
+	RedisURL              string
+	TrustedProxies        []string
+	APIKeys               string
+	APIKeysFile           string
+	APIKeysReload         time.Duration
+	OIDCIssuer            string
+	OIDCAudience          string
+	OIDCRequiredScopes    []string
+	OIDCJWKSURL           string
+	OIDCJWKS              string
+	OIDCJWKSCacheTTL      time.Duration
+	HealthCheckTTL        time.Duration
+	HealthCheckTimeout    time.Duration
+	ShutdownGracePeriod   time.Duration
+}
+
+// Rate limit stores: memory limits each replica on its own, redis shares the buckets between replicas
+const (
+	RateLimitStoreMemory = "memory"
+	RateLimitStoreRedis  = "redis"
+)
+
+// Metrics modes: push exports over OTLP, pull serves /metrics for Prometheus to scrape
+const (
+	MetricsModePush = telemetry.MetricsModePush
+	MetricsModePull = telemetry.MetricsModePull
+)
+
+var AppConfig *Config
+
+// LoadConfig loads configuration from environment variables using Viper
+func LoadConfig() (*Config, error) {
+	viper.SetConfigFile(".env")
+	viper.AutomaticEnv()
+
+	// Set default values
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = viper.GetString("PORT")
+	}
+	if port == "" {
+		port = "8080" // Default fallback
+	}
+
+	viper.SetDefault("WEATHER_ENGINE", "http://localhost:8081")
+	viper.SetDefault("WEATHER_ENGINE_GRPC", "localhost:50051")
+	viper.SetDefault("ENGINE_TRANSPORT", "http") // http or grpc
+	viper.SetDefault("GIN_MODE", "debug")        // debug, release, or test
+	viper.SetDefault("OTEL_SERVICE_NAME", "cep-gateway")
+	viper.SetDefault("OTEL_EXPORTER_OTLP_PROTOCOL", "http/protobuf") // http/protobuf or grpc
+	viper.SetDefault("OTEL_TRACES_EXPORTER", "otlp")                 // otlp, zipkin, console, stdout, or none
+	viper.SetDefault("OTEL_EXPORTER_ZIPKIN_ENDPOINT", "http://localhost:9411/api/v2/spans")
+	viper.SetDefault("OTEL_TRACES_SAMPLER", "parentbased_always_on")
+	viper.SetDefault("OTEL_TRACES_SAMPLER_ARG", 1.0)
+	viper.SetDefault("TRACE_DEBUG_HEADER", false) // honour X-Debug-Trace, which lets any caller force sampling
+	viper.SetDefault("METRICS_MODE", MetricsModePush)
+	viper.SetDefault("LOG_LEVEL", "info") // debug, info, warn, or error
+	viper.SetDefault("LOG_EXPORT_OTLP", true)
+	viper.SetDefault("BATCH_MAX_SIZE", 250)
+	viper.SetDefault("MAX_BODY_BYTES", 64<<10) // larger request bodies get 413
+	viper.SetDefault("LEGACY_ERRORS", false)   // render errors as ErrorResponse instead of problem details
+	viper.SetDefault("STREAM_POLL_INTERVAL", "30s")
+	viper.SetDefault("RATE_LIMIT", 0) // requests per second per client, 0 disables
+	viper.SetDefault("RATE_LIMIT_BURST", 20)
+	viper.SetDefault("RATE_LIMIT_ROUTES", "") // route=rate:burst,... with their own bucket
+	viper.SetDefault("RATE_LIMIT_STORE", RateLimitStoreMemory)
+	viper.SetDefault("RATE_LIMIT_AUTH_FAILURES", "0.1:10") // rate:burst of failed authentications per IP, empty disables
+	viper.SetDefault("REDIS_URL", "redis://localhost:6379/0")
+	viper.SetDefault("TRUSTED_PROXIES", "") // comma-separated CIDRs allowed to set X-Forwarded-For
+	viper.SetDefault("API_KEYS", "")        // JSON list of API keys (see auth.Key), empty disables authentication
+	viper.SetDefault("API_KEYS_FILE", "")   // same JSON in a file, reloaded when it changes
+	viper.SetDefault("API_KEYS_RELOAD_INTERVAL", "30s")
+	viper.SetDefault("OIDC_ISSUER", "")
+	viper.SetDefault("OIDC_AUDIENCE", "")
+	viper.SetDefault("OIDC_REQUIRED_SCOPES", "") // comma-separated scopes every token must carry
+	viper.SetDefault("OIDC_JWKS_URL", "")        // JWKS endpoint; with OIDC_JWKS empty too, JWTs are not accepted
+	viper.SetDefault("OIDC_JWKS", "")            // static JWKS in JSON, instead of OIDC_JWKS_URL
+	viper.SetDefault("OIDC_JWKS_CACHE_TTL", "10m")
+	viper.SetDefault("HEALTH_CHECK_TTL", "30s") // how long a readiness check result is reused
+	viper.SetDefault("HEALTH_CHECK_TIMEOUT", "2s")
+	viper.SetDefault("SHUTDOWN_GRACE_PERIOD", "15s") // time to drain in-flight requests, and then to flush telemetry
+
+	// Try to read .env file, but don't fail if it doesn't exist
+	if err := viper.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+			slog.Info("No .env file found, using environment variables and defaults")
+		} else {
+			slog.Error("Error reading config file", "error", err)
+		}
+	}
+
+	config := &Config{
+		Port:                  port,
+		WeatherEngineURL:      viper.GetString("WEATHER_ENGINE"),
+		WeatherEngineGRPC:     viper.GetString("WEATHER_ENGINE_GRPC"),
+		EngineTransport:       viper.GetString("ENGINE_TRANSPORT"),
+		GinMode:               viper.GetString("GIN_MODE"),
+		ServiceName:           viper.GetString("OTEL_SERVICE_NAME"),
+		OtelExporterURL:       otlpEndpoint(viper.GetString("OTEL_EXPORTER_OTLP_ENDPOINT"), viper.GetString("OTEL_EXPORTER_OTLP_PROTOCOL")),
+		OtelExporterProtocol:  viper.GetString("OTEL_EXPORTER_OTLP_PROTOCOL"),
+		TracesExporter:        viper.GetString("OTEL_TRACES_EXPORTER"),
+		ZipkinEndpoint:        viper.GetString("OTEL_EXPORTER_ZIPKIN_ENDPOINT"),
+		TracesSampler:         viper.GetString("OTEL_TRACES_SAMPLER"),
+		TracesSamplerArg:      viper.GetFloat64("OTEL_TRACES_SAMPLER_ARG"),
+		TraceDebugHeader:      viper.GetBool("TRACE_DEBUG_HEADER"),
+		MetricsMode:           viper.GetString("METRICS_MODE"),
+		LogLevel:              viper.GetString("LOG_LEVEL"),
+		LogExport:             viper.GetBool("LOG_EXPORT_OTLP"),
+		BatchMaxSize:          viper.GetInt("BATCH_MAX_SIZE"),
+		MaxBodyBytes:          viper.GetInt64("MAX_BODY_BYTES"),
+		LegacyErrors:          viper.GetBool("LEGACY_ERRORS"),
+		StreamInterval:        viper.GetDuration("STREAM_POLL_INTERVAL"),
+		RateLimit:             viper.GetFloat64("RATE_LIMIT"),
+		RateLimitBurst:        viper.GetInt("RATE_LIMIT_BURST"),
+		RateLimitRoutes:       viper.GetString("RATE_LIMIT_ROUTES"),
+		RateLimitStore:        viper.GetString("RATE_LIMIT_STORE"),
+		RateLimitAuthFailures: viper.GetString("RATE_LIMIT_AUTH_FAILURES"),
+		RedisURL:              viper.GetString("REDIS_URL"),
+		TrustedProxies:        splitList(viper.GetString("TRUSTED_PROXIES")),
+		APIKeys:               viper.GetString("API_KEYS"),
+		APIKeysFile:           viper.GetString("API_KEYS_FILE"),
+		APIKeysReload:         viper.GetDuration("API_KEYS_RELOAD_INTERVAL"),
+		OIDCIssuer:            viper.GetString("OIDC_ISSUER"),
+		OIDCAudience:          viper.GetString("OIDC_AUDIENCE"),
+		OIDCRequiredScopes:    splitList(viper.GetString("OIDC_REQUIRED_SCOPES")),
+		OIDCJWKSURL:           viper.GetString("OIDC_JWKS_URL"),
+		OIDCJWKS:              viper.GetString("OIDC_JWKS"),
+		OIDCJWKSCacheTTL:      viper.GetDuration("OIDC_JWKS_CACHE_TTL"),
+		HealthCheckTTL:        viper.GetDuration("HEALTH_CHECK_TTL"),
+		HealthCheckTimeout:    viper.GetDuration("HEALTH_CHECK_TIMEOUT"),
+		ShutdownGracePeriod:   viper.GetDuration("SHUTDOWN_GRACE_PERIOD"),
+	}
+
+	// Readiness reports invalid settings; starting anyway keeps liveness probes answering
+	if err := config.Validate(); err != nil {
+		slog.Warn("Invalid configuration", "error", err)
+	}
+
+	AppConfig = config
+	return config, nil
+}
+
+// Validate reports the first setting that prevents the cep-gateway from serving requests
+func (c *Config) Validate() error {
+	switch c.EngineTransport {
+	case "http", "":
+		if u, err := url.Parse(c.WeatherEngineURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("WEATHER_ENGINE is not an absolute URL: %q", c.WeatherEngineURL)
+		}
+	case "grpc":
+		if _, _, err := net.SplitHostPort(c.WeatherEngineGRPC); err != nil {
+			return fmt.Errorf("WEATHER_ENGINE_GRPC is not a host:port address: %q", c.WeatherEngineGRPC)
+		}
+	default:
+		return fmt.Errorf("unknown ENGINE_TRANSPORT %q", c.EngineTransport)
+	}
+
+	switch c.RateLimitStore {
+	case RateLimitStoreMemory, "":
+	case RateLimitStoreRedis:
+		if u, err := url.Parse(c.RedisURL); err != nil || u.Host == "" {
+			return fmt.Errorf("REDIS_URL is not a valid URL: %q", c.RedisURL)
+		}
+	default:
+		return fmt.Errorf("unknown RATE_LIMIT_STORE %q", c.RateLimitStore)
+	}
+
+	if c.APIKeys != "" && c.APIKeysFile != "" {
+		return errors.New("API_KEYS and API_KEYS_FILE are both set")
+	}
+
+	if c.OIDCJWKSURL != "" || c.OIDCJWKS != "" {
+		if c.OIDCIssuer == "" || c.OIDCAudience == "" {
+			return errors.New("OIDC_ISSUER and OIDC_AUDIENCE are required to accept tokens")
+		}
+		if u, err := url.Parse(c.OIDCJWKSURL); c.OIDCJWKS == "" && (err != nil || u.Scheme == "" || u.Host == "") {
+			return fmt.Errorf("OIDC_JWKS_URL is not an absolute URL: %q", c.OIDCJWKSURL)
+		}
+		if c.OIDCJWKS == "" && c.OIDCJWKSCacheTTL <= 0 {
+			return fmt.Errorf("OIDC_JWKS_CACHE_TTL is not a positive duration: %s", c.OIDCJWKSCacheTTL)
+		}
+	}
+
+	switch c.MetricsMode {
+	case MetricsModePush, MetricsModePull, "":
+	default:
+		return fmt.Errorf("unknown METRICS_MODE %q", c.MetricsMode)
+	}
+
+	if c.StreamInterval <= 0 {
+		return fmt.Errorf("STREAM_POLL_INTERVAL is not a positive duration: %s", c.StreamInterval)
+	}
+	return nil
+}
+
+// splitList splits a comma-separated setting, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// otlpEndpoint returns endpoint, or when it is unset the local collector port for
+// protocol: 4317 for grpc and 4318 for http/protobuf
+func otlpEndpoint(endpoint, protocol string) string {
+	switch {
+	case endpoint != "":
+		return endpoint
+	case protocol == "grpc":
+		return "http://localhost:4317"
+	default:
+		return "http://localhost:4318"
+	}
+}
+
+// Telemetry selects the exporters and sampler for the shared telemetry providers
+func (c *Config) Telemetry() telemetry.Config {
+	return telemetry.Config{
+		ServiceName:      c.ServiceName,
+		TracesExporter:   c.TracesExporter,
+		Protocol:         c.OtelExporterProtocol,
+		Endpoint:         c.OtelExporterURL,
+		ZipkinEndpoint:   c.ZipkinEndpoint,
+		TracesSampler:    c.TracesSampler,
+		TracesSamplerArg: c.TracesSamplerArg,
+		MetricsMode:      c.MetricsMode,
+	}
+}
+
+// GetConfig returns the current configuration
+func GetConfig() *Config {
+	if AppConfig == nil {
+		slog.Error("Config not initialized. Call LoadConfig() first.")
+		os.Exit(1)
+	}
+	return AppConfig
+}
@@ -0,0 +1,233 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func resetViperAndConfig() {
+	viper.Reset()
+	AppConfig = nil
+}
+
+func TestLoadConfig_WithDefaultValues(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+
+	os.Unsetenv("PORT")
+	os.Unsetenv("WEATHER_ENGINE")
+	os.Unsetenv("WEATHER_ENGINE_GRPC")
+	os.Unsetenv("ENGINE_TRANSPORT")
+	os.Unsetenv("GIN_MODE")
+	os.Unsetenv("OTEL_SERVICE_NAME")
+	os.Unsetenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	os.Unsetenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	os.Unsetenv("OTEL_TRACES_EXPORTER")
+	os.Unsetenv("OTEL_EXPORTER_ZIPKIN_ENDPOINT")
+	os.Unsetenv("OTEL_TRACES_SAMPLER")
+	os.Unsetenv("OTEL_TRACES_SAMPLER_ARG")
+	os.Unsetenv("METRICS_MODE")
+	os.Unsetenv("LOG_LEVEL")
+	os.Unsetenv("LOG_EXPORT_OTLP")
+	os.Unsetenv("BATCH_MAX_SIZE")
+	os.Unsetenv("MAX_BODY_BYTES")
+	os.Unsetenv("LEGACY_ERRORS")
+	os.Unsetenv("STREAM_POLL_INTERVAL")
+	os.Unsetenv("RATE_LIMIT")
+	os.Unsetenv("RATE_LIMIT_BURST")
+	os.Unsetenv("RATE_LIMIT_ROUTES")
+	os.Unsetenv("RATE_LIMIT_AUTH_FAILURES")
+	os.Unsetenv("RATE_LIMIT_STORE")
+	os.Unsetenv("REDIS_URL")
+	os.Unsetenv("TRUSTED_PROXIES")
+	os.Unsetenv("API_KEYS")
+	os.Unsetenv("API_KEYS_FILE")
+	os.Unsetenv("API_KEYS_RELOAD_INTERVAL")
+	os.Unsetenv("OIDC_ISSUER")
+	os.Unsetenv("OIDC_AUDIENCE")
+	os.Unsetenv("OIDC_REQUIRED_SCOPES")
+	os.Unsetenv("OIDC_JWKS_URL")
+	os.Unsetenv("OIDC_JWKS")
+	os.Unsetenv("OIDC_JWKS_CACHE_TTL")
+	os.Unsetenv("HEALTH_CHECK_TTL")
+	os.Unsetenv("HEALTH_CHECK_TIMEOUT")
+	os.Unsetenv("SHUTDOWN_GRACE_PERIOD")
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.NotNil(t, config)
+	assert.Equal(t, "8080", config.Port)
+	assert.Equal(t, "http://localhost:8081", config.WeatherEngineURL)
+	assert.Equal(t, "localhost:50051", config.WeatherEngineGRPC)
+	assert.Equal(t, "http", config.EngineTransport)
+	assert.Equal(t, "debug", config.GinMode)
+	assert.Equal(t, "cep-gateway", config.ServiceName)
+	assert.Equal(t, "http://localhost:4318", config.OtelExporterURL)
+	assert.Equal(t, "http/protobuf", config.OtelExporterProtocol)
+	assert.Equal(t, "otlp", config.TracesExporter)
+	assert.Equal(t, "http://localhost:9411/api/v2/spans", config.ZipkinEndpoint)
+	assert.Equal(t, "parentbased_always_on", config.TracesSampler)
+	assert.Equal(t, 1.0, config.TracesSamplerArg)
+	assert.Equal(t, MetricsModePush, config.MetricsMode)
+	assert.Equal(t, "info", config.LogLevel)
+	assert.True(t, config.LogExport)
+	assert.Equal(t, 250, config.BatchMaxSize)
+	assert.Equal(t, int64(64<<10), config.MaxBodyBytes)
+	assert.False(t, config.LegacyErrors)
+	assert.Equal(t, 30*time.Second, config.StreamInterval)
+	assert.Equal(t, 0.0, config.RateLimit)
+	assert.Equal(t, 20, config.RateLimitBurst)
+	assert.Equal(t, "", config.RateLimitRoutes)
+	assert.Equal(t, RateLimitStoreMemory, config.RateLimitStore)
+	assert.Equal(t, "0.1:10", config.RateLimitAuthFailures)
+	assert.Equal(t, "redis://localhost:6379/0", config.RedisURL)
+	assert.Empty(t, config.TrustedProxies)
+	assert.Empty(t, config.APIKeys)
+	assert.Empty(t, config.APIKeysFile)
+	assert.Equal(t, 30*time.Second, config.APIKeysReload)
+	assert.Empty(t, config.OIDCIssuer)
+	assert.Empty(t, config.OIDCAudience)
+	assert.Empty(t, config.OIDCRequiredScopes)
+	assert.Empty(t, config.OIDCJWKSURL)
+	assert.Empty(t, config.OIDCJWKS)
+	assert.Equal(t, 10*time.Minute, config.OIDCJWKSCacheTTL)
+	assert.Equal(t, 30*time.Second, config.HealthCheckTTL)
+	assert.Equal(t, 2*time.Second, config.HealthCheckTimeout)
+	assert.Equal(t, 15*time.Second, config.ShutdownGracePeriod)
+	assert.Equal(t, config, AppConfig)
+}
+
+func TestLoadConfig_WithEnvironmentVariables(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+
+	os.Setenv("PORT", "3000")
+	os.Setenv("WEATHER_ENGINE", "http://weather-engine:8081")
+	os.Setenv("GIN_MODE", "release")
+	os.Setenv("BATCH_MAX_SIZE", "50")
+	os.Setenv("STREAM_POLL_INTERVAL", "5s")
+
+	defer func() {
+		os.Unsetenv("PORT")
+		os.Unsetenv("WEATHER_ENGINE")
+		os.Unsetenv("GIN_MODE")
+		os.Unsetenv("BATCH_MAX_SIZE")
+		os.Unsetenv("STREAM_POLL_INTERVAL")
+	}()
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "3000", config.Port)
+	assert.Equal(t, "http://weather-engine:8081", config.WeatherEngineURL)
+	assert.Equal(t, "release", config.GinMode)
+	assert.Equal(t, 50, config.BatchMaxSize)
+	assert.Equal(t, 5*time.Second, config.StreamInterval)
+}
+
+func TestGetConfig_WhenConfigIsLoaded(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+	_, _ = LoadConfig()
+
+	// act
+	config := GetConfig()
+
+	// assert
+	assert.NotNil(t, config)
+	assert.Equal(t, AppConfig, config)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{"http válido", Config{WeatherEngineURL: "http://localhost:8081", EngineTransport: "http", StreamInterval: time.Second}, ""},
+		{"grpc válido", Config{WeatherEngineGRPC: "localhost:50051", EngineTransport: "grpc", StreamInterval: time.Second}, ""},
+		{"URL do engine relativa", Config{WeatherEngineURL: "localhost", EngineTransport: "http"}, "WEATHER_ENGINE"},
+		{"endereço gRPC sem porta", Config{WeatherEngineGRPC: "localhost", EngineTransport: "grpc"}, "WEATHER_ENGINE_GRPC"},
+		{"transporte desconhecido", Config{EngineTransport: "amqp"}, "ENGINE_TRANSPORT"},
+		{"redis com URL válida", Config{WeatherEngineURL: "http://localhost:8081", RateLimitStore: RateLimitStoreRedis, RedisURL: "redis://redis:6379/0", StreamInterval: time.Second}, ""},
+		{"store de rate limit desconhecido", Config{WeatherEngineURL: "http://localhost:8081", RateLimitStore: "memcached"}, "RATE_LIMIT_STORE"},
+		{"chaves de API em duas origens", Config{WeatherEngineURL: "http://localhost:8081", APIKeys: "[]", APIKeysFile: "/etc/keys.json"}, "API_KEYS"},
+		{"OIDC completo", Config{WeatherEngineURL: "http://localhost:8081", OIDCJWKSURL: "https://issuer/jwks", OIDCIssuer: "https://issuer", OIDCAudience: "cep-gateway", OIDCJWKSCacheTTL: time.Minute, StreamInterval: time.Second}, ""},
+		{"cache do JWKS sem TTL", Config{WeatherEngineURL: "http://localhost:8081", OIDCJWKSURL: "https://issuer/jwks", OIDCIssuer: "https://issuer", OIDCAudience: "cep-gateway"}, "OIDC_JWKS_CACHE_TTL"},
+		{"OIDC sem audiência", Config{WeatherEngineURL: "http://localhost:8081", OIDCJWKSURL: "https://issuer/jwks", OIDCIssuer: "https://issuer"}, "OIDC_AUDIENCE"},
+		{"JWKS com URL relativa", Config{WeatherEngineURL: "http://localhost:8081", OIDCJWKSURL: "/jwks", OIDCIssuer: "https://issuer", OIDCAudience: "cep-gateway"}, "OIDC_JWKS_URL"},
+		{"intervalo de stream zerado", Config{WeatherEngineURL: "http://localhost:8081"}, "STREAM_POLL_INTERVAL"},
+		{"intervalo de stream negativo", Config{WeatherEngineURL: "http://localhost:8081", StreamInterval: -time.Second}, "STREAM_POLL_INTERVAL"},
+		{"modo de métricas desconhecido", Config{WeatherEngineURL: "http://localhost:8081", MetricsMode: "poll"}, "METRICS_MODE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// act
+			err := tt.config.Validate()
+
+			// assert
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_TrustedProxies(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+	os.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.1,")
+	defer os.Unsetenv("TRUSTED_PROXIES")
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.1"}, config.TrustedProxies)
+}
+
+func TestLoadConfig_OTLPEndpointDefaultsByProtocol(t *testing.T) {
+	tests := []struct {
+		name     string
+		protocol string
+		endpoint string
+		expected string
+	}{
+		{"http/protobuf sem endpoint", "http/protobuf", "", "http://localhost:4318"},
+		{"grpc sem endpoint", "grpc", "", "http://localhost:4317"},
+		{"grpc com endpoint", "grpc", "http://otel-collector:4317", "http://otel-collector:4317"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			resetViperAndConfig()
+			os.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", tt.protocol)
+			os.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", tt.endpoint)
+			defer func() {
+				os.Unsetenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+				os.Unsetenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+			}()
+
+			// act
+			config, err := LoadConfig()
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, config.OtelExporterURL)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+// Package docs serves the OpenAPI document of the cep-gateway and Swagger UI to browse it.
+// The document is maintained by hand in openapi.yaml; its tests check it against the
+// registered routes and the response models.
+package docs
+
+import (
+	_ "embed"
+
+	"github.com/alexduzi/laboteldistributedtracing/platform/apidocs"
+	"github.com/gin-gonic/gin"
+)
+
+// Path is where Swagger UI is served; the document is at Path/openapi.json and Path/openapi.yaml
+const Path = apidocs.Path
+
+//go:embed openapi.yaml
+var specYAML []byte
+
+var document = apidocs.MustNew(specYAML)
+
+// Spec returns the OpenAPI document in YAML
+func Spec() []byte {
+	return document.YAML()
+}
+
+// SpecJSON returns the OpenAPI document in JSON
+func SpecJSON() []byte {
+	return document.JSON()
+}
+
+// Register serves Swagger UI and the OpenAPI document under Path
+func Register(router gin.IRouter) {
+	document.Register(router)
+}
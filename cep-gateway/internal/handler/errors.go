@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/alexduzi/laboteldistributedtracing/cep"
+	cErrors "github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/client/error"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/codec"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/model"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/ratelimit"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/service"
+	"github.com/alexduzi/laboteldistributedtracing/platform/problem"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	CodeInvalidZipcode  = "invalid_zipcode"
+	CodeZipcodeNotFound = "zipcode_not_found"
+	CodeQuotaExceeded   = "quota_exceeded"
+	CodeInternalError   = "internal_error"
+
+	MsgInvalidZipcode  = "invalid zipcode"
+	MsgZipcodeNotFound = "can not find zipcode"
+	MsgQuotaExceeded   = "weather provider quota exceeded, try again later"
+	MsgInternalError   = "internal server error"
+)
+
+// writeClientError maps the service, weather-engine and request errors into HTTP responses
+func writeClientError(c *gin.Context, err error) {
+	p := problemFor(err)
+	if p.Status >= http.StatusInternalServerError {
+		slog.ErrorContext(c.Request.Context(), "request failed", "error", err)
+	}
+	var throttled *cErrors.ThrottledError
+	if errors.As(err, &throttled) && throttled.RetryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+	}
+	codec.Error(c, p)
+}
+
+// problemFor translates service, client and request errors into the problem answered for them
+func problemFor(err error) model.Problem {
+	var (
+		validation *codec.ValidationError
+		malformed  *codec.MalformedError
+		tooLarge   *http.MaxBytesError
+		throttled  *cErrors.ThrottledError
+	)
+
+	switch {
+	case errors.Is(err, service.ErrInvalidZipcode), errors.Is(err, cErrors.EngineClientInvalidZipcode):
+		return problem.New(http.StatusUnprocessableEntity, CodeInvalidZipcode, MsgInvalidZipcode, zipcodeDetails(err)...)
+	case errors.Is(err, cErrors.EngineClientNotFound):
+		return problem.New(http.StatusNotFound, CodeZipcodeNotFound, MsgZipcodeNotFound)
+	case errors.As(err, &throttled) && throttled.StatusCode == http.StatusServiceUnavailable:
+		return problem.New(http.StatusServiceUnavailable, CodeQuotaExceeded, MsgQuotaExceeded)
+	case errors.As(err, &throttled):
+		return problem.New(http.StatusTooManyRequests, ratelimit.CodeRateLimited, ratelimit.MsgRateLimited)
+	case errors.As(err, &validation):
+		return problem.New(http.StatusUnprocessableEntity, codec.CodeValidationFailed, codec.MsgValidationFailed, validation.Details...)
+	case errors.As(err, &malformed):
+		return problem.New(http.StatusUnprocessableEntity, codec.CodeMalformedBody, codec.MsgMalformedBody, malformed.Details()...)
+	case errors.As(err, &tooLarge):
+		return problem.New(http.StatusRequestEntityTooLarge, codec.CodeBodyTooLarge, codec.MsgBodyTooLarge)
+	default:
+		return problem.New(http.StatusInternalServerError, CodeInternalError, MsgInternalError)
+	}
+}
+
+// zipcodeRules maps the cep parsing errors to the rule reported in the error details
+var zipcodeRules = []struct {
+	err  error
+	rule string
+}{
+	{cep.ErrEmpty, codec.RuleRequired},
+	{cep.ErrFormat, codec.RuleFormat},
+	{cep.ErrInvalid, codec.RuleUnassigned},
+}
+
+// zipcodeDetails tells which rule a CEP rejected by the gateway broke; CEPs rejected by the
+// weather-engine carry no details
+func zipcodeDetails(err error) []model.ErrorDetail {
+	for _, r := range zipcodeRules {
+		if errors.Is(err, r.err) {
+			return []model.ErrorDetail{{Field: "cep", Code: r.rule, Message: r.err.Error()}}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/laboteldistributedtracing/cep"
+	cErrors "github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/client/error"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/codec"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/model"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/ratelimit"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/service"
+	"github.com/alexduzi/laboteldistributedtracing/platform/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// assertProblem checks that rec holds the problem of type code with detail, answered with its status
+func assertProblem(t *testing.T, rec *httptest.ResponseRecorder, code, detail string) model.Problem {
+	t.Helper()
+
+	assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+
+	var p model.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	assert.Equal(t, problem.TypeBase+code, p.Type)
+	assert.Equal(t, code, p.Code)
+	assert.Equal(t, http.StatusText(rec.Code), p.Title)
+	assert.Equal(t, rec.Code, p.Status, "o status do corpo deve ser o da resposta")
+	assert.Equal(t, detail, p.Detail)
+	return p
+}
+
+func TestProblemFor(t *testing.T) {
+	testCases := []struct {
+		name    string
+		err     error
+		status  int
+		code    string
+		detail  string
+		details []model.ErrorDetail
+	}{
+		{"CEP com formato inválido", fmt.Errorf("%w: %w", service.ErrInvalidZipcode, cep.ErrFormat), http.StatusUnprocessableEntity,
+			CodeInvalidZipcode, MsgInvalidZipcode, []model.ErrorDetail{{Field: "cep", Code: codec.RuleFormat, Message: cep.ErrFormat.Error()}}},
+		{"CEP fora das faixas", fmt.Errorf("%w: %w", service.ErrInvalidZipcode, cep.ErrInvalid), http.StatusUnprocessableEntity,
+			CodeInvalidZipcode, MsgInvalidZipcode, []model.ErrorDetail{{Field: "cep", Code: codec.RuleUnassigned, Message: cep.ErrInvalid.Error()}}},
+		{"CEP rejeitado pelo engine", cErrors.EngineClientInvalidZipcode, http.StatusUnprocessableEntity, CodeInvalidZipcode, MsgInvalidZipcode, nil},
+		{"CEP não encontrado", cErrors.EngineClientNotFound, http.StatusNotFound, CodeZipcodeNotFound, MsgZipcodeNotFound, nil},
+		{"Validação", codec.Invalid("ceps", codec.RuleMaxItems, "too many"), http.StatusUnprocessableEntity, codec.CodeValidationFailed,
+			codec.MsgValidationFailed, []model.ErrorDetail{{Field: "ceps", Code: codec.RuleMaxItems, Message: "too many"}}},
+		{"JSON malformado", &codec.MalformedError{Offset: 9, Reason: "unexpected end of JSON input"}, http.StatusUnprocessableEntity, codec.CodeMalformedBody,
+			codec.MsgMalformedBody, []model.ErrorDetail{{Code: codec.RuleSyntax, Message: "unexpected end of JSON input", Offset: 9}}},
+		{"Corpo grande demais", &http.MaxBytesError{Limit: 10}, http.StatusRequestEntityTooLarge, codec.CodeBodyTooLarge, codec.MsgBodyTooLarge, nil},
+		{"Tenant limitado pelo engine", &cErrors.ThrottledError{StatusCode: http.StatusTooManyRequests}, http.StatusTooManyRequests,
+			ratelimit.CodeRateLimited, ratelimit.MsgRateLimited, nil},
+		{"Cota do provedor esgotada", &cErrors.ThrottledError{StatusCode: http.StatusServiceUnavailable}, http.StatusServiceUnavailable,
+			CodeQuotaExceeded, MsgQuotaExceeded, nil},
+		{"Erro no weather-engine", cErrors.EngineClientInternalError, http.StatusInternalServerError, CodeInternalError, MsgInternalError, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			p := problemFor(tc.err)
+
+			// assert
+			assert.Equal(t, problem.New(tc.status, tc.code, tc.detail, tc.details...), p)
+		})
+	}
+}
+
+func TestWriteClientError_InstanceAndTraceID(t *testing.T) {
+	// arrange
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	router, svc := setupTemperatureRouter()
+	svc.On("GetTemperature", mock.Anything, "01310100").Return(nil, cErrors.EngineClientNotFound)
+
+	// act
+	rec := doRequest(router, http.MethodGet, "/api/v1/temperature/01310100", "")
+
+	// assert
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	p := assertProblem(t, rec, CodeZipcodeNotFound, MsgZipcodeNotFound)
+	assert.Equal(t, "/api/v1/temperature/01310100", p.Instance)
+
+	spans := recorder.Ended()
+	require.NotEmpty(t, spans)
+	assert.Equal(t, spans[0].SpanContext().TraceID().String(), p.TraceID, "o trace_id deve ser o do span do servidor")
+}
+
+func TestWriteClientError_RetryAfter(t *testing.T) {
+	// arrange
+	router, svc := setupTemperatureRouter()
+	svc.On("GetTemperature", mock.Anything, "01310100").
+		Return(nil, &cErrors.ThrottledError{StatusCode: http.StatusServiceUnavailable, RetryAfter: 90 * time.Second})
+
+	// act
+	rec := doRequest(router, http.MethodGet, "/api/v1/temperature/01310100", "")
+
+	// assert
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "90", rec.Header().Get("Retry-After"))
+	assertProblem(t, rec, CodeQuotaExceeded, MsgQuotaExceeded)
+}
+
+func TestWriteClientError_Legacy(t *testing.T) {
+	// arrange
+	gin.SetMode(gin.TestMode)
+	svc := service.NewTemperatureServiceStub()
+	svc.On("GetTemperature", mock.Anything, "01310100").Return(nil, cErrors.EngineClientNotFound)
+
+	router := NewRouter("cep-gateway-test", nil, nil, nil, true, false, Handlers{
+		Temperature: NewTemperatureHandler(&config.Config{BatchMaxSize: 3, MaxBodyBytes: 1 << 10}, svc),
+	})
+
+	// act
+	rec := doRequest(router, http.MethodGet, "/api/v1/temperature/01310100", "")
+
+	// assert
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"message":"can not find zipcode"}`, rec.Body.String())
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/model"
+	"github.com/alexduzi/laboteldistributedtracing/platform/health"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupHealthRouter(checks ...health.Check) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	checker := health.NewChecker(time.Minute, time.Second, checks...)
+	return NewRouter("cep-gateway-test", nil, nil, nil, false, false, Handlers{
+		Health: health.NewHandler("cep-gateway-test", checker),
+	})
+}
+
+func TestHealthLive(t *testing.T) {
+	// arrange
+	router := setupHealthRouter(health.Check{Name: "weather-engine", Run: func(context.Context) error {
+		return errors.New("down")
+	}})
+
+	// act
+	rec := doRequest(router, http.MethodGet, "/health/live", "")
+
+	// assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var body model.StatusResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, health.StatusHealthy, body.Status)
+	assert.Equal(t, "cep-gateway-test", body.Service)
+	assert.Empty(t, body.Checks)
+}
+
+func TestHealthReady(t *testing.T) {
+	up := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name           string
+		exporter       func(context.Context) error
+		expectedCode   int
+		expectedStatus string
+	}{
+		{"todas as dependências no ar", up, http.StatusOK, health.StatusHealthy},
+		{"uma dependência fora do ar", down, http.StatusServiceUnavailable, health.StatusUnhealthy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			router := setupHealthRouter(
+				health.Check{Name: "weather-engine", Run: up},
+				health.Check{Name: "exporter", Run: tt.exporter},
+			)
+
+			// act
+			rec := doRequest(router, http.MethodGet, "/health/ready", "")
+
+			// assert
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			var body model.StatusResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tt.expectedStatus, body.Status)
+			require.Len(t, body.Checks, 2)
+			assert.Equal(t, "weather-engine", body.Checks[0].Name)
+			assert.Equal(t, health.StatusUp, body.Checks[0].Status)
+			assert.Equal(t, "exporter", body.Checks[1].Name)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/auth"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/codec"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/docs"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/metrics"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/ratelimit"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/tenant"
+	"github.com/alexduzi/laboteldistributedtracing/platform/health"
+	"github.com/alexduzi/laboteldistributedtracing/platform/logging"
+	"github.com/alexduzi/laboteldistributedtracing/platform/problem"
+	"github.com/alexduzi/laboteldistributedtracing/platform/tracing"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// Handlers groups the HTTP handlers served by the cep-gateway
+type Handlers struct {
+	Temperature *TemperatureHandler
+	Stream      *StreamHandler
+	Health      *health.Handler
+	// Metrics serves the Prometheus exposition format; nil when metrics are pushed
+	Metrics http.Handler
+}
+
+// NewRouter registers the cep-gateway routes behind the OpenTelemetry, RED metrics and request logging middlewares.
+// API routes are also authenticated by authn and rate limited per client by limiter, which
+// also limits the failed authentications of each IP; either may be nil.
+// Apart from the event stream, they negotiate the response format from the Accept header.
+// Errors are rendered as problem details, or in the legacy shape when legacyErrors is set.
+// The X-Debug-Trace header forces sampling only when debugTraces is set.
+// The OpenAPI document and Swagger UI are served under docs.Path.
+func NewRouter(serviceName string, m *metrics.Metrics, limiter *ratelimit.Limiter, authn *auth.Authenticator, legacyErrors, debugTraces bool, handlers Handlers) *gin.Engine {
+	router := gin.New()
+	router.Use(gin.Recovery(), tracing.DebugSampling(debugTraces), otelgin.Middleware(serviceName), m.Middleware(), logging.Middleware(), problem.Middleware(legacyErrors))
+
+	if handlers.Metrics != nil {
+		router.GET("/metrics", gin.WrapH(handlers.Metrics))
+	}
+
+	if handlers.Health != nil {
+		router.GET("/health/live", handlers.Health.Live)
+		router.GET("/health/ready", handlers.Health.Ready)
+	}
+
+	docs.Register(router)
+
+	v1 := router.Group("/api/v1", ratelimit.FailedAuth(limiter), auth.Middleware(authn), ratelimit.Middleware(limiter), tenant.Middleware())
+	api := v1.Group("", codec.Middleware())
+	api.GET("/temperature/:cep", handlers.Temperature.GetTemperature)
+	api.POST("/temperature\\:batch", handlers.Temperature.BatchGetTemperature)
+	v1.GET("/temperature/:cep/stream", handlers.Stream.StreamTemperature)
+
+	return router
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/docs"
+	"github.com/alexduzi/laboteldistributedtracing/platform/health"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+var ginParam = regexp.MustCompile(`/:(\w+)`)
+
+// openAPIPath rewrites a gin route path in the OpenAPI form: /:cep becomes /{cep} and the
+// escaped colon of custom methods is unescaped
+func openAPIPath(path string) string {
+	return strings.ReplaceAll(ginParam.ReplaceAllString(path, "/{$1}"), `\:`, ":")
+}
+
+func TestNewRouter_MatchesOpenAPISpec(t *testing.T) {
+	// arrange
+	gin.SetMode(gin.TestMode)
+	router := NewRouter("cep-gateway-test", nil, nil, nil, false, false, Handlers{
+		Temperature: &TemperatureHandler{},
+		Stream:      &StreamHandler{},
+		Health:      &health.Handler{},
+		Metrics:     http.NotFoundHandler(),
+	})
+
+	var spec struct {
+		Paths map[string]map[string]any `yaml:"paths"`
+	}
+	require.NoError(t, yaml.Unmarshal(docs.Spec(), &spec))
+
+	// act
+	var served []string
+	for _, route := range router.Routes() {
+		if strings.HasPrefix(route.Path, docs.Path) {
+			continue
+		}
+		served = append(served, route.Method+" "+openAPIPath(route.Path))
+	}
+
+	var documented []string
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	// assert
+	assert.ElementsMatch(t, served, documented, "rotas registradas e documentadas devem ser as mesmas")
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/alexduzi/laboteldistributedtracing/cep"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/service"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/stream"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/handler"
+
+type StreamHandler struct {
+	hub      *stream.Hub
+	closing  chan struct{}
+	shutdown sync.Once
+}
+
+func NewStreamHandler(hub *stream.Hub) *StreamHandler {
+	return &StreamHandler{hub: hub, closing: make(chan struct{})}
+}
+
+// Shutdown ends every open stream so a draining server is not held up by long-lived connections
+func (h *StreamHandler) Shutdown(context.Context) error {
+	h.shutdown.Do(func() { close(h.closing) })
+	return nil
+}
+
+// StreamTemperature serves a Server-Sent Events stream that emits a "temperature" event
+// whenever the weather data changes
+func (h *StreamHandler) StreamTemperature(c *gin.Context) {
+	code, err := cep.Parse(c.Param("cep"))
+	if err != nil {
+		writeClientError(c, fmt.Errorf("%w: %w", service.ErrInvalidZipcode, err))
+		return
+	}
+
+	events, unsubscribe := h.hub.Subscribe(code.String())
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	ctx := c.Request.Context()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-h.closing:
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			h.emit(c, event)
+			return true
+		}
+	})
+}
+
+// emit writes the event inside a span linked to the poll that produced it
+func (h *StreamHandler) emit(c *gin.Context, event stream.Event) {
+	_, span := otel.Tracer(tracerName).Start(c.Request.Context(), "emit temperature event",
+		trace.WithLinks(trace.Link{SpanContext: event.SpanContext}),
+		trace.WithAttributes(
+			attribute.String("cep", event.Cep),
+			attribute.Int64("stream.event_id", int64(event.ID)),
+		))
+	defer span.End()
+
+	c.Render(-1, sse.Event{
+		Id:    strconv.FormatUint(event.ID, 10),
+		Event: "temperature",
+		Data:  event.Temperature,
+	})
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/laboteldistributedtracing/cep"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/codec"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/model"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/service"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/stream"
+	"github.com/alexduzi/laboteldistributedtracing/platform/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setupStreamServer(t *testing.T) (*httptest.Server, *stream.Hub, *service.TemperatureServiceStub) {
+	gin.SetMode(gin.TestMode)
+
+	svc := service.NewTemperatureServiceStub()
+	hub := stream.NewHub(svc, time.Hour)
+
+	router := NewRouter("cep-gateway-test", nil, nil, nil, false, false, Handlers{
+		Stream: NewStreamHandler(hub),
+	})
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server, hub, svc
+}
+
+func TestStreamTemperature_EmitsEventWithLinkToPoll(t *testing.T) {
+	// arrange
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	server, hub, svc := setupStreamServer(t)
+	svc.On("GetTemperature", mock.Anything, "01310100").
+		Return(&model.TemperatureResponse{Celsius: 28.5, Fahrenheit: 83.3, Kelvin: 301.65}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/temperature/01310-100/stream", nil)
+	require.NoError(t, err)
+
+	// act
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() && scanner.Text() != "" {
+		lines = append(lines, scanner.Text())
+	}
+
+	// assert
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(t, []string{
+		"id:1",
+		"event:temperature",
+		`data:{"temp_C":28.5,"temp_F":83.3,"temp_K":301.65}`,
+	}, lines)
+
+	// cliente desconecta: o poller compartilhado deve ser encerrado
+	cancel()
+	assert.Eventually(t, func() bool { return hub.Pollers() == 0 }, time.Second, 5*time.Millisecond)
+
+	var poll, emit sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		switch span.Name() {
+		case "poll temperature":
+			poll = span
+		case "emit temperature event":
+			emit = span
+		}
+	}
+	require.NotNil(t, poll)
+	require.NotNil(t, emit)
+	require.Len(t, emit.Links(), 1)
+	assert.Equal(t, poll.SpanContext().SpanID(), emit.Links()[0].SpanContext.SpanID())
+	assert.Equal(t, poll.SpanContext().TraceID(), emit.Links()[0].SpanContext.TraceID())
+}
+
+func TestStreamTemperature_InvalidCep(t *testing.T) {
+	// arrange
+	server, hub, _ := setupStreamServer(t)
+
+	// act
+	resp, err := http.Get(server.URL + "/api/v1/temperature/abc/stream")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var body model.Problem
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+	// assert
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Equal(t, problem.ContentType, resp.Header.Get("Content-Type"))
+	assert.Equal(t, problem.TypeBase+CodeInvalidZipcode, body.Type)
+	assert.Equal(t, "/api/v1/temperature/abc/stream", body.Instance)
+	assert.Equal(t, []model.ErrorDetail{{Field: "cep", Code: codec.RuleFormat, Message: cep.ErrFormat.Error()}}, body.Details)
+	assert.Equal(t, 0, hub.Pollers())
+}
+
+func TestStreamTemperature_ShutdownEndsStream(t *testing.T) {
+	// arrange
+	gin.SetMode(gin.TestMode)
+
+	svc := service.NewTemperatureServiceStub()
+	svc.On("GetTemperature", mock.Anything, "01310100").
+		Return(&model.TemperatureResponse{Celsius: 28.5, Fahrenheit: 83.3, Kelvin: 301.65}, nil)
+	hub := stream.NewHub(svc, time.Hour)
+	streamHandler := NewStreamHandler(hub)
+
+	server := httptest.NewServer(NewRouter("cep-gateway-test", nil, nil, nil, false, false, Handlers{Stream: streamHandler}))
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL + "/api/v1/temperature/01310100/stream")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// act
+	require.NoError(t, streamHandler.Shutdown(context.Background()))
+
+	// assert
+	ended := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		close(ended)
+	}()
+	select {
+	case <-ended:
+	case <-time.After(time.Second):
+		t.Fatal("stream deveria ter sido encerrado")
+	}
+	assert.Eventually(t, func() bool { return hub.Pollers() == 0 }, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/codec"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/model"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const MsgInvalidBatchRequest = "request body must contain a non-empty ceps list"
+
+type TemperatureHandler struct {
+	config  *config.Config
+	service service.TemperatureServiceInterface
+}
+
+func NewTemperatureHandler(cfg *config.Config, temperatureService service.TemperatureServiceInterface) *TemperatureHandler {
+	return &TemperatureHandler{
+		config:  cfg,
+		service: temperatureService,
+	}
+}
+
+// GetTemperature validates the CEP and returns the current temperature in Celsius, Fahrenheit
+// and Kelvin
+func (h *TemperatureHandler) GetTemperature(c *gin.Context) {
+	temperature, err := h.service.GetTemperature(c.Request.Context(), c.Param("cep"))
+	if err != nil {
+		writeClientError(c, err)
+		return
+	}
+
+	codec.Render(c, http.StatusOK, temperature)
+}
+
+// BatchGetTemperature deduplicates the CEPs, looks them up in one weather-engine call and
+// returns per-item results in input order
+func (h *TemperatureHandler) BatchGetTemperature(c *gin.Context) {
+	var req model.BatchTemperatureRequest
+	if err := codec.DecodeJSON(c, h.config.MaxBodyBytes, &req); err != nil {
+		writeClientError(c, err)
+		return
+	}
+	if len(req.Ceps) == 0 {
+		writeClientError(c, codec.Invalid("ceps", codec.RuleRequired, MsgInvalidBatchRequest))
+		return
+	}
+	if len(req.Ceps) > h.config.BatchMaxSize {
+		writeClientError(c, codec.Invalid("ceps", codec.RuleMaxItems, fmt.Sprintf("batch must not exceed %d ceps", h.config.BatchMaxSize)))
+		return
+	}
+
+	results := h.service.GetTemperatures(c.Request.Context(), req.Ceps)
+
+	response := model.BatchTemperatureResponse{Results: make([]model.BatchTemperatureItem, 0, len(results))}
+	for _, res := range results {
+		item := model.BatchTemperatureItem{Cep: res.Cep, Status: http.StatusOK, Temperature: res.Temperature}
+		if res.Err != nil {
+			p := problemFor(res.Err)
+			item.Status = p.Status
+			item.Error = &model.ErrorResponse{Code: p.Code, Message: p.Detail, Details: p.Details}
+		}
+		response.Results = append(response.Results, item)
+	}
+
+	codec.Render(c, http.StatusOK, response)
+}
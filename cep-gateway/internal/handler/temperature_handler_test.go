@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cErrors "github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/client/error"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/codec"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/model"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTemperatureRouter() (*gin.Engine, *service.TemperatureServiceStub) {
+	gin.SetMode(gin.TestMode)
+
+	svc := service.NewTemperatureServiceStub()
+	cfg := &config.Config{BatchMaxSize: 3, MaxBodyBytes: 1 << 10}
+
+	router := NewRouter("cep-gateway-test", nil, nil, nil, false, false, Handlers{
+		Temperature: NewTemperatureHandler(cfg, svc),
+	})
+	return router, svc
+}
+
+func doRequest(router http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequestWithContext(context.Background(), method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGetTemperature_Success(t *testing.T) {
+	// arrange
+	router, svc := setupTemperatureRouter()
+
+	svc.On("GetTemperature", mock.Anything, "01310-100").
+		Return(&model.TemperatureResponse{Celsius: 28.5, Fahrenheit: 83.3, Kelvin: 301.65}, nil)
+
+	// act
+	rec := doRequest(router, http.MethodGet, "/api/v1/temperature/01310-100", "")
+
+	// assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"temp_C":28.5,"temp_F":83.3,"temp_K":301.65}`, rec.Body.String())
+}
+
+func TestGetTemperature_Errors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected int
+		code     string
+		message  string
+	}{
+		{"CEP inválido", service.ErrInvalidZipcode, http.StatusUnprocessableEntity, CodeInvalidZipcode, MsgInvalidZipcode},
+		{"CEP não encontrado", cErrors.EngineClientNotFound, http.StatusNotFound, CodeZipcodeNotFound, MsgZipcodeNotFound},
+		{"Erro no weather-engine", cErrors.EngineClientInternalError, http.StatusInternalServerError, CodeInternalError, MsgInternalError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			router, svc := setupTemperatureRouter()
+			svc.On("GetTemperature", mock.Anything, "01310100").Return(nil, tc.err)
+
+			// act
+			rec := doRequest(router, http.MethodGet, "/api/v1/temperature/01310100", "")
+
+			// assert
+			assert.Equal(t, tc.expected, rec.Code)
+			assertProblem(t, rec, tc.code, tc.message)
+		})
+	}
+}
+
+func TestBatchGetTemperature_PerItemResults(t *testing.T) {
+	// arrange
+	router, svc := setupTemperatureRouter()
+
+	svc.On("GetTemperatures", mock.Anything, []string{"01310-100", "99999999"}).Return([]service.TemperatureResult{
+		{Cep: "01310100", Temperature: &model.TemperatureResponse{Celsius: 28.5, Fahrenheit: 83.3, Kelvin: 301.65}},
+		{Cep: "99999999", Err: cErrors.EngineClientNotFound},
+	})
+
+	// act
+	rec := doRequest(router, http.MethodPost, "/api/v1/temperature:batch", `{"ceps":["01310-100","99999999"]}`)
+
+	// assert
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var body model.BatchTemperatureResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.Results, 2)
+	assert.Equal(t, http.StatusOK, body.Results[0].Status)
+	assert.Equal(t, 28.5, body.Results[0].Temperature.Celsius)
+	assert.Equal(t, http.StatusNotFound, body.Results[1].Status)
+	assert.Equal(t, MsgZipcodeNotFound, body.Results[1].Error.Message)
+}
+
+func TestBatchGetTemperature_InvalidRequest(t *testing.T) {
+	testCases := []struct {
+		name     string
+		body     string
+		expected int
+		details  []model.ErrorDetail
+	}{
+		{"Corpo vazio", ``, http.StatusUnprocessableEntity,
+			[]model.ErrorDetail{{Field: "", Code: codec.RuleRequired, Message: "request body is required"}}},
+		{"Lista vazia", `{"ceps":[]}`, http.StatusUnprocessableEntity,
+			[]model.ErrorDetail{{Field: "ceps", Code: codec.RuleRequired, Message: MsgInvalidBatchRequest}}},
+		{"Acima do limite", `{"ceps":["01310100","20040002","30130010","40010000"]}`, http.StatusUnprocessableEntity,
+			[]model.ErrorDetail{{Field: "ceps", Code: codec.RuleMaxItems, Message: "batch must not exceed 3 ceps"}}},
+		{"Campo desconhecido", `{"ceps":["01310100"],"cep":"01310100"}`, http.StatusUnprocessableEntity,
+			[]model.ErrorDetail{{Field: "cep", Code: codec.RuleUnknownField, Message: "is not a known field"}}},
+		{"Tipo inválido", `{"ceps":"01310100"}`, http.StatusUnprocessableEntity,
+			[]model.ErrorDetail{{Field: "ceps", Code: codec.RuleInvalidType, Message: "must not be a JSON string"}}},
+		{"JSON truncado", `{"ceps":[`, http.StatusUnprocessableEntity,
+			[]model.ErrorDetail{{Field: "", Code: codec.RuleSyntax, Message: "unexpected end of JSON input", Offset: 9}}},
+		{"Sintaxe inválida", `{"ceps":}`, http.StatusUnprocessableEntity,
+			[]model.ErrorDetail{{Field: "", Code: codec.RuleSyntax, Message: "invalid character '}' looking for beginning of value", Offset: 9}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			router, svc := setupTemperatureRouter()
+
+			// act
+			rec := doRequest(router, http.MethodPost, "/api/v1/temperature:batch", tc.body)
+
+			// assert
+			assert.Equal(t, tc.expected, rec.Code)
+
+			var body model.Problem
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tc.details, body.Details)
+			svc.AssertNotCalled(t, "GetTemperatures", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestBatchGetTemperature_BodyTooLarge(t *testing.T) {
+	// arrange
+	router, svc := setupTemperatureRouter()
+	body := `{"ceps":["` + strings.Repeat("0", 2048) + `"]}`
+
+	// act
+	rec := doRequest(router, http.MethodPost, "/api/v1/temperature:batch", body)
+
+	// assert
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assertProblem(t, rec, codec.CodeBodyTooLarge, codec.MsgBodyTooLarge)
+	svc.AssertNotCalled(t, "GetTemperatures", mock.Anything, mock.Anything)
+}
+
+func TestBatchGetTemperature_UnsupportedMediaType(t *testing.T) {
+	// arrange
+	router, svc := setupTemperatureRouter()
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/api/v1/temperature:batch",
+		strings.NewReader(`{"ceps":["01310100"]}`))
+	req.Header.Set("Content-Type", "text/plain")
+	rec := httptest.NewRecorder()
+
+	// act
+	router.ServeHTTP(rec, req)
+
+	// assert
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	assertProblem(t, rec, codec.CodeUnsupportedMediaType, codec.MsgUnsupportedMediaType)
+	svc.AssertNotCalled(t, "GetTemperatures", mock.Anything, mock.Anything)
+}
+
+func TestGetTemperature_NegotiatesFormat(t *testing.T) {
+	testCases := []struct {
+		name        string
+		accept      string
+		contentType string
+		body        string
+	}{
+		{"JSON por padrão", "", "application/json", `{"temp_C":28.5,"temp_F":83.3,"temp_K":301.65}`},
+		{"XML", "application/xml", "application/xml",
+			`<TemperatureResponse><temp_C>28.5</temp_C><temp_F>83.3</temp_F><temp_K>301.65</temp_K></TemperatureResponse>`},
+		{"Texto", "text/plain", "text/plain", "temp_C=28.5\ntemp_F=83.3\ntemp_K=301.65\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			router, svc := setupTemperatureRouter()
+			svc.On("GetTemperature", mock.Anything, "01310100").
+				Return(&model.TemperatureResponse{Celsius: 28.5, Fahrenheit: 83.3, Kelvin: 301.65}, nil)
+
+			req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/v1/temperature/01310100", nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			rec := httptest.NewRecorder()
+
+			// act
+			router.ServeHTTP(rec, req)
+
+			// assert
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Header().Get("Content-Type"), tc.contentType)
+			assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+			assert.Equal(t, tc.body, rec.Body.String())
+		})
+	}
+}
+
+func TestGetTemperature_NotAcceptable(t *testing.T) {
+	// arrange
+	router, svc := setupTemperatureRouter()
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/v1/temperature/01310100", nil)
+	req.Header.Set("Accept", "image/png")
+	rec := httptest.NewRecorder()
+
+	// act
+	router.ServeHTTP(rec, req)
+
+	// assert
+	assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+	assertProblem(t, rec, codec.CodeNotAcceptable, codec.MsgNotAcceptable)
+	svc.AssertNotCalled(t, "GetTemperature", mock.Anything, mock.Anything)
+}
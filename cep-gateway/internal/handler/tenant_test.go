@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/auth"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/client"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/model"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/service"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/tenant"
+	"github.com/alexduzi/laboteldistributedtracing/platform/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func setupBaggagePropagator(t *testing.T) {
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+}
+
+// setupTenantGateway wires the gateway against a stand-in weather-engine that records
+// the tenant it extracted from the incoming baggage
+func setupTenantGateway(t *testing.T, authn *auth.Authenticator) (*gin.Engine, *[]string) {
+	gin.SetMode(gin.TestMode)
+
+	var received []string
+	engine := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		received = append(received, baggage.FromContext(ctx).Member(tenant.BaggageKey).Value())
+		_ = json.NewEncoder(w).Encode(model.TemperatureResponse{Celsius: 20, Fahrenheit: 68, Kelvin: 293})
+	}))
+	t.Cleanup(engine.Close)
+
+	cfg := &config.Config{WeatherEngineURL: engine.URL, BatchMaxSize: 3}
+	svc := service.NewTemperatureService(cfg, client.NewWeatherEngineClient(cfg))
+
+	router := NewRouter("cep-gateway-test", nil, nil, authn, false, false, Handlers{
+		Temperature: NewTemperatureHandler(cfg, svc),
+	})
+	return router, &received
+}
+
+func doTenantRequest(router http.Handler, tenantID string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/v1/temperature/01310100", nil)
+	if tenantID != "" {
+		req.Header.Set(tenant.Header, tenantID)
+	}
+	for name, values := range header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestTenantMiddleware_PropagatesTenantToEngine(t *testing.T) {
+	// arrange
+	setupBaggagePropagator(t)
+	router, received := setupTenantGateway(t, nil)
+
+	// act
+	rec := doTenantRequest(router, "team-a", nil)
+
+	// assert
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []string{"team-a"}, *received)
+}
+
+func TestTenantMiddleware_WithoutHeaderSendsAnonymous(t *testing.T) {
+	// arrange
+	setupBaggagePropagator(t)
+	router, received := setupTenantGateway(t, nil)
+
+	// act
+	rec := doTenantRequest(router, "", nil)
+
+	// assert
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []string{tenant.Anonymous}, *received)
+}
+
+func TestTenantMiddleware_ReplacesCallerBaggage(t *testing.T) {
+	tests := []struct {
+		name     string
+		tenantID string
+		expected string
+	}{
+		{"sem cabeçalho", "", tenant.Anonymous},
+		{"com cabeçalho", "team-a", "team-a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			setupBaggagePropagator(t)
+			router, received := setupTenantGateway(t, nil)
+
+			// act
+			rec := doTenantRequest(router, tt.tenantID, http.Header{"Baggage": {tenant.BaggageKey + "=team-b"}})
+
+			// assert
+			require.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, []string{tt.expected}, *received, "a baggage enviada pelo cliente não pode escolher o tenant")
+		})
+	}
+}
+
+func TestTenantMiddleware_AuthenticatedCallerIsItsTenant(t *testing.T) {
+	// arrange
+	setupBaggagePropagator(t)
+	keys, err := auth.Load(`[{"name": "mobile-app", "key": "mobile-secret"}]`, "")
+	require.NoError(t, err)
+	router, received := setupTenantGateway(t, &auth.Authenticator{Keys: keys})
+
+	// act
+	rec := doTenantRequest(router, "team-b", http.Header{
+		auth.HeaderAPIKey: {"mobile-secret"},
+		"Baggage":         {tenant.BaggageKey + "=team-c"},
+	})
+
+	// assert
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []string{"mobile-app"}, *received, "o tenant deve ser a identidade autenticada")
+}
+
+func TestTenantMiddleware_RejectsInvalidTenant(t *testing.T) {
+	tests := []struct {
+		name     string
+		tenantID string
+	}{
+		{"com espaço", "team a"},
+		{"com vírgula", "team,a"},
+		{"longo demais", strings.Repeat("a", 65)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			setupBaggagePropagator(t)
+			router, received := setupTenantGateway(t, nil)
+
+			// act
+			rec := doTenantRequest(router, tt.tenantID, nil)
+
+			// assert
+			assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+			var body model.Problem
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, problem.TypeBase+tenant.CodeInvalidTenant, body.Type)
+			assert.Equal(t, tenant.CodeInvalidTenant, body.Code)
+			require.Len(t, body.Details, 1)
+			assert.Equal(t, tenant.Header, body.Details[0].Field, "o detalhe deve apontar o cabeçalho inválido")
+			assert.Empty(t, *received)
+		})
+	}
+}
+
+func TestTenantFromContext(t *testing.T) {
+	// arrange
+	ctx, err := tenant.WithTenant(context.Background(), "team-b")
+	require.NoError(t, err)
+
+	// act & assert
+	assert.Equal(t, "team-b", tenant.FromContext(ctx))
+	assert.Equal(t, tenant.Anonymous, tenant.FromContext(context.Background()))
+}
@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/alexduzi/laboteldistributedtracing/platform/httpmetrics"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const MeterName = "github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/metrics"
+
+// Metrics holds the instruments recorded by the cep-gateway.
+// A nil *Metrics is valid and records nothing.
+type Metrics struct {
+	http        *httpmetrics.Instruments
+	rateLimited metric.Int64Counter
+}
+
+// New creates every instrument from the given meter
+func New(meter metric.Meter) (*Metrics, error) {
+	var m Metrics
+	var err error
+
+	if m.http, err = httpmetrics.New(meter); err != nil {
+		return nil, err
+	}
+	if m.rateLimited, err = meter.Int64Counter("app.ratelimit.rejections",
+		metric.WithDescription("Number of HTTP requests rejected by the per-client rate limiter"),
+		metric.WithUnit("{request}")); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Middleware records request count, error count and duration per route, method and status
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	if m == nil {
+		return (*httpmetrics.Instruments)(nil).Middleware()
+	}
+	return m.http.Middleware()
+}
+
+// RecordRateLimited records a request rejected by the rate limiter, by route and kind of client key
+func (m *Metrics) RecordRateLimited(ctx context.Context, route, clientType string) {
+	if m == nil {
+		return
+	}
+
+	m.rateLimited.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("http.route", route),
+		attribute.String("ratelimit.client_type", clientType),
+	))
+}
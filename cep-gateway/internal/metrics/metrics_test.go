@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func setupMetrics(t *testing.T) (*Metrics, *sdkmetric.ManualReader) {
+	t.Helper()
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	m, err := New(provider.Meter(MeterName))
+	require.NoError(t, err)
+
+	return m, reader
+}
+
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Metrics {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	result := make(map[string]metricdata.Metrics)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			result[m.Name] = m
+		}
+	}
+	return result
+}
+
+func attributeValue(set attribute.Set, key string) string {
+	value, _ := set.Value(attribute.Key(key))
+	return value.Emit()
+}
+
+func TestNilMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// arrange
+	var m *Metrics
+	router := gin.New()
+	router.Use(m.Middleware())
+	router.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+	w := httptest.NewRecorder()
+
+	// act & assert
+	assert.NotPanics(t, func() {
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ok", nil))
+		m.RecordRateLimited(context.Background(), "/ok", "ip")
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"github.com/alexduzi/laboteldistributedtracing/platform/health"
+	"github.com/alexduzi/laboteldistributedtracing/platform/problem"
+)
+
+// TemperatureResponse represents temperature in different units
+type TemperatureResponse struct {
+	Celsius    float64 `json:"temp_C" xml:"temp_C" example:"28.5"`
+	Fahrenheit float64 `json:"temp_F" xml:"temp_F" example:"83.3"`
+	Kelvin     float64 `json:"temp_K" xml:"temp_K" example:"301.65"`
+	// Degraded is set by the weather-engine when the CEP providers were down and the
+	// temperature is that of the city resolved offline from the CEP range
+	Degraded   bool   `json:"degraded,omitempty" xml:"degraded,omitempty" example:"true"`
+	Resolution string `json:"resolution,omitempty" xml:"resolution,omitempty" example:"prefix"`
+}
+
+// BatchTemperatureRequest represents a request for the temperature of several CEPs
+type BatchTemperatureRequest struct {
+	Ceps []string `json:"ceps" binding:"required" example:"01310-100,20040-002"`
+}
+
+// BatchTemperatureResponse represents the per-CEP results of a batch request, in input order
+type BatchTemperatureResponse struct {
+	Results []BatchTemperatureItem `json:"results" xml:"results>result"`
+}
+
+// BatchTemperatureItem represents the outcome of a single CEP in a batch request
+type BatchTemperatureItem struct {
+	Cep         string               `json:"cep" xml:"cep" example:"01310-100"`
+	Status      int                  `json:"status" xml:"status" example:"200"`
+	Temperature *TemperatureResponse `json:"temperature,omitempty" xml:"temperature,omitempty"`
+	Error       *ErrorResponse       `json:"error,omitempty" xml:"error,omitempty"`
+}
+
+// StatusResponse represents the health/readiness status response
+type StatusResponse = health.StatusResponse
+
+// DependencyStatus represents the outcome of one readiness check
+type DependencyStatus = health.DependencyStatus
+
+// ErrorResponse represents an error in a batch item. Code identifies the error for clients
+// to branch on, while Message may change; Details lists what failed validation.
+type ErrorResponse struct {
+	Code    string        `json:"code" xml:"code" example:"invalid_zipcode"`
+	Message string        `json:"message" xml:"message" example:"invalid zipcode"`
+	Details []ErrorDetail `json:"details,omitempty" xml:"details>detail,omitempty"`
+}
+
+// Problem represents an RFC 7807 problem details error response
+type Problem = problem.Problem
+
+// ErrorDetail represents one validation failure of a request
+type ErrorDetail = problem.ErrorDetail
@@ -0,0 +1,239 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/codec"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/metrics"
+	"github.com/alexduzi/laboteldistributedtracing/platform/problem"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	CodeRateLimited = "rate_limited"
+	MsgRateLimited  = "rate limit exceeded"
+)
+
+// Kinds of client key, recorded on spans and metrics
+const (
+	ClientAPIKey = "api_key"
+	ClientIP     = "ip"
+)
+
+// globalScope names the bucket shared by the routes without a limit of their own
+const globalScope = "*"
+
+// failedAuthScope names the bucket counting the failed authentications of a client IP
+const failedAuthScope = "auth"
+
+// Limiter applies a token bucket per client, and per route for routes with their own limit.
+// A nil *Limiter is valid and allows every request.
+type Limiter struct {
+	store      Store
+	limit      Limit
+	routes     map[string]Limit
+	failedAuth Limit
+	metrics    *metrics.Metrics
+}
+
+// New limits every client to limit across all routes, except that the routes in
+// routes, keyed by their gin path, get a separate bucket with their own limit, and
+// clients authenticated with a limit of their own (see WithClient) use it instead of limit.
+// Requests that no limit applies to pass through untouched. failedAuth limits the failed
+// authentications of each client IP (see FailedAuth).
+func New(store Store, limit Limit, routes map[string]Limit, failedAuth Limit, m *metrics.Metrics) *Limiter {
+	return &Limiter{
+		store:      store,
+		limit:      limit,
+		routes:     routes,
+		failedAuth: failedAuth,
+		metrics:    m,
+	}
+}
+
+type clientContextKey struct{}
+
+// client is a caller identified upstream of the limiter, by authentication
+type client struct {
+	name  string
+	limit Limit
+}
+
+// WithClient returns a copy of ctx identifying the caller by name, so its bucket is
+// shared however it authenticates. A limit with a positive rate replaces the default limit.
+func WithClient(ctx context.Context, name string, limit Limit) context.Context {
+	return context.WithValue(ctx, clientContextKey{}, client{name: name, limit: limit})
+}
+
+// ParseRoutes reads per-route limits written as "route=rate:burst", separated by commas,
+// e.g. "/api/v1/temperature:batch=1:5"
+func ParseRoutes(spec string) (map[string]Limit, error) {
+	routes := make(map[string]Limit)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		i := strings.LastIndex(entry, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid route limit %q: want route=rate:burst", entry)
+		}
+		route, value := entry[:i], entry[i+1:]
+
+		limit, err := ParseLimit(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid route limit %q: %w", entry, err)
+		}
+		routes[route] = limit
+	}
+	return routes, nil
+}
+
+// ParseLimit reads a limit written as "rate:burst", e.g. "0.5:5"
+func ParseLimit(value string) (Limit, error) {
+	rawRate, rawBurst, ok := strings.Cut(value, ":")
+	if !ok {
+		return Limit{}, errors.New("want rate:burst")
+	}
+	rate, err := strconv.ParseFloat(rawRate, 64)
+	if err != nil || rate <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate %q", rawRate)
+	}
+	burst, err := strconv.Atoi(rawBurst)
+	if err != nil || burst < 1 {
+		return Limit{}, fmt.Errorf("invalid burst %q", rawBurst)
+	}
+	return Limit{Rate: rate, Burst: burst}, nil
+}
+
+// limitFor returns the limit that applies to route for the client and the scope of its bucket
+func (l *Limiter) limitFor(route string, limit Limit) (Limit, string, bool) {
+	if routeLimit, ok := l.routes[route]; ok {
+		return routeLimit, route, true
+	}
+	if limit.Rate <= 0 {
+		limit = l.limit
+	}
+	if limit.Rate > 0 {
+		return Limit{Rate: limit.Rate, Burst: max(limit.Burst, 1)}, globalScope, true
+	}
+	return Limit{}, "", false
+}
+
+// Middleware answers 429 once the client has used up its bucket. Every limited
+// response carries X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset
+// (seconds until the bucket is full), and rejections add Retry-After.
+// When the store fails the request is let through.
+func Middleware(l *Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if l == nil {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		route := c.FullPath()
+		key, clientType, clientLimit := clientKey(c)
+		limit, scope, ok := l.limitFor(route, clientLimit)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		span := trace.SpanFromContext(ctx)
+		res, err := l.store.Take(ctx, scope+"|"+key, limit)
+		if err != nil {
+			span.RecordError(err)
+			slog.WarnContext(ctx, "Rate limit store unavailable, allowing request", "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit.Burst))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+
+		if !res.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+			span.AddEvent("rate limited", trace.WithAttributes(
+				attribute.String("http.route", route),
+				attribute.String("ratelimit.client_type", clientType),
+				attribute.Int64("ratelimit.retry_after_ms", res.RetryAfter.Milliseconds()),
+			))
+			l.metrics.RecordRateLimited(ctx, route, clientType)
+			codec.Abort(c, problem.New(http.StatusTooManyRequests, CodeRateLimited, MsgRateLimited))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// FailedAuth goes in front of authentication and limits the failed attempts of each client
+// IP: every 401 answered downstream takes a token from the IP's bucket, and once the bucket
+// is used up the IP gets 429, with Retry-After, before its credentials are checked.
+// Successful requests are left to Middleware. When the store fails the request is let through.
+func FailedAuth(l *Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if l == nil || l.failedAuth.Rate <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		route := c.FullPath()
+		key := failedAuthScope + "|" + ClientIP + ":" + c.ClientIP()
+		limit := Limit{Rate: l.failedAuth.Rate, Burst: max(l.failedAuth.Burst, 1)}
+
+		res, err := l.store.Peek(ctx, key, limit)
+		if err != nil {
+			trace.SpanFromContext(ctx).RecordError(err)
+			slog.WarnContext(ctx, "Rate limit store unavailable, allowing request", "error", err)
+			c.Next()
+			return
+		}
+		if !res.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+			trace.SpanFromContext(ctx).AddEvent("authentication rate limited", trace.WithAttributes(
+				attribute.String("http.route", route),
+				attribute.Int64("ratelimit.retry_after_ms", res.RetryAfter.Milliseconds()),
+			))
+			l.metrics.RecordRateLimited(ctx, route, ClientIP)
+			codec.Abort(c, problem.New(http.StatusTooManyRequests, CodeRateLimited, MsgRateLimited))
+			return
+		}
+
+		c.Next()
+
+		if c.Writer.Status() == http.StatusUnauthorized {
+			if _, err := l.store.Take(ctx, key, limit); err != nil {
+				slog.WarnContext(ctx, "Rate limit store unavailable, failed authentication not counted", "error", err)
+			}
+		}
+	}
+}
+
+// clientKey identifies the caller by the name authentication gave to WithClient, or else
+// by IP, and returns the client's own limit if any. Credentials that were not validated
+// are never used, as a caller could then get a fresh bucket by sending a new one.
+func clientKey(c *gin.Context) (string, string, Limit) {
+	if cl, ok := c.Request.Context().Value(clientContextKey{}).(client); ok {
+		return ClientAPIKey + ":" + cl.name, ClientAPIKey, cl.limit
+	}
+	return ClientIP + ":" + c.ClientIP(), ClientIP, Limit{}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
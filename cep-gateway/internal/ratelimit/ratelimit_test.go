@@ -0,0 +1,326 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/metrics"
+	"github.com/alexduzi/laboteldistributedtracing/platform/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, Limit) (Result, error) {
+	return Result{}, errors.New("connection refused")
+}
+
+func (failingStore) Peek(context.Context, string, Limit) (Result, error) {
+	return Result{}, errors.New("connection refused")
+}
+
+// setupRouter serves the limited routes inside a recorded server span, as otelgin would
+func setupRouter(t *testing.T, store Store, limit Limit, routes map[string]Limit) (*gin.Engine, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	reader := sdkmetric.NewManualReader()
+	m, err := metrics.New(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter(metrics.MeterName))
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		ctx, span := tracer.Start(c.Request.Context(), c.FullPath())
+		defer span.End()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	})
+	v1 := router.Group("/api/v1", Middleware(New(store, limit, routes, Limit{}, m)))
+	v1.GET("/temperature/:cep", func(c *gin.Context) { c.Status(http.StatusOK) })
+	v1.POST("/temperature\\:batch", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	return router, recorder, reader
+}
+
+// doRequest sends the request from clientIP, or from 203.0.113.7 when it is empty
+func doRequest(router http.Handler, method, target, clientIP string) *httptest.ResponseRecorder {
+	req := httptest.NewRequestWithContext(context.Background(), method, target, nil)
+	if clientIP == "" {
+		clientIP = "203.0.113.7"
+	}
+	req.RemoteAddr = clientIP + ":51000"
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware_RejectsWhenBucketIsEmpty(t *testing.T) {
+	// arrange
+	router, recorder, reader := setupRouter(t, NewMemoryStore(), Limit{Rate: 1, Burst: 2}, nil)
+
+	// act
+	first := doRequest(router, http.MethodGet, "/api/v1/temperature/01310100", "")
+	second := doRequest(router, http.MethodGet, "/api/v1/temperature/01310100", "")
+	rejected := doRequest(router, http.MethodGet, "/api/v1/temperature/01310100", "")
+
+	// assert
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "2", first.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", first.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "1", first.Header().Get("X-RateLimit-Reset"))
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, "0", second.Header().Get("X-RateLimit-Remaining"))
+
+	assert.Equal(t, http.StatusTooManyRequests, rejected.Code)
+	assert.Equal(t, problem.ContentType, rejected.Header().Get("Content-Type"))
+	assert.Contains(t, rejected.Body.String(), `"type":"`+problem.TypeBase+CodeRateLimited+`"`)
+	assert.Contains(t, rejected.Body.String(), `"detail":"`+MsgRateLimited+`"`)
+	assert.Equal(t, "1", rejected.Header().Get("Retry-After"))
+	assert.Equal(t, "0", rejected.Header().Get("X-RateLimit-Remaining"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	assert.Empty(t, spans[0].Events())
+	require.Len(t, spans[2].Events(), 1)
+	event := spans[2].Events()[0]
+	assert.Equal(t, "rate limited", event.Name)
+	assert.Contains(t, event.Attributes, attribute.String("ratelimit.client_type", ClientIP))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	var rejections int64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == "app.ratelimit.rejections" {
+				for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+					rejections += dp.Value
+				}
+			}
+		}
+	}
+	assert.Equal(t, int64(1), rejections)
+}
+
+func TestMiddleware_SeparateBucketsPerClient(t *testing.T) {
+	// arrange
+	router, _, _ := setupRouter(t, NewMemoryStore(), Limit{Rate: 1, Burst: 1}, nil)
+
+	// act
+	byIPA := doRequest(router, http.MethodGet, "/api/v1/temperature/01310100", "203.0.113.7")
+	byIPB := doRequest(router, http.MethodGet, "/api/v1/temperature/01310100", "198.51.100.9")
+	byIPAAgain := doRequest(router, http.MethodGet, "/api/v1/temperature/01310100", "203.0.113.7")
+
+	// assert
+	assert.Equal(t, http.StatusOK, byIPA.Code)
+	assert.Equal(t, http.StatusOK, byIPB.Code)
+	assert.Equal(t, http.StatusTooManyRequests, byIPAAgain.Code)
+}
+
+func TestMiddleware_IgnoresUnauthenticatedAPIKey(t *testing.T) {
+	// arrange
+	router, _, _ := setupRouter(t, NewMemoryStore(), Limit{Rate: 1, Burst: 1}, nil)
+
+	request := func(apiKey string) int {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/v1/temperature/01310100", nil)
+		req.RemoteAddr = "203.0.113.7:51000"
+		req.Header.Set("X-API-Key", apiKey)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// act
+	statuses := []int{request("key-a"), request("key-b"), request("key-c")}
+
+	// assert
+	assert.Equal(t, []int{http.StatusOK, http.StatusTooManyRequests, http.StatusTooManyRequests}, statuses,
+		"chaves não validadas não podem abrir buckets novos")
+}
+
+func TestMiddleware_PerRouteLimit(t *testing.T) {
+	// arrange
+	routes := map[string]Limit{"/api/v1/temperature:batch": {Rate: 1, Burst: 1}}
+	router, _, _ := setupRouter(t, NewMemoryStore(), Limit{}, routes)
+
+	// act
+	batch := doRequest(router, http.MethodPost, "/api/v1/temperature:batch", "")
+	batchAgain := doRequest(router, http.MethodPost, "/api/v1/temperature:batch", "")
+	single := doRequest(router, http.MethodGet, "/api/v1/temperature/01310100", "")
+
+	// assert
+	assert.Equal(t, http.StatusOK, batch.Code)
+	assert.Equal(t, "1", batch.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, http.StatusTooManyRequests, batchAgain.Code)
+	assert.Equal(t, http.StatusOK, single.Code, "rota sem limite próprio e sem limite global")
+	assert.Empty(t, single.Header().Get("X-RateLimit-Limit"))
+}
+
+func TestMiddleware_StoreFailureAllowsRequest(t *testing.T) {
+	// arrange
+	router, recorder, _ := setupRouter(t, failingStore{}, Limit{Rate: 1, Burst: 1}, nil)
+
+	// act
+	rec := doRequest(router, http.MethodGet, "/api/v1/temperature/01310100", "")
+
+	// assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, recorder.Ended(), 1)
+	require.Len(t, recorder.Ended()[0].Events(), 1)
+	assert.Equal(t, "exception", recorder.Ended()[0].Events()[0].Name)
+}
+
+func TestMiddleware_NoLimitConfigured(t *testing.T) {
+	// arrange
+	router, _, _ := setupRouter(t, NewMemoryStore(), Limit{Rate: 0, Burst: 20}, map[string]Limit{})
+
+	// act
+	first := doRequest(router, http.MethodGet, "/api/v1/temperature/01310100", "")
+	second := doRequest(router, http.MethodGet, "/api/v1/temperature/01310100", "")
+
+	// assert
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Empty(t, second.Header().Get("X-RateLimit-Limit"))
+}
+
+func TestMiddleware_AuthenticatedClientLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// arrange
+	limits := map[string]Limit{"premium": {Rate: 1, Burst: 3}, "basic": {}}
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		name := c.GetHeader("X-Client")
+		c.Request = c.Request.WithContext(WithClient(c.Request.Context(), name, limits[name]))
+		c.Next()
+	}, Middleware(New(NewMemoryStore(), Limit{Rate: 1, Burst: 1}, nil, Limit{}, nil)))
+	router.GET("/api/v1/temperature/:cep", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	request := func(name string) *httptest.ResponseRecorder {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/v1/temperature/01310100", nil)
+		req.Header.Set("X-Client", name)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// act
+	var premium []int
+	for range 4 {
+		premium = append(premium, request("premium").Code)
+	}
+	basic := []int{request("basic").Code, request("basic").Code}
+
+	// assert
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, premium, "limite próprio do cliente")
+	assert.Equal(t, []int{http.StatusOK, http.StatusTooManyRequests}, basic, "cliente sem limite próprio usa o limite padrão")
+}
+
+func TestParseRoutes(t *testing.T) {
+	// act
+	routes, err := ParseRoutes(" /api/v1/temperature:batch=0.5:5, /api/v1/temperature/:cep/stream=1:2 ,")
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Limit{
+		"/api/v1/temperature:batch":       {Rate: 0.5, Burst: 5},
+		"/api/v1/temperature/:cep/stream": {Rate: 1, Burst: 2},
+	}, routes)
+
+	for _, spec := range []string{"/api/v1/x", "/api/v1/x=1", "/api/v1/x=0:1", "/api/v1/x=1:0", "/api/v1/x=a:1", "=1:1"} {
+		_, err := ParseRoutes(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestRetryAfterRoundsUp(t *testing.T) {
+	assert.Equal(t, 1, ceilSeconds(10*time.Millisecond))
+	assert.Equal(t, 2, ceilSeconds(1500*time.Millisecond))
+	assert.Equal(t, 0, ceilSeconds(0))
+}
+
+// setupFailedAuthRouter serves a route that answers 401 unless the request carries the
+// X-API-Key "valid", behind FailedAuth
+func setupFailedAuthRouter(t *testing.T, store Store, failedAuth Limit) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	v1 := router.Group("/api/v1", FailedAuth(New(store, Limit{}, nil, failedAuth, nil)))
+	v1.GET("/temperature/:cep", func(c *gin.Context) {
+		if c.GetHeader("X-API-Key") != "valid" {
+			c.Status(http.StatusUnauthorized)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func doAuthRequest(router http.Handler, clientIP, apiKey string) *httptest.ResponseRecorder {
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/v1/temperature/01310100", nil)
+	req.RemoteAddr = clientIP + ":51000"
+	req.Header.Set("X-API-Key", apiKey)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestFailedAuth_LimitsFailuresPerIP(t *testing.T) {
+	// arrange
+	router := setupFailedAuthRouter(t, NewMemoryStore(), Limit{Rate: 0.001, Burst: 2})
+
+	// act
+	first := doAuthRequest(router, "203.0.113.7", "guess-1")
+	second := doAuthRequest(router, "203.0.113.7", "guess-2")
+	rejected := doAuthRequest(router, "203.0.113.7", "guess-3")
+	rejectedValid := doAuthRequest(router, "203.0.113.7", "valid")
+	otherIP := doAuthRequest(router, "198.51.100.9", "valid")
+
+	// assert
+	assert.Equal(t, http.StatusUnauthorized, first.Code)
+	assert.Equal(t, http.StatusUnauthorized, second.Code)
+	assert.Equal(t, http.StatusTooManyRequests, rejected.Code)
+	assert.NotEmpty(t, rejected.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusTooManyRequests, rejectedValid.Code, "as credenciais não podem ser verificadas com o balde vazio")
+	assert.Equal(t, http.StatusOK, otherIP.Code)
+}
+
+func TestFailedAuth_SuccessesAreNotCounted(t *testing.T) {
+	// arrange
+	router := setupFailedAuthRouter(t, NewMemoryStore(), Limit{Rate: 0.001, Burst: 1})
+
+	// act
+	var statuses []int
+	for range 3 {
+		statuses = append(statuses, doAuthRequest(router, "203.0.113.7", "valid").Code)
+	}
+
+	// assert
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusOK}, statuses)
+}
+
+func TestFailedAuth_StoreUnavailable(t *testing.T) {
+	// arrange
+	router := setupFailedAuthRouter(t, failingStore{}, Limit{Rate: 0.001, Burst: 1})
+
+	// act
+	rec := doAuthRequest(router, "203.0.113.7", "valid")
+
+	// assert
+	assert.Equal(t, http.StatusOK, rec.Code, "falha no store não deve bloquear o tráfego")
+}
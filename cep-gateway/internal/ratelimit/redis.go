@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript applies the token bucket atomically on the Redis server, using its
+// clock so every gateway replica sees the same refill. It returns whether the
+// token was taken and the tokens left.
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(state[1]) or burst
+local updated = tonumber(state[2]) or now
+
+tokens = math.min(burst, tokens + math.max(0, now - updated) / 1000 * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) / rate * 1000) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+// peekScript refills the bucket like takeScript, without taking a token or writing
+// it back, and returns the tokens it holds
+var peekScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(state[1]) or burst
+local updated = tonumber(state[2]) or now
+
+return tostring(math.min(burst, tokens + math.max(0, now - updated) / 1000 * rate))
+`)
+
+// RedisStore keeps the buckets in Redis, so the limit is shared by every gateway replica
+type RedisStore struct {
+	client redis.Scripter
+	prefix string
+}
+
+func NewRedisStore(client redis.Scripter) *RedisStore {
+	return &RedisStore{client: client, prefix: "ratelimit:"}
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	reply, err := takeScript.Run(ctx, s.client, []string{s.prefix + key}, limit.Rate, limit.Burst).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	if len(reply) != 2 {
+		return Result{}, fmt.Errorf("unexpected rate limit script reply %v", reply)
+	}
+
+	allowed, _ := reply[0].(int64)
+	raw, _ := reply[1].(string)
+	tokens, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return Result{}, fmt.Errorf("unexpected rate limit script reply %v: %w", reply, err)
+	}
+	return result(allowed == 1, tokens, limit), nil
+}
+
+func (s *RedisStore) Peek(ctx context.Context, key string, limit Limit) (Result, error) {
+	raw, err := peekScript.Run(ctx, s.client, []string{s.prefix + key}, limit.Rate, limit.Burst).Text()
+	if err != nil {
+		return Result{}, err
+	}
+	tokens, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return Result{}, fmt.Errorf("unexpected rate limit script reply %q: %w", raw, err)
+	}
+	return result(tokens >= 1, tokens, limit), nil
+}
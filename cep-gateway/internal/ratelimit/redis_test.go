@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+	return server, client
+}
+
+func TestRedisStore_SharedBetweenReplicas(t *testing.T) {
+	// arrange
+	_, client := setupRedis(t)
+	replicaA := NewRedisStore(client)
+	replicaB := NewRedisStore(client)
+	limit := Limit{Rate: 1, Burst: 2}
+	ctx := context.Background()
+
+	// act
+	first, err := replicaA.Take(ctx, "ip:10.0.0.1", limit)
+	require.NoError(t, err)
+	second, err := replicaB.Take(ctx, "ip:10.0.0.1", limit)
+	require.NoError(t, err)
+	third, err := replicaA.Take(ctx, "ip:10.0.0.1", limit)
+	require.NoError(t, err)
+
+	// assert
+	assert.True(t, first.Allowed)
+	assert.Equal(t, 1, first.Remaining)
+	assert.True(t, second.Allowed)
+	assert.Equal(t, 0, second.Remaining)
+	assert.False(t, third.Allowed)
+	assert.Greater(t, third.RetryAfter, time.Duration(0))
+	assert.LessOrEqual(t, third.RetryAfter, time.Second)
+}
+
+func TestRedisStore_ExpiresIdleBuckets(t *testing.T) {
+	// arrange
+	server, client := setupRedis(t)
+	store := NewRedisStore(client)
+
+	// act
+	_, err := store.Take(context.Background(), "ip:10.0.0.1", Limit{Rate: 1, Burst: 5})
+	require.NoError(t, err)
+
+	// assert
+	ttl := server.TTL("ratelimit:ip:10.0.0.1")
+	assert.Greater(t, ttl, time.Duration(0))
+	assert.LessOrEqual(t, ttl, 3*time.Second)
+}
+
+func TestRedisStore_Unavailable(t *testing.T) {
+	// arrange
+	server, client := setupRedis(t)
+	store := NewRedisStore(client)
+	server.Close()
+
+	// act
+	_, err := store.Take(context.Background(), "ip:10.0.0.1", Limit{Rate: 1, Burst: 1})
+
+	// assert
+	assert.Error(t, err)
+}
+
+func TestRedisStore_Peek(t *testing.T) {
+	// arrange
+	_, client := setupRedis(t)
+	store := NewRedisStore(client)
+	limit := Limit{Rate: 0.001, Burst: 1}
+	ctx := context.Background()
+
+	// act
+	unknown, err := store.Peek(ctx, "ip:10.0.0.1", limit)
+	require.NoError(t, err)
+	_, err = store.Take(ctx, "ip:10.0.0.1", limit)
+	require.NoError(t, err)
+	empty, err := store.Peek(ctx, "ip:10.0.0.1", limit)
+	require.NoError(t, err)
+
+	// assert
+	assert.True(t, unknown.Allowed, "balde desconhecido está cheio")
+	assert.False(t, empty.Allowed)
+	assert.Greater(t, empty.RetryAfter, time.Duration(0))
+}
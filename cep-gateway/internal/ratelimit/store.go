@@ -0,0 +1,139 @@
+package ratelimit
+
+import (
+	"container/list"
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit is a token bucket refilled at Rate tokens per second up to Burst tokens
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Result describes the bucket after a request tried to take a token from it
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// RetryAfter is how long until the next token is available; zero when Allowed
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+}
+
+// Store keeps the token buckets; implementations must be safe for concurrent use
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+	// Peek reports whether Take would be allowed, without taking a token
+	Peek(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// take refills tokens for the elapsed time and tries to consume one,
+// returning the tokens left in the bucket
+func take(tokens float64, elapsed time.Duration, limit Limit) (float64, Result) {
+	tokens = refill(tokens, elapsed, limit)
+
+	allowed := tokens >= 1
+	if allowed {
+		tokens--
+	}
+	return tokens, result(allowed, tokens, limit)
+}
+
+func refill(tokens float64, elapsed time.Duration, limit Limit) float64 {
+	return math.Min(float64(limit.Burst), tokens+max(elapsed.Seconds(), 0)*limit.Rate)
+}
+
+func result(allowed bool, tokens float64, limit Limit) Result {
+	res := Result{
+		Allowed:   allowed,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+	if !allowed {
+		res.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+	return res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
+
+// maxBuckets bounds the memory store; once it is reached the least recently updated
+// bucket is dropped for each new client, which only forgets a client that has been idle
+// the longest and so most likely has refilled anyway
+const maxBuckets = 100_000
+
+type bucket struct {
+	key     string
+	tokens  float64
+	updated time.Time
+}
+
+// MemoryStore keeps the buckets in process, so each gateway replica limits on its own
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*list.Element
+	// recent orders the buckets from the most to the least recently updated
+	recent *list.List
+	now    func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return newMemoryStoreWithClock(time.Now)
+}
+
+func newMemoryStoreWithClock(now func() time.Time) *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*list.Element),
+		recent:  list.New(),
+		now:     now,
+	}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	elem, ok := s.buckets[key]
+	if ok {
+		s.recent.MoveToFront(elem)
+	} else {
+		if len(s.buckets) >= maxBuckets {
+			s.evictOldest()
+		}
+		elem = s.recent.PushFront(&bucket{key: key, tokens: float64(limit.Burst), updated: now})
+		s.buckets[key] = elem
+	}
+	b := elem.Value.(*bucket)
+
+	var res Result
+	b.tokens, res = take(b.tokens, now.Sub(b.updated), limit)
+	b.updated = now
+	return res, nil
+}
+
+func (s *MemoryStore) Peek(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens := float64(limit.Burst)
+	if elem, ok := s.buckets[key]; ok {
+		b := elem.Value.(*bucket)
+		tokens = refill(b.tokens, s.now().Sub(b.updated), limit)
+	}
+	return result(tokens >= 1, tokens, limit), nil
+}
+
+// evictOldest drops the least recently updated bucket
+func (s *MemoryStore) evictOldest() {
+	if oldest := s.recent.Back(); oldest != nil {
+		s.recent.Remove(oldest)
+		delete(s.buckets, oldest.Value.(*bucket).key)
+	}
+}
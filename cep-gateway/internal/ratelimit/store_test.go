@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_Take(t *testing.T) {
+	// arrange
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := newMemoryStoreWithClock(func() time.Time { return now })
+	limit := Limit{Rate: 2, Burst: 3}
+	ctx := context.Background()
+
+	// act & assert
+	for remaining := 2; remaining >= 0; remaining-- {
+		res, err := store.Take(ctx, "ip:10.0.0.1", limit)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, remaining, res.Remaining)
+	}
+
+	res, err := store.Take(ctx, "ip:10.0.0.1", limit)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed, "balde vazio deve rejeitar")
+	assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+	assert.Equal(t, 1500*time.Millisecond, res.Reset)
+
+	other, err := store.Take(ctx, "ip:10.0.0.2", limit)
+	require.NoError(t, err)
+	assert.True(t, other.Allowed, "cada cliente tem o próprio balde")
+
+	now = now.Add(500 * time.Millisecond)
+	res, err = store.Take(ctx, "ip:10.0.0.1", limit)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed, "um token deve ter sido reposto")
+	assert.Equal(t, 0, res.Remaining)
+}
+
+func TestMemoryStore_BoundedByMaxBuckets(t *testing.T) {
+	// arrange
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := newMemoryStoreWithClock(func() time.Time { return now })
+	limit := Limit{Rate: 1, Burst: 1}
+	ctx := context.Background()
+
+	// act
+	for i := range maxBuckets + 100 {
+		res, err := store.Take(ctx, fmt.Sprintf("ip:%d", i), limit)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+	}
+
+	// assert
+	assert.Len(t, store.buckets, maxBuckets, "baldes esvaziados de clientes novos não podem crescer sem limite")
+	assert.Equal(t, maxBuckets, store.recent.Len())
+	assert.NotContains(t, store.buckets, "ip:0", "o balde atualizado há mais tempo deve ser descartado")
+	assert.Contains(t, store.buckets, fmt.Sprintf("ip:%d", maxBuckets+99))
+}
+
+func TestMemoryStore_EvictsLeastRecentlyUpdated(t *testing.T) {
+	// arrange
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := newMemoryStoreWithClock(func() time.Time { return now })
+	limit := Limit{Rate: 1, Burst: 2}
+	ctx := context.Background()
+	for i := range maxBuckets {
+		_, err := store.Take(ctx, fmt.Sprintf("ip:%d", i), limit)
+		require.NoError(t, err)
+	}
+
+	// act
+	_, err := store.Take(ctx, "ip:0", limit)
+	require.NoError(t, err)
+	_, err = store.Take(ctx, "ip:new", limit)
+	require.NoError(t, err)
+	drained, err := store.Take(ctx, "ip:0", limit)
+	require.NoError(t, err)
+
+	// assert
+	assert.Contains(t, store.buckets, "ip:0", "um cliente ativo não deve ser descartado")
+	assert.NotContains(t, store.buckets, "ip:1")
+	assert.False(t, drained.Allowed, "o balde mantido continua vazio")
+}
+
+func TestMemoryStore_Peek(t *testing.T) {
+	// arrange
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := newMemoryStoreWithClock(func() time.Time { return now })
+	limit := Limit{Rate: 1, Burst: 1}
+	ctx := context.Background()
+
+	// act
+	unknown, err := store.Peek(ctx, "ip:10.0.0.1", limit)
+	require.NoError(t, err)
+	_, err = store.Take(ctx, "ip:10.0.0.1", limit)
+	require.NoError(t, err)
+	empty, err := store.Peek(ctx, "ip:10.0.0.1", limit)
+	require.NoError(t, err)
+	again, err := store.Peek(ctx, "ip:10.0.0.1", limit)
+	require.NoError(t, err)
+
+	// assert
+	assert.True(t, unknown.Allowed, "balde desconhecido está cheio")
+	assert.False(t, empty.Allowed)
+	assert.Equal(t, time.Second, empty.RetryAfter)
+	assert.Equal(t, empty, again, "consultar o balde não pode consumir tokens")
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type TemperatureServiceStub struct {
+	mock.Mock
+}
+
+func NewTemperatureServiceStub() *TemperatureServiceStub {
+	return &TemperatureServiceStub{}
+}
+
+func (s *TemperatureServiceStub) GetTemperature(ctx context.Context, cep string) (*model.TemperatureResponse, error) {
+	args := s.Called(ctx, cep)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TemperatureResponse), nil
+}
+
+func (s *TemperatureServiceStub) GetTemperatures(ctx context.Context, ceps []string) []TemperatureResult {
+	args := s.Called(ctx, ceps)
+	return args.Get(0).([]TemperatureResult)
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alexduzi/laboteldistributedtracing/cep"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/client"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/model"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const tracerName = "github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/service"
+
+var ErrInvalidZipcode = errors.New("invalid zipcode")
+
+type TemperatureServiceInterface interface {
+	GetTemperature(ctx context.Context, cep string) (*model.TemperatureResponse, error)
+	GetTemperatures(ctx context.Context, ceps []string) []TemperatureResult
+}
+
+// TemperatureResult holds the outcome of one CEP of a batch lookup
+type TemperatureResult = client.TemperatureResult
+
+type TemperatureService struct {
+	config       *config.Config
+	engineClient client.WeatherEngineClientInterface
+}
+
+func NewTemperatureService(cfg *config.Config, engineClient client.WeatherEngineClientInterface) *TemperatureService {
+	return &TemperatureService{
+		config:       cfg,
+		engineClient: engineClient,
+	}
+}
+
+// GetTemperature validates the CEP and forwards it to the weather-engine
+func (s *TemperatureService) GetTemperature(ctx context.Context, raw string) (*model.TemperatureResponse, error) {
+	code, err := cep.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidZipcode, err)
+	}
+	normalized := code.String()
+
+	return s.engineClient.GetTemperature(ctx, normalized)
+}
+
+// GetTemperatures validates every distinct CEP and forwards the valid ones to the
+// weather-engine in a single batch call. Results follow the order in which each CEP
+// first appears in the input.
+func (s *TemperatureService) GetTemperatures(ctx context.Context, ceps []string) []TemperatureResult {
+	unique := cep.Deduplicate(ceps)
+
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "batch temperature")
+	defer span.End()
+
+	results := make([]TemperatureResult, len(unique))
+	valid := make([]string, 0, len(unique))
+	positions := make([]int, 0, len(unique))
+	for i, raw := range unique {
+		code, err := cep.Parse(raw)
+		if err != nil {
+			results[i] = TemperatureResult{Cep: raw, Err: fmt.Errorf("%w: %w", ErrInvalidZipcode, err)}
+			continue
+		}
+		valid = append(valid, code.String())
+		positions = append(positions, i)
+	}
+
+	span.SetAttributes(
+		attribute.Int("batch.size", len(ceps)),
+		attribute.Int("batch.unique", len(unique)),
+		attribute.Int("batch.forwarded", len(valid)),
+	)
+
+	if len(valid) > 0 {
+		forwarded, err := s.engineClient.GetTemperatures(ctx, valid)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		for j, i := range positions {
+			if err != nil {
+				results[i] = TemperatureResult{Cep: valid[j], Err: err}
+				continue
+			}
+			results[i] = forwarded[j]
+		}
+	}
+
+	failed := 0
+	for _, res := range results {
+		if res.Err != nil {
+			failed++
+		}
+	}
+	span.SetAttributes(attribute.Int("batch.failed", failed))
+
+	return results
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/client"
+	cErrors "github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/client/error"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestService() (*TemperatureService, *client.WeatherEngineClientStub) {
+	engineClient := client.NewWeatherEngineClientStub()
+	cfg := &config.Config{BatchMaxSize: 250}
+	return NewTemperatureService(cfg, engineClient), engineClient
+}
+
+func TestGetTemperature_NormalizesBeforeForwarding(t *testing.T) {
+	// arrange
+	svc, engineClient := newTestService()
+
+	engineClient.On("GetTemperature", mock.Anything, "01310100").
+		Return(&model.TemperatureResponse{Celsius: 28.5, Fahrenheit: 83.3, Kelvin: 301.65}, nil)
+
+	// act
+	result, err := svc.GetTemperature(context.Background(), "01310-100")
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, 28.5, result.Celsius)
+	engineClient.AssertExpectations(t)
+}
+
+func TestGetTemperature_InvalidZipcode(t *testing.T) {
+	// arrange
+	svc, engineClient := newTestService()
+
+	// act
+	result, err := svc.GetTemperature(context.Background(), "0131-0100A")
+
+	// assert
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrInvalidZipcode)
+	engineClient.AssertNotCalled(t, "GetTemperature", mock.Anything, mock.Anything)
+}
+
+func TestGetTemperatures_DeduplicatesAndKeepsInputOrder(t *testing.T) {
+	// arrange
+	svc, engineClient := newTestService()
+
+	engineClient.On("GetTemperatures", mock.Anything, []string{"01310100", "99999999"}).Return([]client.TemperatureResult{
+		{Cep: "01310100", Temperature: &model.TemperatureResponse{Celsius: 28.5}},
+		{Cep: "99999999", Err: cErrors.EngineClientNotFound},
+	}, nil)
+
+	// act
+	results := svc.GetTemperatures(context.Background(), []string{"01310-100", "123", "99999999", "01310100"})
+
+	// assert
+	require.Len(t, results, 3)
+	assert.Equal(t, "01310100", results[0].Cep)
+	assert.Equal(t, 28.5, results[0].Temperature.Celsius)
+	assert.Equal(t, "123", results[1].Cep)
+	assert.ErrorIs(t, results[1].Err, ErrInvalidZipcode)
+	assert.Equal(t, "99999999", results[2].Cep)
+	assert.ErrorIs(t, results[2].Err, cErrors.EngineClientNotFound)
+
+	engineClient.AssertNumberOfCalls(t, "GetTemperatures", 1)
+	engineClient.AssertNotCalled(t, "GetTemperature", mock.Anything, mock.Anything)
+}
+
+func TestGetTemperatures_EngineCallFails(t *testing.T) {
+	// arrange
+	svc, engineClient := newTestService()
+
+	engineClient.On("GetTemperatures", mock.Anything, []string{"01310100", "20040002"}).Return(nil, cErrors.EngineClientInternalError)
+
+	// act
+	results := svc.GetTemperatures(context.Background(), []string{"01310100", "abc", "20040002"})
+
+	// assert
+	require.Len(t, results, 3)
+	assert.ErrorIs(t, results[0].Err, cErrors.EngineClientInternalError)
+	assert.ErrorIs(t, results[1].Err, ErrInvalidZipcode, "CEPs inválidos não são enviados ao engine")
+	assert.ErrorIs(t, results[2].Err, cErrors.EngineClientInternalError)
+	assert.Equal(t, []string{"01310100", "abc", "20040002"}, []string{results[0].Cep, results[1].Cep, results[2].Cep})
+}
+
+func TestGetTemperatures_OnlyInvalidCepsSkipEngine(t *testing.T) {
+	// arrange
+	svc, engineClient := newTestService()
+
+	// act
+	results := svc.GetTemperatures(context.Background(), []string{"abc", "123"})
+
+	// assert
+	require.Len(t, results, 2)
+	assert.ErrorIs(t, results[0].Err, ErrInvalidZipcode)
+	assert.ErrorIs(t, results[1].Err, ErrInvalidZipcode)
+	engineClient.AssertNotCalled(t, "GetTemperatures", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,171 @@
+package stream
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/model"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/service"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/stream"
+
+// Event is a temperature change observed by a poller
+type Event struct {
+	ID          uint64
+	Cep         string
+	Temperature model.TemperatureResponse
+	// SpanContext identifies the poll that produced the event so emitters can link back to it
+	SpanContext trace.SpanContext
+}
+
+// Hub shares one upstream poller per CEP across all of its subscribers
+type Hub struct {
+	service  service.TemperatureServiceInterface
+	interval time.Duration
+
+	mu      sync.Mutex
+	pollers map[string]*poller
+}
+
+type poller struct {
+	cep         string
+	cancel      context.CancelFunc
+	subscribers map[chan Event]struct{}
+	last        *Event
+}
+
+// DefaultInterval is the poll interval used when NewHub is given a non-positive one
+const DefaultInterval = 30 * time.Second
+
+func NewHub(temperatureService service.TemperatureServiceInterface, interval time.Duration) *Hub {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Hub{
+		service:  temperatureService,
+		interval: interval,
+		pollers:  make(map[string]*poller),
+	}
+}
+
+// Subscribe registers a listener for the CEP, starting its poller if needed.
+// The latest known event is delivered right away. The returned function must be
+// called once the subscriber goes away; the poller stops with its last subscriber.
+func (h *Hub) Subscribe(cep string) (<-chan Event, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	p, ok := h.pollers[cep]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		p = &poller{
+			cep:         cep,
+			cancel:      cancel,
+			subscribers: make(map[chan Event]struct{}),
+		}
+		h.pollers[cep] = p
+		go h.run(ctx, p)
+	}
+
+	ch := make(chan Event, 1)
+	p.subscribers[ch] = struct{}{}
+	if p.last != nil {
+		ch <- *p.last
+	}
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+
+			delete(p.subscribers, ch)
+			close(ch)
+			if len(p.subscribers) == 0 {
+				p.cancel()
+				delete(h.pollers, cep)
+			}
+		})
+	}
+	return ch, unsubscribe
+}
+
+// Pollers returns the number of active upstream pollers
+func (h *Hub) Pollers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.pollers)
+}
+
+func (h *Hub) run(ctx context.Context, p *poller) {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		h.poll(ctx, p)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *Hub) poll(ctx context.Context, p *poller) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "poll temperature",
+		trace.WithNewRoot(),
+		trace.WithAttributes(attribute.String("cep", p.cep)))
+	defer span.End()
+
+	temperature, err := h.service.GetTemperature(ctx, p.cep)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	changed := p.last == nil || p.last.Temperature != *temperature
+	span.SetAttributes(
+		attribute.Bool("stream.changed", changed),
+		attribute.Int("stream.subscribers", len(p.subscribers)),
+	)
+	if !changed || ctx.Err() != nil {
+		return
+	}
+
+	var id uint64 = 1
+	if p.last != nil {
+		id = p.last.ID + 1
+	}
+	event := Event{ID: id, Cep: p.cep, Temperature: *temperature, SpanContext: span.SpanContext()}
+	p.last = &event
+
+	for ch := range p.subscribers {
+		publish(ch, event)
+	}
+}
+
+// publish delivers the event without blocking the poller, replacing an undelivered older event
+func publish(ch chan Event, event Event) {
+	select {
+	case ch <- event:
+		return
+	default:
+	}
+
+	select {
+	case <-ch:
+	default:
+	}
+	ch <- event
+}
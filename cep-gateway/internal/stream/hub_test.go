@@ -0,0 +1,128 @@
+package stream
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/model"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sequenceService devolve as temperaturas em sequência, repetindo a última
+type sequenceService struct {
+	*service.TemperatureServiceStub
+	mu    sync.Mutex
+	temps []float64
+	calls atomic.Int32
+}
+
+func (s *sequenceService) GetTemperature(ctx context.Context, cep string) (*model.TemperatureResponse, error) {
+	n := int(s.calls.Add(1)) - 1
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	celsius := s.temps[min(n, len(s.temps)-1)]
+	return &model.TemperatureResponse{Celsius: celsius}, nil
+}
+
+func receive(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+		return Event{}
+	}
+}
+
+func TestHub_EmitsOnlyWhenDataChanges(t *testing.T) {
+	// arrange
+	svc := &sequenceService{temps: []float64{20, 20, 20, 21}}
+	hub := NewHub(svc, 5*time.Millisecond)
+
+	// act
+	events, unsubscribe := hub.Subscribe("01310100")
+	defer unsubscribe()
+
+	first := receive(t, events)
+	second := receive(t, events)
+
+	// assert
+	assert.Equal(t, uint64(1), first.ID)
+	assert.Equal(t, 20.0, first.Temperature.Celsius)
+	assert.Equal(t, uint64(2), second.ID)
+	assert.Equal(t, 21.0, second.Temperature.Celsius)
+	assert.GreaterOrEqual(t, svc.calls.Load(), int32(4))
+}
+
+func TestNewHub_DefaultsNonPositiveInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		// act
+		hub := NewHub(&sequenceService{temps: []float64{20}}, interval)
+
+		// assert
+		assert.Equal(t, DefaultInterval, hub.interval, "um intervalo não positivo faria o ticker entrar em pânico")
+	}
+}
+
+func TestHub_SharesOnePollerPerCep(t *testing.T) {
+	// arrange
+	svc := &sequenceService{temps: []float64{20}}
+	hub := NewHub(svc, time.Hour)
+
+	// act
+	events1, unsubscribe1 := hub.Subscribe("01310100")
+	first := receive(t, events1)
+
+	events2, unsubscribe2 := hub.Subscribe("01310100")
+	replay := receive(t, events2)
+
+	_, unsubscribe3 := hub.Subscribe("20040002")
+
+	// assert
+	assert.Equal(t, 2, hub.Pollers())
+	assert.Equal(t, first, replay)
+
+	unsubscribe1()
+	assert.Equal(t, 2, hub.Pollers())
+
+	unsubscribe2()
+	unsubscribe3()
+	assert.Equal(t, 0, hub.Pollers())
+}
+
+func TestHub_UnsubscribeClosesChannel(t *testing.T) {
+	// arrange
+	svc := &sequenceService{temps: []float64{20}}
+	hub := NewHub(svc, time.Hour)
+
+	events, unsubscribe := hub.Subscribe("01310100")
+	receive(t, events)
+
+	// act
+	unsubscribe()
+	unsubscribe()
+
+	// assert
+	_, open := <-events
+	assert.False(t, open)
+	require.Equal(t, 0, hub.Pollers())
+}
+
+func TestPublish_ReplacesUndeliveredEvent(t *testing.T) {
+	// arrange
+	ch := make(chan Event, 1)
+
+	// act
+	publish(ch, Event{ID: 1})
+	publish(ch, Event{ID: 2})
+
+	// assert
+	assert.Equal(t, uint64(2), (<-ch).ID)
+}
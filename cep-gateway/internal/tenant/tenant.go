@@ -0,0 +1,93 @@
+package tenant
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/auth"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/codec"
+	"github.com/alexduzi/laboteldistributedtracing/cepgateway/internal/model"
+	"github.com/alexduzi/laboteldistributedtracing/platform/problem"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Header identifies the calling tenant when the caller is not authenticated
+const Header = "X-Tenant-ID"
+
+// BaggageKey is the baggage member propagating the tenant to the weather-engine
+const BaggageKey = "tenant.id"
+
+// Anonymous identifies callers that did not send a tenant
+const Anonymous = "anonymous"
+
+const (
+	CodeInvalidTenant = "invalid_tenant"
+	MsgInvalidTenant  = "invalid tenant id"
+)
+
+var validID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// FromContext returns the tenant carried in the baggage of ctx, or Anonymous
+func FromContext(ctx context.Context) string {
+	if id := baggage.FromContext(ctx).Member(BaggageKey).Value(); id != "" {
+		return id
+	}
+	return Anonymous
+}
+
+// WithTenant returns a copy of ctx whose baggage carries the tenant
+func WithTenant(ctx context.Context, id string) (context.Context, error) {
+	member, err := baggage.NewMemberRaw(BaggageKey, id)
+	if err != nil {
+		return ctx, err
+	}
+	bag, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return ctx, err
+	}
+	return baggage.ContextWithBaggage(ctx, bag), nil
+}
+
+// Middleware puts the tenant into the request baggage, from where the instrumented
+// engine clients propagate it, and tags the server span with it. The tenant of an
+// authenticated caller is its identity (see auth.Identity) and Header is ignored;
+// otherwise it is the one sent in Header, or Anonymous. A tenant sent by the caller in
+// its own baggage is always replaced, so it cannot pick another tenant's cache
+// partition or rate limit bucket. Malformed identifiers in Header are rejected with 422.
+// It must run after otelgin and auth.Middleware.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := auth.Identity(c.Request.Context())
+		if !ok {
+			id = c.GetHeader(Header)
+			if id == "" {
+				id = Anonymous
+			} else if !validID.MatchString(id) {
+				abortInvalid(c)
+				return
+			}
+		}
+
+		ctx, err := WithTenant(c.Request.Context(), id)
+		if err != nil {
+			abortInvalid(c)
+			return
+		}
+
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String(BaggageKey, id))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+func abortInvalid(c *gin.Context) {
+	codec.Abort(c, problem.New(http.StatusUnprocessableEntity, CodeInvalidTenant, MsgInvalidTenant, model.ErrorDetail{
+		Field:   Header,
+		Code:    codec.RuleFormat,
+		Message: "must be 1 to 64 letters, digits, dots, underscores or hyphens",
+	}))
+}
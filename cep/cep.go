@@ -0,0 +1,107 @@
+// Package cep parses and normalises Brazilian postal codes (CEPs). It is shared by the
+// cep-gateway and the weather-engine so both accept and reject exactly the same input.
+package cep
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrEmpty   = errors.New("empty CEP")
+	ErrFormat  = errors.New("CEP must have eight digits, written as 00000000 or 00000-000")
+	ErrInvalid = errors.New("CEP is outside the ranges assigned by the Correios")
+)
+
+// Length is the number of digits of a CEP
+const Length = 8
+
+// CEP is a valid postal code holding its eight digits, without the hyphen.
+// The zero value is not a valid CEP; obtain one with Parse.
+type CEP string
+
+// Parse normalises s, ignoring surrounding whitespace and accepting the digits with or
+// without the hyphen before the last three, and rejects ranges no CEP is assigned to
+func Parse(s string) (CEP, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", ErrEmpty
+	}
+
+	if len(s) == Length+1 {
+		if s[5] != '-' {
+			return "", ErrFormat
+		}
+		s = s[:5] + s[6:]
+	}
+	if len(s) != Length {
+		return "", ErrFormat
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return "", ErrFormat
+		}
+	}
+
+	// the Correios numbering starts at 01000-000
+	if s < "01000000" {
+		return "", ErrInvalid
+	}
+	return CEP(s), nil
+}
+
+// Deduplicate drops repeated CEPs, treating the hyphenated and plain forms as the same entry.
+// Valid entries are returned normalised; invalid ones are kept as given, trimmed, so they
+// can be reported back.
+func Deduplicate(ceps []string) []string {
+	seen := make(map[string]struct{}, len(ceps))
+	unique := make([]string, 0, len(ceps))
+
+	for _, raw := range ceps {
+		key := strings.TrimSpace(raw)
+		if code, err := Parse(key); err == nil {
+			key = code.String()
+		}
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+		unique = append(unique, key)
+	}
+	return unique
+}
+
+// MustParse is like Parse but panics on invalid input; meant for constants and tests
+func MustParse(s string) CEP {
+	c, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// String returns the eight digits, as sent to the CEP providers
+func (c CEP) String() string {
+	return string(c)
+}
+
+// Formatted returns the CEP in the usual 00000-000 form
+func (c CEP) Formatted() string {
+	if len(c) != Length {
+		return string(c)
+	}
+	return string(c[:5]) + "-" + string(c[5:])
+}
+
+// Prefix returns the first n digits of the CEP, which narrow down its location
+func (c CEP) Prefix(n int) string {
+	return string(c[:min(max(n, 0), len(c))])
+}
+
+// Region returns the postal region of the CEP, given by its first digit
+func (c CEP) Region() Region {
+	if len(c) == 0 {
+		return Region{}
+	}
+	return regions[c[0]-'0']
+}
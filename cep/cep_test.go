@@ -0,0 +1,116 @@
+package cep
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected CEP
+		err      error
+	}{
+		{"Somente dígitos", "01310100", "01310100", nil},
+		{"Com hífen", "01310-100", "01310100", nil},
+		{"Com espaços nas pontas", " \t01310-100\n", "01310100", nil},
+		{"Primeiro CEP atribuído", "01000-000", "01000000", nil},
+		{"Último CEP", "99999-999", "99999999", nil},
+		{"Vazio", "", "", ErrEmpty},
+		{"Somente espaços", "   ", "", ErrEmpty},
+		{"Curto", "0131010", "", ErrFormat},
+		{"Longo", "013101000", "", ErrFormat},
+		{"Com letra", "0131A100", "", ErrFormat},
+		{"Hífen fora de posição", "0131-0100", "", ErrFormat},
+		{"Dois hífens", "01310--100", "", ErrFormat},
+		{"Espaço no meio", "01310 100", "", ErrFormat},
+		{"Dígitos não ASCII", "０１３１０１００", "", ErrFormat},
+		{"Sinal", "+1310100", "", ErrFormat},
+		{"Zeros", "00000000", "", ErrInvalid},
+		{"Faixa não atribuída", "00999-999", "", ErrInvalid},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			result, err := Parse(tc.input)
+
+			// assert
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestCEP_Formats(t *testing.T) {
+	// arrange
+	c := MustParse("01310100")
+
+	// act & assert
+	assert.Equal(t, "01310100", c.String())
+	assert.Equal(t, "01310-100", c.Formatted())
+	assert.Equal(t, "013", c.Prefix(3))
+	assert.Equal(t, "01310100", c.Prefix(20))
+	assert.Equal(t, "", c.Prefix(-1))
+}
+
+func TestDeduplicate(t *testing.T) {
+	// act
+	result := Deduplicate([]string{"01310-100", "01310100", " x", "x", "20040002"})
+
+	// assert
+	assert.Equal(t, []string{"01310100", "x", "20040002"}, result)
+}
+
+func TestMustParse_Panics(t *testing.T) {
+	assert.Panics(t, func() { MustParse("abc") })
+}
+
+func TestCEP_Region(t *testing.T) {
+	testCases := []struct {
+		input string
+		digit int
+		ufs   []string
+	}{
+		{"01310-100", 0, []string{"SP"}},
+		{"13015-904", 1, []string{"SP"}},
+		{"20040-002", 2, []string{"RJ", "ES"}},
+		{"30130-010", 3, []string{"MG"}},
+		{"40020-000", 4, []string{"BA", "SE"}},
+		{"50030-230", 5, []string{"PE", "AL", "PB", "RN"}},
+		{"69005-040", 6, []string{"CE", "PI", "MA", "PA", "AP", "AM", "RR", "AC"}},
+		{"70040-010", 7, []string{"DF", "GO", "TO", "MT", "MS", "RO"}},
+		{"80010-000", 8, []string{"PR", "SC"}},
+		{"90010-150", 9, []string{"RS"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			// act
+			region := MustParse(tc.input).Region()
+
+			// assert
+			assert.Equal(t, tc.digit, region.Digit)
+			assert.Equal(t, tc.ufs, region.UFs)
+			assert.LessOrEqual(t, region.First, tc.input)
+			assert.GreaterOrEqual(t, region.Last, tc.input)
+		})
+	}
+}
+
+func TestRegions(t *testing.T) {
+	// act
+	all := Regions()
+	all[0].Name = "alterado"
+
+	// assert
+	require.Len(t, all, 10)
+	for i, region := range Regions() {
+		assert.Equal(t, i, region.Digit)
+		assert.NotEmpty(t, region.UFs)
+	}
+	assert.NotEqual(t, "alterado", Regions()[0].Name, "Regions deve devolver uma cópia")
+}
@@ -0,0 +1,58 @@
+package cep
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		"01310100", "01310-100", " 01310-100 ", "", "0131A100", "0131-0100",
+		"00000000", "99999-999", "01310--100", "０１３１０１００", "\x00\xff",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		c, err := Parse(input)
+		if err != nil {
+			if c != "" {
+				t.Fatalf("Parse(%q) returned %q with error %v", input, c, err)
+			}
+			return
+		}
+
+		digits := c.String()
+		if len(digits) != Length || strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+			t.Fatalf("Parse(%q) = %q, want eight ASCII digits", input, digits)
+		}
+		if digits < "01000000" {
+			t.Fatalf("Parse(%q) = %q, accepted an unassigned range", input, digits)
+		}
+
+		// the result is a fixed point: both forms parse back to the same CEP
+		for _, form := range []string{c.String(), c.Formatted()} {
+			again, err := Parse(form)
+			if err != nil || again != c {
+				t.Fatalf("Parse(%q) = %q, %v; want %q", form, again, err, c)
+			}
+		}
+
+		// only whitespace and the hyphen are dropped from the input
+		stripped := strings.ReplaceAll(strings.TrimFunc(input, unicode.IsSpace), "-", "")
+		if stripped != digits {
+			t.Fatalf("Parse(%q) = %q, dropped more than whitespace and the hyphen", input, digits)
+		}
+
+		region := c.Region()
+		if region.Digit != int(digits[0]-'0') || len(region.UFs) == 0 {
+			t.Fatalf("Region of %q = %+v", c, region)
+		}
+
+		if location, ok := Locate(c); ok && !slices.Contains(region.UFs, location.UF) {
+			t.Fatalf("Locate(%q) = %+v, outside region %d", c, location, region.Digit)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package cep
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strings"
+)
+
+// ranges.csv maps CEP ranges to the city they belong to. Every UF has a range spanning
+// all of its CEPs, pointing at the capital, plus narrower ranges for the capital itself
+// and a few major cities; the narrowest range containing a CEP wins.
+//
+//go:embed ranges.csv
+var rangesCSV string
+
+// Location is the approximate place of a CEP, resolved offline from its range
+type Location struct {
+	UF   string
+	City string
+	// First and Last bound the matched range, as eight digits
+	First string
+	Last  string
+}
+
+func (l Location) span() int {
+	return atoi(l.Last) - atoi(l.First)
+}
+
+var ranges = mustLoadRanges(rangesCSV)
+
+// Locate resolves the CEP to its UF and city without calling any provider. The city is
+// the one owning the narrowest known range containing the CEP, which is the capital of
+// the UF when no closer city is known; ok is false for CEPs outside every range.
+func Locate(c CEP) (location Location, ok bool) {
+	digits := c.String()
+	for _, r := range ranges {
+		if digits < r.First || digits > r.Last {
+			continue
+		}
+		if !ok || r.span() < location.span() {
+			location, ok = r, true
+		}
+	}
+	return location, ok
+}
+
+func mustLoadRanges(data string) []Location {
+	records, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+	if err != nil {
+		panic(fmt.Sprintf("cep: reading ranges: %v", err))
+	}
+
+	loaded := make([]Location, 0, len(records))
+	for i, record := range records[1:] {
+		if len(record) != 4 {
+			panic(fmt.Sprintf("cep: ranges line %d: want 4 fields, got %d", i+2, len(record)))
+		}
+		first, errFirst := Parse(record[0])
+		last, errLast := Parse(record[1])
+		if errFirst != nil || errLast != nil || first > last {
+			panic(fmt.Sprintf("cep: ranges line %d: invalid range %s-%s", i+2, record[0], record[1]))
+		}
+		loaded = append(loaded, Location{UF: record[2], City: record[3], First: first.String(), Last: last.String()})
+	}
+	return loaded
+}
+
+// atoi converts the eight digits of a range bound; they are validated on load
+func atoi(digits string) int {
+	n := 0
+	for i := 0; i < len(digits); i++ {
+		n = n*10 + int(digits[i]-'0')
+	}
+	return n
+}
@@ -0,0 +1,76 @@
+package cep
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocate(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		uf    string
+		city  string
+	}{
+		{"Capital de SP", "01310-100", "SP", "São Paulo"},
+		{"Cidade do interior conhecida", "13015-904", "SP", "Campinas"},
+		{"Interior de SP cai na capital", "17500-000", "SP", "São Paulo"},
+		{"Capital do RJ", "20040-002", "RJ", "Rio de Janeiro"},
+		{"Interior do ES cai na capital", "29300-000", "ES", "Vitória"},
+		{"Capital de MG", "30130-010", "MG", "Belo Horizonte"},
+		{"Capital de PE", "50030-230", "PE", "Recife"},
+		{"Segunda faixa do AM", "69500-000", "AM", "Manaus"},
+		{"Roraima entre as faixas do AM", "69301-000", "RR", "Boa Vista"},
+		{"Capital federal", "70040-010", "DF", "Brasília"},
+		{"Segunda faixa do DF", "73000-000", "DF", "Brasília"},
+		{"Goiás entre as faixas do DF", "72800-000", "GO", "Goiânia"},
+		{"Capital do RS", "90010-150", "RS", "Porto Alegre"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			location, ok := Locate(MustParse(tc.input))
+
+			// assert
+			require.True(t, ok)
+			assert.Equal(t, tc.uf, location.UF)
+			assert.Equal(t, tc.city, location.City)
+			assert.LessOrEqual(t, location.First, MustParse(tc.input).String())
+			assert.GreaterOrEqual(t, location.Last, MustParse(tc.input).String())
+		})
+	}
+}
+
+func TestLocate_Unassigned(t *testing.T) {
+	// act
+	_, ok := Locate(MustParse("78900-000"))
+
+	// assert
+	assert.False(t, ok)
+}
+
+func TestRanges_MatchRegions(t *testing.T) {
+	covered := map[string]bool{}
+	for _, r := range ranges {
+		// assert
+		for _, bound := range []string{r.First, r.Last} {
+			region := CEP(bound).Region()
+			assert.Contains(t, region.UFs, r.UF, "faixa %s-%s fora da região %d", r.First, r.Last, region.Digit)
+		}
+		covered[r.UF] = true
+	}
+
+	for _, region := range Regions() {
+		for _, uf := range region.UFs {
+			assert.True(t, covered[uf], "UF %s sem faixa", uf)
+		}
+	}
+}
+
+func TestMustLoadRanges_Panics(t *testing.T) {
+	assert.Panics(t, func() { mustLoadRanges("first,last,uf,city\n02000000,01000000,SP,São Paulo\n") })
+	assert.Panics(t, func() { mustLoadRanges("first,last,uf,city\n01000000,SP\n") })
+}
@@ -0,0 +1,31 @@
+package cep
+
+// Region is one of the ten postal regions of the Correios, identified by the first
+// digit of the CEP and spanning the states (UFs) listed
+type Region struct {
+	Digit int
+	Name  string
+	UFs   []string
+	// First and Last bound the CEPs of the region, in the 00000-000 form
+	First string
+	Last  string
+}
+
+var regions = [10]Region{
+	{Digit: 0, Name: "Grande São Paulo", UFs: []string{"SP"}, First: "01000-000", Last: "09999-999"},
+	{Digit: 1, Name: "Interior de São Paulo", UFs: []string{"SP"}, First: "10000-000", Last: "19999-999"},
+	{Digit: 2, Name: "Rio de Janeiro e Espírito Santo", UFs: []string{"RJ", "ES"}, First: "20000-000", Last: "29999-999"},
+	{Digit: 3, Name: "Minas Gerais", UFs: []string{"MG"}, First: "30000-000", Last: "39999-999"},
+	{Digit: 4, Name: "Bahia e Sergipe", UFs: []string{"BA", "SE"}, First: "40000-000", Last: "49999-999"},
+	{Digit: 5, Name: "Pernambuco, Alagoas, Paraíba e Rio Grande do Norte", UFs: []string{"PE", "AL", "PB", "RN"}, First: "50000-000", Last: "59999-999"},
+	{Digit: 6, Name: "Norte e Ceará, Piauí e Maranhão", UFs: []string{"CE", "PI", "MA", "PA", "AP", "AM", "RR", "AC"}, First: "60000-000", Last: "69999-999"},
+	{Digit: 7, Name: "Centro-Oeste, Tocantins e Rondônia", UFs: []string{"DF", "GO", "TO", "MT", "MS", "RO"}, First: "70000-000", Last: "79999-999"},
+	{Digit: 8, Name: "Paraná e Santa Catarina", UFs: []string{"PR", "SC"}, First: "80000-000", Last: "89999-999"},
+	{Digit: 9, Name: "Rio Grande do Sul", UFs: []string{"RS"}, First: "90000-000", Last: "99999-999"},
+}
+
+// Regions returns the ten postal regions, indexed by their digit
+func Regions() []Region {
+	all := regions
+	return all[:]
+}
@@ -0,0 +1,87 @@
+// Package apidocs serves an OpenAPI document and Swagger UI to browse it
+package apidocs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Path is where Swagger UI is served; the document is at Path/openapi.json and Path/openapi.yaml
+const Path = "/docs"
+
+// initializer points Swagger UI at the served document instead of the petstore example
+const initializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: "openapi.json",
+    dom_id: "#swagger-ui",
+    deepLinking: true,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+// Document is an OpenAPI document kept in both YAML and JSON
+type Document struct {
+	yaml []byte
+	json []byte
+}
+
+// New parses an OpenAPI document written in YAML
+func New(specYAML []byte) (*Document, error) {
+	var doc any
+	if err := yaml.Unmarshal(specYAML, &doc); err != nil {
+		return nil, fmt.Errorf("parsing OpenAPI document: %w", err)
+	}
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("encoding OpenAPI document as JSON: %w", err)
+	}
+	return &Document{yaml: specYAML, json: encoded}, nil
+}
+
+// MustNew is New for documents embedded at build time, panicking when they do not parse
+func MustNew(specYAML []byte) *Document {
+	d, err := New(specYAML)
+	if err != nil {
+		panic("apidocs: " + err.Error())
+	}
+	return d
+}
+
+// YAML returns the document as written
+func (d *Document) YAML() []byte {
+	return d.yaml
+}
+
+// JSON returns the document encoded as JSON
+func (d *Document) JSON() []byte {
+	return d.json
+}
+
+// Register serves Swagger UI and the document under Path
+func (d *Document) Register(router gin.IRouter) {
+	assets := http.StripPrefix(Path, http.FileServer(http.FS(swaggerFiles.FS)))
+
+	router.GET(Path, func(c *gin.Context) {
+		c.Redirect(http.StatusMovedPermanently, Path+"/")
+	})
+	router.GET(Path+"/*file", func(c *gin.Context) {
+		switch c.Param("file") {
+		case "/openapi.json":
+			c.Data(http.StatusOK, "application/json", d.json)
+		case "/openapi.yaml":
+			c.Data(http.StatusOK, "application/yaml", d.yaml)
+		case "/swagger-initializer.js":
+			c.Data(http.StatusOK, "text/javascript; charset=utf-8", []byte(initializer))
+		default:
+			assets.ServeHTTP(c.Writer, c.Request)
+		}
+	})
+}
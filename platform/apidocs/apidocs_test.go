@@ -0,0 +1,89 @@
+package apidocs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const specYAML = "openapi: 3.0.3\ninfo:\n  title: test\n  version: \"1.0\"\npaths: {}\n"
+
+func setupRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	doc, err := New([]byte(specYAML))
+	require.NoError(t, err)
+
+	router := gin.New()
+	doc.Register(router)
+	return router
+}
+
+func get(router http.Handler, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestNew(t *testing.T) {
+	// act
+	doc, err := New([]byte(specYAML))
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, specYAML, string(doc.YAML()))
+	assert.JSONEq(t, `{"openapi":"3.0.3","info":{"title":"test","version":"1.0"},"paths":{}}`, string(doc.JSON()))
+}
+
+func TestNew_InvalidYAML(t *testing.T) {
+	// act
+	_, err := New([]byte("openapi: [3.0.3"))
+
+	// assert
+	assert.Error(t, err)
+	assert.Panics(t, func() { MustNew([]byte("openapi: [3.0.3")) })
+}
+
+func TestRegister(t *testing.T) {
+	testCases := []struct {
+		name        string
+		target      string
+		status      int
+		contentType string
+		contains    string
+	}{
+		{"Documento em JSON", "/docs/openapi.json", http.StatusOK, "application/json", `"openapi":"3.0.3"`},
+		{"Documento em YAML", "/docs/openapi.yaml", http.StatusOK, "application/yaml", "openapi: 3.0.3"},
+		{"Swagger UI", "/docs/", http.StatusOK, "text/html", "swagger-ui"},
+		{"Inicialização aponta para o documento", "/docs/swagger-initializer.js", http.StatusOK, "text/javascript", `url: "openapi.json"`},
+		{"Recursos do Swagger UI", "/docs/swagger-ui-bundle.js", http.StatusOK, "text/javascript", "SwaggerUIBundle"},
+		{"Recurso inexistente", "/docs/missing.js", http.StatusNotFound, "", ""},
+	}
+
+	router := setupRouter(t)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			rec := get(router, tc.target)
+
+			// assert
+			assert.Equal(t, tc.status, rec.Code)
+			assert.Contains(t, rec.Header().Get("Content-Type"), tc.contentType)
+			assert.Contains(t, rec.Body.String(), tc.contains)
+		})
+	}
+}
+
+func TestRegister_RedirectsToUI(t *testing.T) {
+	// act
+	rec := get(setupRouter(t), "/docs")
+
+	// assert
+	assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+	assert.Equal(t, "/docs/", rec.Header().Get("Location"))
+}
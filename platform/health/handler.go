@@ -0,0 +1,55 @@
+package health
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatusResponse represents the health/readiness status response
+type StatusResponse struct {
+	Status    string             `json:"status" example:"healthy"`
+	Timestamp time.Time          `json:"timestamp" example:"2024-01-01T00:00:00Z"`
+	Service   string             `json:"service" example:"cep-gateway"`
+	Checks    []DependencyStatus `json:"checks,omitempty"`
+}
+
+// Handler serves the liveness and readiness probes of a service
+type Handler struct {
+	serviceName string
+	checker     *Checker
+}
+
+func NewHandler(serviceName string, checker *Checker) *Handler {
+	return &Handler{
+		serviceName: serviceName,
+		checker:     checker,
+	}
+}
+
+// Live reports that the process is up, without checking any dependency
+func (h *Handler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, StatusResponse{
+		Status:    StatusHealthy,
+		Timestamp: time.Now().UTC(),
+		Service:   h.serviceName,
+	})
+}
+
+// Ready runs the readiness checks, reporting the status and latency of each
+func (h *Handler) Ready(c *gin.Context) {
+	checks, ready := h.checker.Run(c.Request.Context())
+
+	status, code := StatusHealthy, http.StatusOK
+	if !ready {
+		status, code = StatusUnhealthy, http.StatusServiceUnavailable
+	}
+
+	c.JSON(code, StatusResponse{
+		Status:    status,
+		Timestamp: time.Now().UTC(),
+		Service:   h.serviceName,
+		Checks:    checks,
+	})
+}
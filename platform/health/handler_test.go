@@ -0,0 +1,81 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveProbe(h *Handler, probe gin.HandlerFunc) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/probe", probe)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/probe", nil))
+	return rec
+}
+
+func TestHandler_Live(t *testing.T) {
+	// arrange
+	h := NewHandler("svc", NewChecker(time.Minute, time.Second, Check{Name: "db", Run: func(context.Context) error {
+		return errors.New("down")
+	}}))
+
+	// act
+	rec := serveProbe(h, h.Live)
+
+	// assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var body StatusResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, StatusHealthy, body.Status)
+	assert.Equal(t, "svc", body.Service)
+	assert.Empty(t, body.Checks, "liveness não consulta dependências")
+}
+
+func TestHandler_Ready(t *testing.T) {
+	up := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name           string
+		dependency     func(context.Context) error
+		expectedCode   int
+		expectedStatus string
+	}{
+		{"todas as dependências no ar", up, http.StatusOK, StatusHealthy},
+		{"uma dependência fora do ar", down, http.StatusServiceUnavailable, StatusUnhealthy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			h := NewHandler("svc", NewChecker(time.Minute, time.Second,
+				Check{Name: "exporter", Run: up},
+				Check{Name: "db", Run: tt.dependency},
+			))
+
+			// act
+			rec := serveProbe(h, h.Ready)
+
+			// assert
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			var body StatusResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tt.expectedStatus, body.Status)
+			require.Len(t, body.Checks, 2)
+			assert.Equal(t, "exporter", body.Checks[0].Name)
+			assert.Equal(t, StatusUp, body.Checks[0].Status)
+			assert.Equal(t, "db", body.Checks[1].Name)
+		})
+	}
+}
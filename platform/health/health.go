@@ -0,0 +1,186 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusHealthy   = "healthy"
+	StatusUnhealthy = "unhealthy"
+
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// DependencyStatus represents the outcome of one readiness check
+type DependencyStatus struct {
+	Name      string    `json:"name" example:"exporter"`
+	Status    string    `json:"status" example:"up"`
+	LatencyMs float64   `json:"latency_ms" example:"3.2"`
+	Error     string    `json:"error,omitempty" example:"connection refused"`
+	CheckedAt time.Time `json:"checked_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// Check probes a single dependency; a nil error means it is usable
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+type result struct {
+	status DependencyStatus
+	at     time.Time
+}
+
+// Checker runs the readiness checks concurrently. Each result is reused for ttl
+// so frequent probes do not turn into traffic against the upstream APIs.
+type Checker struct {
+	checks  []Check
+	ttl     time.Duration
+	timeout time.Duration
+	now     func() time.Time
+
+	mu       sync.Mutex
+	results  map[string]result
+	draining atomic.Bool
+}
+
+func NewChecker(ttl, timeout time.Duration, checks ...Check) *Checker {
+	return newCheckerWithClock(ttl, timeout, time.Now, checks...)
+}
+
+func newCheckerWithClock(ttl, timeout time.Duration, now func() time.Time, checks ...Check) *Checker {
+	return &Checker{
+		checks:  checks,
+		ttl:     ttl,
+		timeout: timeout,
+		now:     now,
+		results: make(map[string]result, len(checks)),
+	}
+}
+
+// Drain makes every following Run report the service as not ready, without running the checks
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Run returns the status of every dependency, in registration order, and whether all of them are up
+func (c *Checker) Run(ctx context.Context) ([]DependencyStatus, bool) {
+	if c.draining.Load() {
+		return []DependencyStatus{{
+			Name:      "shutdown",
+			Status:    StatusDown,
+			Error:     "draining in-flight requests",
+			CheckedAt: c.now(),
+		}}, false
+	}
+
+	statuses := make([]DependencyStatus, len(c.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Go(func() {
+			statuses[i] = c.run(ctx, check)
+		})
+	}
+	wg.Wait()
+
+	ready := true
+	for _, status := range statuses {
+		if status.Status != StatusUp {
+			ready = false
+		}
+	}
+	return statuses, ready
+}
+
+func (c *Checker) run(ctx context.Context, check Check) DependencyStatus {
+	c.mu.Lock()
+	cached, ok := c.results[check.Name]
+	c.mu.Unlock()
+	if ok && c.now().Sub(cached.at) < c.ttl {
+		return cached.status
+	}
+
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	start := c.now()
+	err := check.Run(ctx)
+	status := DependencyStatus{
+		Name:      check.Name,
+		Status:    StatusUp,
+		LatencyMs: float64(c.now().Sub(start).Microseconds()) / 1000,
+		CheckedAt: start,
+	}
+	if err != nil {
+		status.Status = StatusDown
+		status.Error = err.Error()
+	}
+
+	c.mu.Lock()
+	c.results[check.Name] = result{status: status, at: start}
+	c.mu.Unlock()
+
+	return status
+}
+
+// HTTP reports a dependency as reachable when a GET to target gets any non-5xx answer
+func HTTP(client *http.Client, target string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// Dial reports a dependency as reachable when a TCP connection to address succeeds
+func Dial(address string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
+
+// Address returns the host:port of rawURL, defaulting the port from the scheme
+func Address(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
@@ -0,0 +1,162 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func countingCheck(name string, err error, calls *int) Check {
+	return Check{Name: name, Run: func(context.Context) error {
+		*calls++
+		return err
+	}}
+}
+
+func TestChecker_Run(t *testing.T) {
+	// arrange
+	var upCalls, downCalls int
+	checker := NewChecker(time.Minute, time.Second,
+		countingCheck("weather-engine", nil, &upCalls),
+		countingCheck("exporter", errors.New("connection refused"), &downCalls),
+	)
+
+	// act
+	statuses, ready := checker.Run(context.Background())
+
+	// assert
+	assert.False(t, ready)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "weather-engine", statuses[0].Name)
+	assert.Equal(t, StatusUp, statuses[0].Status)
+	assert.Empty(t, statuses[0].Error)
+	assert.Equal(t, "exporter", statuses[1].Name)
+	assert.Equal(t, StatusDown, statuses[1].Status)
+	assert.Equal(t, "connection refused", statuses[1].Error)
+}
+
+func TestChecker_CachesResults(t *testing.T) {
+	// arrange
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var calls int
+	checker := newCheckerWithClock(30*time.Second, time.Second, func() time.Time { return now },
+		countingCheck("weather-engine", nil, &calls))
+
+	// act & assert
+	_, ready := checker.Run(context.Background())
+	assert.True(t, ready)
+	assert.Equal(t, 1, calls)
+
+	now = now.Add(10 * time.Second)
+	checker.Run(context.Background())
+	assert.Equal(t, 1, calls, "resultado dentro do ttl deve ser reaproveitado")
+
+	now = now.Add(30 * time.Second)
+	checker.Run(context.Background())
+	assert.Equal(t, 2, calls, "resultado expirado deve ser verificado de novo")
+}
+
+func TestChecker_Timeout(t *testing.T) {
+	// arrange
+	checker := NewChecker(0, 10*time.Millisecond, Check{Name: "slow", Run: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+
+	// act
+	statuses, ready := checker.Run(context.Background())
+
+	// assert
+	assert.False(t, ready)
+	assert.Equal(t, StatusDown, statuses[0].Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), statuses[0].Error)
+}
+
+func TestChecker_Drain(t *testing.T) {
+	// arrange
+	var calls int
+	checker := NewChecker(time.Minute, time.Second, countingCheck("config", nil, &calls))
+
+	// act
+	checker.Drain()
+	statuses, ready := checker.Run(context.Background())
+
+	// assert
+	assert.False(t, ready)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, "shutdown", statuses[0].Name)
+	assert.Equal(t, StatusDown, statuses[0].Status)
+	assert.Zero(t, calls)
+}
+
+func TestHTTP(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{"ok", http.StatusOK, false},
+		{"não autorizado ainda é alcançável", http.StatusUnauthorized, false},
+		{"erro do servidor", http.StatusServiceUnavailable, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			// act
+			err := HTTP(server.Client(), server.URL)(context.Background())
+
+			// assert
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDial(t *testing.T) {
+	// arrange
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+
+	// act & assert
+	assert.NoError(t, Dial(address)(context.Background()))
+
+	require.NoError(t, listener.Close())
+	assert.Error(t, Dial(address)(context.Background()))
+}
+
+func TestAddress(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"http://otel-collector:4318", "otel-collector:4318"},
+		{"http://localhost:9411/api/v2/spans", "localhost:9411"},
+		{"http://weather-engine:8081", "weather-engine:8081"},
+		{"http://cep-gateway.example.com/", "cep-gateway.example.com:80"},
+		{"https://viacep.com.br/ws/", "viacep.com.br:443"},
+		{"https://cep-gateway.example.com/", "cep-gateway.example.com:443"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Address(tc.input))
+		})
+	}
+}
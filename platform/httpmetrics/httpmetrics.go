@@ -0,0 +1,86 @@
+package httpmetrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// DurationBuckets are expressed in seconds
+var DurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// AttributeFunc derives an extra attribute from the request context, e.g. the tenant
+type AttributeFunc func(ctx context.Context) attribute.KeyValue
+
+// Instruments holds the request instruments every HTTP service records.
+// A nil *Instruments is valid and records nothing.
+type Instruments struct {
+	requests        metric.Int64Counter
+	errors          metric.Int64Counter
+	requestDuration metric.Float64Histogram
+	extra           []AttributeFunc
+}
+
+// New creates the request instruments from the given meter; extra attributes are added to every measurement
+func New(meter metric.Meter, extra ...AttributeFunc) (*Instruments, error) {
+	i := Instruments{extra: extra}
+	var err error
+
+	if i.requests, err = meter.Int64Counter("app.requests",
+		metric.WithDescription("Number of HTTP requests handled"),
+		metric.WithUnit("{request}")); err != nil {
+		return nil, err
+	}
+	if i.errors, err = meter.Int64Counter("app.errors",
+		metric.WithDescription("Number of HTTP requests answered with a 5xx status"),
+		metric.WithUnit("{request}")); err != nil {
+		return nil, err
+	}
+	if i.requestDuration, err = meter.Float64Histogram("app.request.duration",
+		metric.WithDescription("Duration of HTTP requests"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(DurationBuckets...)); err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// Middleware records request count, error count and duration per route, method and status
+func (i *Instruments) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if i == nil {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := c.Writer.Status()
+
+		ctx := c.Request.Context()
+		kvs := make([]attribute.KeyValue, 0, len(i.extra)+3)
+		for _, extra := range i.extra {
+			kvs = append(kvs, extra(ctx))
+		}
+		kvs = append(kvs,
+			attribute.String("http.route", route),
+			attribute.String("http.request.method", c.Request.Method),
+			attribute.Int("http.response.status_code", status),
+		)
+		attrs := metric.WithAttributes(kvs...)
+
+		i.requests.Add(ctx, 1, attrs)
+		i.requestDuration.Record(ctx, time.Since(start).Seconds(), attrs)
+		if status >= 500 {
+			i.errors.Add(ctx, 1, attrs)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package httpmetrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Metrics {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	result := make(map[string]metricdata.Metrics)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			result[m.Name] = m
+		}
+	}
+	return result
+}
+
+func attributeValue(set attribute.Set, key string) string {
+	value, _ := set.Value(attribute.Key(key))
+	return value.Emit()
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// arrange
+	reader := sdkmetric.NewManualReader()
+	instruments, err := New(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test"),
+		func(context.Context) attribute.KeyValue { return attribute.String("tenant", "acme") })
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(instruments.Middleware())
+	router.GET("/api/v1/temperature/:cep", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/api/v1/fail", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+	// act
+	for _, target := range []string{"/api/v1/temperature/01001000", "/api/v1/temperature/20040020", "/api/v1/fail", "/unknown"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	// assert
+	collected := collect(t, reader)
+
+	requests := collected["app.requests"].Data.(metricdata.Sum[int64])
+	counts := make(map[string]int64)
+	for _, dp := range requests.DataPoints {
+		assert.Equal(t, "acme", attributeValue(dp.Attributes, "tenant"), "atributo extra em toda medição")
+		counts[attributeValue(dp.Attributes, "http.route")+" "+attributeValue(dp.Attributes, "http.response.status_code")] = dp.Value
+	}
+	assert.Equal(t, map[string]int64{
+		"/api/v1/temperature/:cep 200": 2,
+		"/api/v1/fail 500":             1,
+		"unmatched 404":                1,
+	}, counts)
+
+	errorsSum := collected["app.errors"].Data.(metricdata.Sum[int64])
+	require.Len(t, errorsSum.DataPoints, 1)
+	assert.Equal(t, "/api/v1/fail", attributeValue(errorsSum.DataPoints[0].Attributes, "http.route"))
+	assert.Equal(t, int64(1), errorsSum.DataPoints[0].Value)
+
+	duration := collected["app.request.duration"].Data.(metricdata.Histogram[float64])
+	assert.Len(t, duration.DataPoints, 3)
+}
+
+func TestNilInstruments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// arrange
+	var instruments *Instruments
+	router := gin.New()
+	router.Use(instruments.Middleware())
+	router.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+	w := httptest.NewRecorder()
+
+	// act & assert
+	assert.NotPanics(t, func() {
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ok", nil))
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
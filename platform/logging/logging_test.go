@@ -0,0 +1,179 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordingExporter keeps every exported log record in memory
+type recordingExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *recordingExporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (e *recordingExporter) Shutdown(context.Context) error   { return nil }
+func (e *recordingExporter) ForceFlush(context.Context) error { return nil }
+
+func spanContext() context.Context {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10},
+		SpanID:     trace.SpanID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+		TraceFlags: trace.FlagsSampled,
+	})
+	return trace.ContextWithSpanContext(context.Background(), sc)
+}
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var lines []map[string]any
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		var line map[string]any
+		require.NoError(t, decoder.Decode(&line))
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected slog.Level
+		wantErr  bool
+	}{
+		{"debug", "debug", slog.LevelDebug, false},
+		{"info", "info", slog.LevelInfo, false},
+		{"warn maiúsculo", "WARN", slog.LevelWarn, false},
+		{"error", "error", slog.LevelError, false},
+		{"nível inválido", "verbose", slog.LevelInfo, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// act
+			level, err := ParseLevel(tt.input)
+
+			// assert
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, level)
+		})
+	}
+}
+
+func TestNew_AddsTraceAndSpanIDs(t *testing.T) {
+	// arrange
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo, "logging-test", nil)
+
+	// act
+	logger.InfoContext(spanContext(), "with span")
+	logger.Info("without span")
+
+	// assert
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "with span", lines[0]["msg"])
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", lines[0]["trace_id"])
+	assert.Equal(t, "0102030405060708", lines[0]["span_id"])
+	assert.NotContains(t, lines[1], "trace_id")
+	assert.NotContains(t, lines[1], "span_id")
+}
+
+func TestNew_RespectsLevel(t *testing.T) {
+	// arrange
+	var buf bytes.Buffer
+	exporter := &recordingExporter{}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter)))
+	logger := New(&buf, slog.LevelWarn, "logging-test", provider)
+
+	// act
+	logger.Info("dropped")
+	logger.Warn("kept")
+
+	// assert
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "kept", lines[0]["msg"])
+	require.Len(t, exporter.records, 1)
+	assert.Equal(t, "kept", exporter.records[0].Body().AsString())
+}
+
+func TestNew_ExportsThroughBridge(t *testing.T) {
+	// arrange
+	var buf bytes.Buffer
+	exporter := &recordingExporter{}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter)))
+	logger := New(&buf, slog.LevelInfo, "logging-test", provider).With("component", "test")
+
+	// act
+	logger.ErrorContext(spanContext(), "upstream failed", "cep", "01001000")
+
+	// assert
+	require.Len(t, exporter.records, 1)
+	record := exporter.records[0]
+	assert.Equal(t, "upstream failed", record.Body().AsString())
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", record.TraceID().String())
+	assert.Equal(t, "0102030405060708", record.SpanID().String())
+	assert.Equal(t, "logging-test", record.InstrumentationScope().Name)
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "test", lines[0]["component"])
+	assert.Equal(t, "01001000", lines[0]["cep"])
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// arrange
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(New(&buf, slog.LevelInfo, "logging-test", nil))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(spanContext())
+		c.Next()
+	}, Middleware())
+	router.GET("/api/v1/temperature/:cep", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+	// act
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/temperature/01001000", nil))
+
+	// assert
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "ERROR", lines[0]["level"])
+	assert.Equal(t, "request completed", lines[0]["msg"])
+	assert.Equal(t, "/api/v1/temperature/:cep", lines[0]["route"])
+	assert.Equal(t, float64(http.StatusInternalServerError), lines[0]["status"])
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", lines[0]["trace_id"])
+}
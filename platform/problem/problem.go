@@ -0,0 +1,108 @@
+// Package problem builds RFC 7807 problem details for error responses and renders them, or
+// the legacy {"message": ...} body for clients that have not migrated yet.
+package problem
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Media types of problem responses
+const (
+	ContentType    = "application/problem+json"
+	ContentTypeXML = "application/problem+xml"
+)
+
+// TypeBase is prefixed to a problem code to form its type; the types are documented there
+const TypeBase = "https://github.com/alexduzi/laboteldistributedtracing/blob/main/docs/problems.md#"
+
+const legacyKey = "problem.legacy"
+
+// Problem represents an RFC 7807 problem details error response. Code and Details are
+// extension members: the problem code and, when a request failed validation, what failed.
+type Problem struct {
+	XMLName  xml.Name      `json:"-" xml:"urn:ietf:rfc:7807 problem"`
+	Type     string        `json:"type" xml:"type" example:"https://github.com/alexduzi/laboteldistributedtracing/blob/main/docs/problems.md#invalid_zipcode"`
+	Title    string        `json:"title" xml:"title" example:"Unprocessable Entity"`
+	Status   int           `json:"status" xml:"status" example:"422"`
+	Detail   string        `json:"detail,omitempty" xml:"detail,omitempty" example:"invalid zipcode"`
+	Instance string        `json:"instance,omitempty" xml:"instance,omitempty" example:"/api/v1/temperature/0131010"`
+	TraceID  string        `json:"trace_id,omitempty" xml:"trace_id,omitempty" example:"4bf92f3577b34da6a3ce929d0e0e4736"`
+	Code     string        `json:"code" xml:"code" example:"invalid_zipcode"`
+	Details  []ErrorDetail `json:"details,omitempty" xml:"details>detail,omitempty"`
+}
+
+// ErrorDetail represents one validation failure of a request
+type ErrorDetail struct {
+	// Field is the JSON path of the body field, the path parameter or the header that failed
+	Field   string `json:"field" xml:"field" example:"ceps"`
+	Code    string `json:"code" xml:"code" example:"required"`
+	Message string `json:"message" xml:"message" example:"must contain at least one cep"`
+	// Offset is the byte of the body where it stopped being valid JSON, for syntax failures
+	Offset int64 `json:"offset,omitempty" xml:"offset,omitempty" example:"9"`
+}
+
+// ErrorResponse represents an error response in the legacy format
+type ErrorResponse struct {
+	Message string `json:"message" xml:"message" example:"invalid zipcode"`
+}
+
+// New returns the problem of type code answered with status
+func New(status int, code, detail string, details ...ErrorDetail) Problem {
+	return Problem{
+		Type:    TypeBase + code,
+		Title:   http.StatusText(status),
+		Status:  status,
+		Detail:  detail,
+		Code:    code,
+		Details: details,
+	}
+}
+
+// ErrorResponse returns p in the legacy format, which only keeps its detail
+func (p Problem) ErrorResponse() ErrorResponse {
+	return ErrorResponse{Message: p.Detail}
+}
+
+// Middleware renders the errors of the requests it serves in the legacy format when legacy is set
+func Middleware(legacy bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(legacyKey, legacy)
+		c.Next()
+	}
+}
+
+// Legacy reports whether the errors of the request are rendered as ErrorResponse
+func Legacy(c *gin.Context) bool {
+	return c.GetBool(legacyKey)
+}
+
+// ForRequest returns p naming the request path as its instance and carrying the id of the current trace
+func ForRequest(c *gin.Context, p Problem) Problem {
+	p.Instance = c.Request.URL.Path
+	if sc := trace.SpanContextFromContext(c.Request.Context()); sc.HasTraceID() {
+		p.TraceID = sc.TraceID().String()
+	}
+	return p
+}
+
+// Write renders p for the request as application/problem+json, or as an ErrorResponse
+// holding its detail when the request is served in the legacy format
+func Write(c *gin.Context, p Problem) {
+	if Legacy(c) {
+		c.JSON(p.Status, p.ErrorResponse())
+		return
+	}
+
+	c.Header("Content-Type", ContentType)
+	c.JSON(p.Status, ForRequest(c, p))
+}
+
+// Abort renders p like Write and stops the remaining handlers
+func Abort(c *gin.Context, p Problem) {
+	c.Abort()
+	Write(c, p)
+}
@@ -0,0 +1,66 @@
+package problem
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRouter(legacy bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware(legacy))
+	router.GET("/error", func(c *gin.Context) {
+		Abort(c, New(http.StatusUnprocessableEntity, "validation_failed", "request failed validation",
+			ErrorDetail{Field: "ceps", Code: "required", Message: "is required"}))
+	})
+	return router
+}
+
+func TestNew(t *testing.T) {
+	// act
+	p := New(http.StatusNotFound, "zipcode_not_found", "can not find zipcode")
+
+	// assert
+	assert.Equal(t, TypeBase+"zipcode_not_found", p.Type)
+	assert.Equal(t, "Not Found", p.Title)
+	assert.Equal(t, http.StatusNotFound, p.Status)
+	assert.Equal(t, "can not find zipcode", p.Detail)
+	assert.Equal(t, "zipcode_not_found", p.Code)
+	assert.Empty(t, p.Details)
+}
+
+func TestWrite(t *testing.T) {
+	testCases := []struct {
+		name        string
+		legacy      bool
+		contentType string
+		body        string
+	}{
+		{"Problem details", false, ContentType,
+			`{"type":"` + TypeBase + `validation_failed","title":"Unprocessable Entity","status":422,` +
+				`"detail":"request failed validation","instance":"/error","code":"validation_failed",` +
+				`"details":[{"field":"ceps","code":"required","message":"is required"}]}`},
+		{"Formato legado", true, gin.MIMEJSON, `{"message":"request failed validation"}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			router := setupRouter(tc.legacy)
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/error", nil)
+
+			// act
+			router.ServeHTTP(rec, req)
+
+			// assert
+			assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+			assert.Contains(t, rec.Header().Get("Content-Type"), tc.contentType)
+			assert.JSONEq(t, tc.body, rec.Body.String(), "o formato legado deve conter apenas a mensagem")
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Signals start a graceful shutdown
+var Signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// ShutdownFunc stops a component, giving up when ctx is done
+type ShutdownFunc func(ctx context.Context) error
+
+// Graceful serves HTTP until SIGINT or SIGTERM and then shuts down in order:
+// readiness fails, in-flight requests drain within GracePeriod, and finally
+// the telemetry providers are flushed.
+type Graceful struct {
+	Server      *http.Server
+	GracePeriod time.Duration
+	// NotReady is called as soon as shutdown starts so load balancers stop routing here
+	NotReady func()
+	// Drain stops other components concurrently with the HTTP server
+	Drain []ShutdownFunc
+	// Flush runs in order once every server has drained
+	Flush []ShutdownFunc
+}
+
+// Serve accepts connections on listener until ctx is done or a shutdown signal arrives
+func (g *Graceful) Serve(ctx context.Context, listener net.Listener) error {
+	ctx, stop := signal.NotifyContext(ctx, Signals...)
+	defer stop()
+
+	served := make(chan error, 1)
+	go func() {
+		served <- g.Server.Serve(listener)
+	}()
+
+	var errs []error
+	select {
+	case err := <-served:
+		errs = append(errs, err)
+	case <-ctx.Done():
+		// a second signal terminates the process right away
+		stop()
+		slog.Info("Shutting down", "grace_period", g.GracePeriod)
+	}
+
+	if g.NotReady != nil {
+		g.NotReady()
+	}
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), g.GracePeriod)
+	defer cancel()
+	errs = append(errs, g.drain(drainCtx))
+
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), g.GracePeriod)
+	defer cancelFlush()
+	for _, flush := range g.Flush {
+		errs = append(errs, flush(flushCtx))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (g *Graceful) drain(ctx context.Context) error {
+	drains := append([]ShutdownFunc{g.Server.Shutdown}, g.Drain...)
+	errs := make([]error, len(drains))
+
+	var wg sync.WaitGroup
+	for i, drain := range drains {
+		wg.Go(func() {
+			errs[i] = drain(ctx)
+		})
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
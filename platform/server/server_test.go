@@ -0,0 +1,180 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// recordingExporter keeps the exported spans after shutdown, unlike tracetest.InMemoryExporter
+type recordingExporter struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (e *recordingExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, span := range spans {
+		e.names = append(e.names, span.Name())
+	}
+	return nil
+}
+
+func (e *recordingExporter) Shutdown(context.Context) error { return nil }
+
+func (e *recordingExporter) Names() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.names...)
+}
+
+// setupSlowServer returns a router that calls a stand-in upstream which takes
+// delay to answer, and a channel closed once the upstream call is in flight
+func setupSlowServer(t *testing.T, tp *sdktrace.TracerProvider, delay time.Duration) (*gin.Engine, <-chan struct{}) {
+	gin.SetMode(gin.TestMode)
+
+	inFlight := make(chan struct{})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(inFlight)
+		time.Sleep(delay)
+		_, _ = io.WriteString(w, "ok")
+	}))
+	t.Cleanup(upstream.Close)
+
+	router := gin.New()
+	router.Use(otelgin.Middleware("cep-gateway-test", otelgin.WithTracerProvider(tp)))
+	router.GET("/slow", func(c *gin.Context) {
+		ctx, span := tp.Tracer("test").Start(c.Request.Context(), "slow upstream")
+		defer span.End()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, upstream.URL, nil)
+		require.NoError(t, err)
+		resp, err := upstream.Client().Do(req)
+		if err != nil {
+			c.Status(http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		c.String(http.StatusOK, string(body))
+	})
+	return router, inFlight
+}
+
+func sendSignal(t *testing.T, sig os.Signal) {
+	t.Helper()
+
+	process, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, process.Signal(sig))
+}
+
+func TestGraceful_DrainsInFlightRequestOnSignal(t *testing.T) {
+	// arrange
+	exporter := &recordingExporter{}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+	router, inFlight := setupSlowServer(t, tp, 200*time.Millisecond)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := "http://" + listener.Addr().String()
+
+	var notReady atomic.Bool
+	graceful := &Graceful{
+		Server:      &http.Server{Handler: router},
+		GracePeriod: 5 * time.Second,
+		NotReady:    func() { notReady.Store(true) },
+		Flush:       []ShutdownFunc{tp.Shutdown},
+	}
+
+	served := make(chan error, 1)
+	go func() { served <- graceful.Serve(context.Background(), listener) }()
+
+	type response struct {
+		status int
+		body   string
+		err    error
+	}
+	responses := make(chan response, 1)
+	go func() {
+		resp, err := http.Get(address + "/slow")
+		if err != nil {
+			responses <- response{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		responses <- response{status: resp.StatusCode, body: string(body)}
+	}()
+
+	// act
+	<-inFlight
+	sendSignal(t, syscall.SIGTERM)
+
+	// assert
+	select {
+	case err := <-served:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("servidor não terminou o shutdown")
+	}
+
+	res := <-responses
+	require.NoError(t, res.err)
+	assert.Equal(t, http.StatusOK, res.status)
+	assert.Equal(t, "ok", res.body)
+	assert.True(t, notReady.Load())
+
+	assert.Contains(t, exporter.Names(), "GET /slow", "span do servidor deve ser exportado")
+	assert.Contains(t, exporter.Names(), "slow upstream", "span da chamada ao upstream deve ser exportado")
+
+	_, err = http.Get(address + "/slow")
+	assert.Error(t, err, "novas conexões devem ser recusadas")
+}
+
+func TestGraceful_GracePeriodExceeded(t *testing.T) {
+	// arrange
+	tp := sdktrace.NewTracerProvider()
+	router, inFlight := setupSlowServer(t, tp, time.Second)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	var flushed atomic.Bool
+	graceful := &Graceful{
+		Server:      &http.Server{Handler: router},
+		GracePeriod: 50 * time.Millisecond,
+		Flush: []ShutdownFunc{func(context.Context) error {
+			flushed.Store(true)
+			return nil
+		}},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- graceful.Serve(ctx, listener) }()
+	go func() { _, _ = http.Get("http://" + listener.Addr().String() + "/slow") }()
+
+	// act
+	<-inFlight
+	cancel()
+
+	// assert
+	assert.ErrorIs(t, <-served, context.DeadlineExceeded)
+	assert.True(t, flushed.Load(), "telemetria deve ser descarregada mesmo após estourar o prazo")
+}
@@ -0,0 +1,225 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/alexduzi/laboteldistributedtracing/platform/tracing"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/runtime"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/propagation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+// Metrics modes accepted in METRICS_MODE
+const (
+	MetricsModePush = "push"
+	MetricsModePull = "pull"
+)
+
+// Config selects how a service exports its traces, metrics and logs
+type Config struct {
+	ServiceName string
+	// TracesExporter selects the span exporter, see tracing.ExporterConfig
+	TracesExporter string
+	// Protocol and Endpoint select the OTLP receiver of all three signals, see tracing.ExporterConfig
+	Protocol       string
+	Endpoint       string
+	ZipkinEndpoint string
+	// TracesSampler and TracesSamplerArg select the head sampler, see tracing.NewSampler
+	TracesSampler    string
+	TracesSamplerArg float64
+	// MetricsMode is MetricsModePush or MetricsModePull; empty means push
+	MetricsMode string
+}
+
+// ShutdownFunc flushes and releases the telemetry providers
+type ShutdownFunc func(ctx context.Context) error
+
+// InitTracerProvider configures the global TracerProvider exporting spans through the exporter selected in cfg.TracesExporter
+func InitTracerProvider(ctx context.Context, cfg Config) (ShutdownFunc, error) {
+	exporter, err := tracing.NewExporter(ctx, tracing.ExporterConfig{
+		Exporter:       cfg.TracesExporter,
+		Protocol:       cfg.Protocol,
+		Endpoint:       cfg.Endpoint,
+		ZipkinEndpoint: cfg.ZipkinEndpoint,
+	}, os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := NewResource(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	sampler, err := tracing.NewSampler(cfg.TracesSampler, cfg.TracesSamplerArg)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sampler),
+		sdktrace.WithResource(res),
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithSpanProcessor(tracing.ErrorSpanProcessor(sdktrace.NewBatchSpanProcessor(exporter))))
+	}
+
+	tp := sdktrace.NewTracerProvider(opts...)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return tp.Shutdown, nil
+}
+
+// InitMeterProvider configures the global MeterProvider according to cfg.MetricsMode.
+// In push mode metrics go to the OTLP collector over cfg.Protocol and the returned handler
+// is nil; in pull mode the handler serves them in the Prometheus exposition format.
+func InitMeterProvider(ctx context.Context, cfg Config) (ShutdownFunc, http.Handler, error) {
+	res, err := NewResource(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var reader sdkmetric.Reader
+	var handler http.Handler
+
+	switch cfg.MetricsMode {
+	case MetricsModePush, "":
+		exporter, err := newMetricExporter(ctx, cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		reader = sdkmetric.NewPeriodicReader(exporter)
+	case MetricsModePull:
+		registry := prometheus.NewRegistry()
+		if err := registry.Register(collectors.NewBuildInfoCollector()); err != nil {
+			return nil, nil, err
+		}
+		exporter, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
+		if err != nil {
+			return nil, nil, err
+		}
+		reader = exporter
+		// exemplars are only part of the OpenMetrics exposition format
+		handler = promhttp.HandlerFor(registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
+	default:
+		return nil, nil, fmt.Errorf("unknown metrics mode %q", cfg.MetricsMode)
+	}
+
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(reader),
+		sdkmetric.WithResource(res),
+		// measurements taken inside a sampled span carry its trace and span IDs as exemplars
+		sdkmetric.WithExemplarFilter(exemplar.TraceBasedFilter),
+	)
+
+	if err := runtime.Start(runtime.WithMeterProvider(mp)); err != nil {
+		return nil, nil, err
+	}
+
+	otel.SetMeterProvider(mp)
+
+	return mp.Shutdown, handler, nil
+}
+
+// InitLoggerProvider configures the global LoggerProvider exporting log records to the OTLP collector over cfg.Protocol
+func InitLoggerProvider(ctx context.Context, cfg Config) (ShutdownFunc, error) {
+	exporter, err := newLogExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := NewResource(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	lp := sdklog.NewLoggerProvider(
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+		sdklog.WithResource(res),
+	)
+
+	global.SetLoggerProvider(lp)
+
+	return lp.Shutdown, nil
+}
+
+// newMetricExporter builds the OTLP metric exporter for cfg.Protocol; like the span exporter,
+// OTEL_EXPORTER_OTLP_METRICS_ENDPOINT takes precedence over cfg.Endpoint
+func newMetricExporter(ctx context.Context, cfg Config) (sdkmetric.Exporter, error) {
+	_, signalEndpoint := os.LookupEnv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT")
+
+	switch cfg.Protocol {
+	case tracing.ProtocolHTTPProtobuf, "":
+		var opts []otlpmetrichttp.Option
+		if !signalEndpoint {
+			opts = append(opts, otlpmetrichttp.WithEndpointURL(strings.TrimSuffix(cfg.Endpoint, "/")+"/v1/metrics"))
+		}
+		return otlpmetrichttp.New(ctx, opts...)
+	case tracing.ProtocolGRPC:
+		var opts []otlpmetricgrpc.Option
+		if !signalEndpoint {
+			opts = append(opts, otlpmetricgrpc.WithEndpointURL(cfg.Endpoint))
+		}
+		return otlpmetricgrpc.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unknown OTLP protocol %q", cfg.Protocol)
+	}
+}
+
+// newLogExporter builds the OTLP log exporter for cfg.Protocol; like the span exporter,
+// OTEL_EXPORTER_OTLP_LOGS_ENDPOINT takes precedence over cfg.Endpoint
+func newLogExporter(ctx context.Context, cfg Config) (sdklog.Exporter, error) {
+	_, signalEndpoint := os.LookupEnv("OTEL_EXPORTER_OTLP_LOGS_ENDPOINT")
+
+	switch cfg.Protocol {
+	case tracing.ProtocolHTTPProtobuf, "":
+		var opts []otlploghttp.Option
+		if !signalEndpoint {
+			opts = append(opts, otlploghttp.WithEndpointURL(strings.TrimSuffix(cfg.Endpoint, "/")+"/v1/logs"))
+		}
+		return otlploghttp.New(ctx, opts...)
+	case tracing.ProtocolGRPC:
+		var opts []otlploggrpc.Option
+		if !signalEndpoint {
+			opts = append(opts, otlploggrpc.WithEndpointURL(cfg.Endpoint))
+		}
+		return otlploggrpc.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unknown OTLP protocol %q", cfg.Protocol)
+	}
+}
+
+// NewResource describes the running service for every exported signal
+func NewResource(cfg Config) (*resource.Resource, error) {
+	return resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(cfg.ServiceName),
+		),
+	)
+}
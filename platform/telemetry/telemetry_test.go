@@ -0,0 +1,244 @@
+package telemetry
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/alexduzi/laboteldistributedtracing/platform/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/grpc"
+)
+
+// fakeCollector stands in for a collector receiving OTLP/gRPC metrics and logs
+type fakeCollector struct {
+	collectormetrics.UnimplementedMetricsServiceServer
+
+	mu      sync.Mutex
+	metrics []string
+	logs    []string
+}
+
+func (c *fakeCollector) Export(_ context.Context, req *collectormetrics.ExportMetricsServiceRequest) (*collectormetrics.ExportMetricsServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, rm := range req.GetResourceMetrics() {
+		for _, sm := range rm.GetScopeMetrics() {
+			for _, m := range sm.GetMetrics() {
+				c.metrics = append(c.metrics, m.GetName())
+			}
+		}
+	}
+	return &collectormetrics.ExportMetricsServiceResponse{}, nil
+}
+
+// logsService adapts fakeCollector to the logs service, whose Export has another signature
+type logsService struct {
+	collectorlogs.UnimplementedLogsServiceServer
+	*fakeCollector
+}
+
+func (s logsService) Export(_ context.Context, req *collectorlogs.ExportLogsServiceRequest) (*collectorlogs.ExportLogsServiceResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, rl := range req.GetResourceLogs() {
+		for _, sl := range rl.GetScopeLogs() {
+			for _, record := range sl.GetLogRecords() {
+				s.logs = append(s.logs, record.GetBody().GetStringValue())
+			}
+		}
+	}
+	return &collectorlogs.ExportLogsServiceResponse{}, nil
+}
+
+// newGRPCCollector serves a fakeCollector and returns the endpoint URL to reach it
+func newGRPCCollector(t *testing.T) (*fakeCollector, string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	collector := &fakeCollector{}
+	server := grpc.NewServer()
+	collectormetrics.RegisterMetricsServiceServer(server, collector)
+	collectorlogs.RegisterLogsServiceServer(server, logsService{fakeCollector: collector})
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	return collector, "http://" + listener.Addr().String()
+}
+
+func TestInitMeterProvider_PullMode(t *testing.T) {
+	// arrange
+	ctx := context.Background()
+	cfg := Config{ServiceName: "telemetry-test", MetricsMode: MetricsModePull}
+
+	shutdown, handler, err := InitMeterProvider(ctx, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, handler)
+	t.Cleanup(func() { _ = shutdown(ctx) })
+
+	counter, err := otel.Meter("test").Int64Counter("app.requests")
+	require.NoError(t, err)
+	counter.Add(ctx, 1)
+
+	// act
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "go_build_info")
+	assert.Contains(t, body, "go_goroutine_count")
+	assert.Contains(t, body, "app_requests_total")
+	assert.Contains(t, body, `service_name="telemetry-test"`)
+}
+
+func TestInitMeterProvider_PullModeExemplars(t *testing.T) {
+	// arrange
+	ctx := context.Background()
+	cfg := Config{ServiceName: "telemetry-test", MetricsMode: MetricsModePull}
+
+	shutdown, handler, err := InitMeterProvider(ctx, cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = shutdown(ctx) })
+
+	histogram, err := otel.Meter("test").Float64Histogram("app.upstream.duration")
+	require.NoError(t, err)
+
+	spanCtx, span := sdktrace.NewTracerProvider().Tracer("test").Start(ctx, "request")
+	histogram.Record(spanCtx, 0.02)
+	span.End()
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text")
+
+	// act
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `trace_id="`+span.SpanContext().TraceID().String()+`"`)
+}
+
+func TestInitMeterProvider_PushMode(t *testing.T) {
+	// arrange
+	ctx := context.Background()
+	cfg := Config{
+		ServiceName: "telemetry-test",
+		Endpoint:    "http://localhost:4318",
+		MetricsMode: MetricsModePush,
+	}
+
+	// act
+	shutdown, handler, err := InitMeterProvider(ctx, cfg)
+
+	// assert
+	require.NoError(t, err)
+	assert.Nil(t, handler)
+	assert.NotNil(t, shutdown)
+}
+
+func TestInitMeterProvider_UnknownMode(t *testing.T) {
+	// arrange
+	cfg := Config{ServiceName: "telemetry-test", MetricsMode: "scrape"}
+
+	// act
+	shutdown, handler, err := InitMeterProvider(context.Background(), cfg)
+
+	// assert
+	assert.Error(t, err)
+	assert.Nil(t, shutdown)
+	assert.Nil(t, handler)
+}
+
+func TestInitTracerProvider_NoneExporter(t *testing.T) {
+	// arrange
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	cfg := Config{ServiceName: "telemetry-test", TracesExporter: tracing.ExporterNone}
+
+	// act
+	shutdown, err := InitTracerProvider(context.Background(), cfg)
+	require.NoError(t, err)
+
+	_, span := otel.Tracer("test").Start(context.Background(), "not exported")
+	span.End()
+
+	// assert
+	assert.True(t, span.SpanContext().IsValid())
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func TestInitMeterProvider_PushModeGRPC(t *testing.T) {
+	// arrange
+	ctx := context.Background()
+	collector, endpoint := newGRPCCollector(t)
+	cfg := Config{
+		ServiceName: "telemetry-test",
+		Protocol:    tracing.ProtocolGRPC,
+		Endpoint:    endpoint,
+		MetricsMode: MetricsModePush,
+	}
+
+	shutdown, _, err := InitMeterProvider(ctx, cfg)
+	require.NoError(t, err)
+
+	counter, err := otel.Meter("test").Int64Counter("app.requests")
+	require.NoError(t, err)
+	counter.Add(ctx, 1)
+
+	// act
+	require.NoError(t, shutdown(ctx), "o shutdown envia as métricas pendentes")
+
+	// assert
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	assert.Contains(t, collector.metrics, "app.requests", "as métricas devem chegar ao coletor por OTLP/gRPC")
+}
+
+func TestInitLoggerProvider_GRPC(t *testing.T) {
+	// arrange
+	ctx := context.Background()
+	collector, endpoint := newGRPCCollector(t)
+	cfg := Config{ServiceName: "telemetry-test", Protocol: tracing.ProtocolGRPC, Endpoint: endpoint}
+
+	shutdown, err := InitLoggerProvider(ctx, cfg)
+	require.NoError(t, err)
+
+	var record otellog.Record
+	record.SetBody(otellog.StringValue("request failed"))
+	global.GetLoggerProvider().Logger("test").Emit(ctx, record)
+
+	// act
+	require.NoError(t, shutdown(ctx), "o shutdown envia os logs pendentes")
+
+	// assert
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	assert.Equal(t, []string{"request failed"}, collector.logs, "os logs devem chegar ao coletor por OTLP/gRPC")
+}
+
+func TestInitMeterProvider_UnknownProtocol(t *testing.T) {
+	// arrange
+	cfg := Config{ServiceName: "telemetry-test", Protocol: "http/json", MetricsMode: MetricsModePush}
+
+	// act
+	shutdown, _, err := InitMeterProvider(context.Background(), cfg)
+
+	// assert
+	assert.Error(t, err)
+	assert.Nil(t, shutdown)
+}
@@ -0,0 +1,87 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxFailedTraces bounds the traces remembered as failed while their local root is still running
+const maxFailedTraces = 10_000
+
+// ErrorSpanProcessor forwards to next the sampled spans and upgrades the record-only spans
+// that end with an error status, together with the local ancestors that end after them.
+//
+// This is a best-effort head decision: the sampling flag has already been propagated as
+// unsampled, so spans that ended before the error, downstream spans and spans of other
+// services are not kept. Collector tail sampling remains the way to keep whole error traces.
+func ErrorSpanProcessor(next sdktrace.SpanProcessor) sdktrace.SpanProcessor {
+	return &errorSpanProcessor{
+		next:   next,
+		failed: make(map[trace.TraceID]struct{}),
+	}
+}
+
+type errorSpanProcessor struct {
+	next sdktrace.SpanProcessor
+
+	mu     sync.Mutex
+	failed map[trace.TraceID]struct{}
+}
+
+func (p *errorSpanProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	p.next.OnStart(parent, s)
+}
+
+func (p *errorSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if s.SpanContext().IsSampled() {
+		p.next.OnEnd(s)
+		return
+	}
+	if p.upgrade(s) {
+		p.next.OnEnd(upgradedSpan{s})
+	}
+}
+
+// upgrade reports whether the record-only span s failed or has a failed descendant,
+// forgetting its trace once the local root ends
+func (p *errorSpanProcessor) upgrade(s sdktrace.ReadOnlySpan) bool {
+	traceID := s.SpanContext().TraceID()
+	localRoot := !s.Parent().IsValid() || s.Parent().IsRemote()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, failed := p.failed[traceID]
+	if s.Status().Code == codes.Error {
+		failed = true
+		if !localRoot && len(p.failed) < maxFailedTraces {
+			p.failed[traceID] = struct{}{}
+		}
+	}
+	if localRoot {
+		delete(p.failed, traceID)
+	}
+	return failed
+}
+
+func (p *errorSpanProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+func (p *errorSpanProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
+
+// upgradedSpan reports a record-only span as sampled so exporting processors keep it
+type upgradedSpan struct {
+	sdktrace.ReadOnlySpan
+}
+
+func (s upgradedSpan) SpanContext() trace.SpanContext {
+	sc := s.ReadOnlySpan.SpanContext()
+	return sc.WithTraceFlags(sc.TraceFlags().WithSampled(true))
+}
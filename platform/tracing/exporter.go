@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/exporters/zipkin"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Exporter names accepted in OTEL_TRACES_EXPORTER
+const (
+	ExporterOTLP    = "otlp"
+	ExporterZipkin  = "zipkin"
+	ExporterConsole = "console"
+	ExporterStdout  = "stdout"
+	ExporterNone    = "none"
+)
+
+// OTLP protocols accepted in OTEL_EXPORTER_OTLP_PROTOCOL
+const (
+	ProtocolGRPC         = "grpc"
+	ProtocolHTTPProtobuf = "http/protobuf"
+)
+
+// ExporterConfig selects where spans are exported
+type ExporterConfig struct {
+	// Exporter is one of the Exporter* names; empty means otlp
+	Exporter string
+	// Protocol is one of the Protocol* names; empty means http/protobuf
+	Protocol string
+	// Endpoint is the base URL of the OTLP receiver
+	Endpoint string
+	// ZipkinEndpoint is the full URL of the Zipkin spans API
+	ZipkinEndpoint string
+}
+
+// NewExporter builds the span exporter named in cfg.Exporter.
+// It returns a nil exporter for "none". Console output is written to w.
+// The OTLP exporters also honour the standard OTEL_EXPORTER_OTLP_* variables
+// (headers, timeout, compression, certificates); a signal specific
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT takes precedence over cfg.Endpoint.
+// Jaeger accepts OTLP natively, so it is reached through the otlp exporter.
+func NewExporter(ctx context.Context, cfg ExporterConfig, w io.Writer) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case ExporterOTLP, "":
+		return newOTLPExporter(ctx, cfg)
+	case ExporterZipkin:
+		return zipkin.New(cfg.ZipkinEndpoint)
+	case ExporterConsole, ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(w), stdouttrace.WithPrettyPrint())
+	case ExporterNone:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown traces exporter %q", cfg.Exporter)
+	}
+}
+
+func newOTLPExporter(ctx context.Context, cfg ExporterConfig) (sdktrace.SpanExporter, error) {
+	_, signalEndpoint := os.LookupEnv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+
+	switch cfg.Protocol {
+	case ProtocolHTTPProtobuf, "":
+		var opts []otlptracehttp.Option
+		if !signalEndpoint {
+			opts = append(opts, otlptracehttp.WithEndpointURL(strings.TrimSuffix(cfg.Endpoint, "/")+"/v1/traces"))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	case ProtocolGRPC:
+		var opts []otlptracegrpc.Option
+		if !signalEndpoint {
+			opts = append(opts, otlptracegrpc.WithEndpointURL(cfg.Endpoint))
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unknown OTLP protocol %q", cfg.Protocol)
+	}
+}
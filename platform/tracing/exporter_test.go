@@ -0,0 +1,207 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeTraceService stands in for a collector receiving OTLP/gRPC spans
+type fakeTraceService struct {
+	collectortrace.UnimplementedTraceServiceServer
+
+	mu    sync.Mutex
+	names []string
+}
+
+func (s *fakeTraceService) Export(_ context.Context, req *collectortrace.ExportTraceServiceRequest) (*collectortrace.ExportTraceServiceResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.names = append(s.names, spanNames(req)...)
+	return &collectortrace.ExportTraceServiceResponse{}, nil
+}
+
+func spanNames(req *collectortrace.ExportTraceServiceRequest) []string {
+	var names []string
+	for _, rs := range req.GetResourceSpans() {
+		for _, ss := range rs.GetScopeSpans() {
+			for _, span := range ss.GetSpans() {
+				names = append(names, span.GetName())
+			}
+		}
+	}
+	return names
+}
+
+// newOTLPHTTPServer stands in for a collector receiving OTLP/HTTP spans
+func newOTLPHTTPServer(t *testing.T) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var names []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+
+		var req collectortrace.ExportTraceServiceRequest
+		require.NoError(t, proto.Unmarshal(body, &req))
+
+		mu.Lock()
+		names = append(names, spanNames(&req)...)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, &names
+}
+
+// exportSpan sends one span named name through exporter and flushes it
+func exportSpan(t *testing.T, exporter sdktrace.SpanExporter, name string) {
+	t.Helper()
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	_, span := tp.Tracer("test").Start(context.Background(), name)
+	span.End()
+	require.NoError(t, tp.Shutdown(context.Background()))
+}
+
+func TestNewTraceExporter_OTLPHTTP(t *testing.T) {
+	// arrange
+	server, names := newOTLPHTTPServer(t)
+	cfg := ExporterConfig{Exporter: ExporterOTLP, Protocol: ProtocolHTTPProtobuf, Endpoint: server.URL}
+
+	// act
+	exporter, err := NewExporter(context.Background(), cfg, io.Discard)
+	require.NoError(t, err)
+	exportSpan(t, exporter, "otlp http span")
+
+	// assert
+	assert.Equal(t, []string{"otlp http span"}, *names)
+}
+
+func TestNewTraceExporter_OTLPHTTPSignalEndpoint(t *testing.T) {
+	// arrange
+	server, names := newOTLPHTTPServer(t)
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", server.URL+"/v1/traces")
+	cfg := ExporterConfig{Exporter: ExporterOTLP, Endpoint: "http://127.0.0.1:1"}
+
+	// act
+	exporter, err := NewExporter(context.Background(), cfg, io.Discard)
+	require.NoError(t, err)
+	exportSpan(t, exporter, "env endpoint span")
+
+	// assert
+	assert.Equal(t, []string{"env endpoint span"}, *names)
+}
+
+func TestNewTraceExporter_OTLPGRPC(t *testing.T) {
+	// arrange
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	service := &fakeTraceService{}
+	server := grpc.NewServer()
+	collectortrace.RegisterTraceServiceServer(server, service)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	cfg := ExporterConfig{Exporter: ExporterOTLP, Protocol: ProtocolGRPC, Endpoint: "http://" + listener.Addr().String()}
+
+	// act
+	exporter, err := NewExporter(context.Background(), cfg, io.Discard)
+	require.NoError(t, err)
+	exportSpan(t, exporter, "otlp grpc span")
+
+	// assert
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	assert.Equal(t, []string{"otlp grpc span"}, service.names)
+}
+
+func TestNewTraceExporter_Zipkin(t *testing.T) {
+	// arrange
+	var received []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/spans", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	cfg := ExporterConfig{Exporter: ExporterZipkin, ZipkinEndpoint: server.URL + "/api/v2/spans"}
+
+	// act
+	exporter, err := NewExporter(context.Background(), cfg, io.Discard)
+	require.NoError(t, err)
+	exportSpan(t, exporter, "zipkin span")
+
+	// assert
+	require.Len(t, received, 1)
+	assert.Equal(t, "zipkin span", received[0]["name"])
+}
+
+func TestNewTraceExporter_Console(t *testing.T) {
+	for _, name := range []string{ExporterConsole, ExporterStdout} {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			var buf bytes.Buffer
+			cfg := ExporterConfig{Exporter: name}
+
+			// act
+			exporter, err := NewExporter(context.Background(), cfg, &buf)
+			require.NoError(t, err)
+			exportSpan(t, exporter, "console span")
+
+			// assert
+			var span map[string]any
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &span))
+			assert.Equal(t, "console span", span["Name"])
+		})
+	}
+}
+
+func TestNewTraceExporter_None(t *testing.T) {
+	// act
+	exporter, err := NewExporter(context.Background(), ExporterConfig{Exporter: ExporterNone}, io.Discard)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Nil(t, exporter)
+}
+
+func TestNewTraceExporter_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ExporterConfig
+	}{
+		{"exporter desconhecido", ExporterConfig{Exporter: "jaeger-thrift"}},
+		{"protocolo desconhecido", ExporterConfig{Exporter: ExporterOTLP, Protocol: "http/json"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// act
+			exporter, err := NewExporter(context.Background(), tt.cfg, io.Discard)
+
+			// assert
+			assert.Error(t, err)
+			assert.Nil(t, exporter)
+		})
+	}
+}
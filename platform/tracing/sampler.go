@@ -0,0 +1,160 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Sampler names accepted in OTEL_TRACES_SAMPLER
+const (
+	SamplerAlwaysOn                = "always_on"
+	SamplerAlwaysOff               = "always_off"
+	SamplerTraceIDRatio            = "traceidratio"
+	SamplerParentBasedAlwaysOn     = "parentbased_always_on"
+	SamplerParentBasedAlwaysOff    = "parentbased_always_off"
+	SamplerParentBasedTraceIDRatio = "parentbased_traceidratio"
+	SamplerRateLimited             = "ratelimited"
+)
+
+// DebugHeader forces a request to be sampled whatever the configured sampler
+const DebugHeader = "X-Debug-Trace"
+
+type forceSamplingKey struct{}
+
+// WithForceSampling marks ctx so that spans started from it are always sampled
+func WithForceSampling(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceSamplingKey{}, true)
+}
+
+func forceSampling(ctx context.Context) bool {
+	forced, _ := ctx.Value(forceSamplingKey{}).(bool)
+	return forced
+}
+
+// DebugSampling forces sampling of requests carrying DebugHeader when enabled; otherwise the header is ignored,
+// so anonymous callers cannot bypass the configured sampler.
+// It must run before otelgin so the server span sees the marked context.
+func DebugSampling(enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if enabled && c.GetHeader(DebugHeader) != "" {
+			c.Request = c.Request.WithContext(WithForceSampling(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
+
+// NewSampler builds the sampler called name, configured by arg and wrapped by the rule-based override
+func NewSampler(name string, arg float64) (sdktrace.Sampler, error) {
+	base, err := baseSampler(name, arg)
+	if err != nil {
+		return nil, err
+	}
+	return RuleSampler(base), nil
+}
+
+func baseSampler(name string, arg float64) (sdktrace.Sampler, error) {
+	switch name {
+	case SamplerAlwaysOn:
+		return sdktrace.AlwaysSample(), nil
+	case SamplerAlwaysOff:
+		return sdktrace.NeverSample(), nil
+	case SamplerTraceIDRatio:
+		return sdktrace.TraceIDRatioBased(arg), nil
+	case SamplerParentBasedAlwaysOn, "":
+		return sdktrace.ParentBased(sdktrace.AlwaysSample()), nil
+	case SamplerParentBasedAlwaysOff:
+		return sdktrace.ParentBased(sdktrace.NeverSample()), nil
+	case SamplerParentBasedTraceIDRatio:
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(arg)), nil
+	case SamplerRateLimited:
+		if arg <= 0 {
+			return nil, fmt.Errorf("rate limited sampler needs a positive traces per second argument, got %v", arg)
+		}
+		return sdktrace.ParentBased(RateLimitedSampler(arg, time.Now)), nil
+	default:
+		return nil, fmt.Errorf("unknown traces sampler %q", name)
+	}
+}
+
+// RateLimitedSampler samples at most perSecond traces per second using a token bucket refilled from now
+func RateLimitedSampler(perSecond float64, now func() time.Time) sdktrace.Sampler {
+	return &rateLimitedSampler{
+		perSecond: perSecond,
+		tokens:    perSecond,
+		last:      now(),
+		now:       now,
+	}
+}
+
+type rateLimitedSampler struct {
+	mu        sync.Mutex
+	perSecond float64
+	tokens    float64
+	last      time.Time
+	now       func() time.Time
+}
+
+func (s *rateLimitedSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	decision := sdktrace.Drop
+	if s.take() {
+		decision = sdktrace.RecordAndSample
+	}
+	return sdktrace.SamplingResult{
+		Decision:   decision,
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+func (s *rateLimitedSampler) take() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.tokens = min(s.perSecond, s.tokens+now.Sub(s.last).Seconds()*s.perSecond)
+	s.last = now
+
+	if s.tokens < 1 {
+		return false
+	}
+	s.tokens--
+	return true
+}
+
+func (s *rateLimitedSampler) Description() string {
+	return fmt.Sprintf("RateLimitedSampler{%g}", s.perSecond)
+}
+
+// RuleSampler applies the override rules on top of base: contexts marked with
+// WithForceSampling are always sampled, and spans base drops are still recorded
+// so that ErrorSpanProcessor can export them if they end with an error status.
+func RuleSampler(base sdktrace.Sampler) sdktrace.Sampler {
+	return ruleSampler{base: base}
+}
+
+type ruleSampler struct {
+	base sdktrace.Sampler
+}
+
+func (s ruleSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if forceSampling(p.ParentContext) {
+		return sdktrace.SamplingResult{
+			Decision:   sdktrace.RecordAndSample,
+			Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+		}
+	}
+	result := s.base.ShouldSample(p)
+	if result.Decision == sdktrace.Drop {
+		result.Decision = sdktrace.RecordOnly
+	}
+	return result
+}
+
+func (s ruleSampler) Description() string {
+	return fmt.Sprintf("RuleSampler{%s}", s.base.Description())
+}
@@ -0,0 +1,249 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// sequenceIDGenerator hands out trace IDs whose ratio-relevant byte cycles through
+// the given values, so TraceIDRatioBased decisions are deterministic:
+// with a 0.5 ratio, 0x00 is always sampled and 0xff never is.
+type sequenceIDGenerator struct {
+	mu     sync.Mutex
+	values []byte
+	next   int
+}
+
+func (g *sequenceIDGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.next++
+	var traceID trace.TraceID
+	traceID[0] = byte(g.next)
+	traceID[8] = g.values[(g.next-1)%len(g.values)]
+	return traceID, g.NewSpanID(ctx, traceID)
+}
+
+func (g *sequenceIDGenerator) NewSpanID(context.Context, trace.TraceID) trace.SpanID {
+	return trace.SpanID{0x01, byte(g.next)}
+}
+
+func newTestProvider(sampler sdktrace.Sampler) (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sampler),
+		sdktrace.WithIDGenerator(&sequenceIDGenerator{values: []byte{0x00, 0xff}}),
+		sdktrace.WithSpanProcessor(ErrorSpanProcessor(sdktrace.NewSimpleSpanProcessor(exporter))),
+	)
+	return tp, exporter
+}
+
+func startSpans(ctx context.Context, tp *sdktrace.TracerProvider, n int) {
+	for range n {
+		_, span := tp.Tracer("test").Start(ctx, "operation")
+		span.End()
+	}
+}
+
+func sampledParent() context.Context {
+	return trace.ContextWithRemoteSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0xaa, 0x08: 0xff},
+		SpanID:     trace.SpanID{0xbb},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	}))
+}
+
+func TestNewSampler(t *testing.T) {
+	tests := []struct {
+		name     string
+		sampler  string
+		arg      float64
+		ctx      context.Context
+		expected int
+	}{
+		{"always_on", SamplerAlwaysOn, 0, context.Background(), 4},
+		{"always_off", SamplerAlwaysOff, 0, context.Background(), 0},
+		{"traceidratio", SamplerTraceIDRatio, 0.5, context.Background(), 2},
+		{"parentbased_always_on sem pai", SamplerParentBasedAlwaysOn, 0, context.Background(), 4},
+		{"parentbased_always_off sem pai", SamplerParentBasedAlwaysOff, 0, context.Background(), 0},
+		{"parentbased_traceidratio sem pai", SamplerParentBasedTraceIDRatio, 0.5, context.Background(), 2},
+		{"padrão vazio", "", 0, context.Background(), 4},
+		{"parentbased_traceidratio com pai amostrado", SamplerParentBasedTraceIDRatio, 0, sampledParent(), 4},
+		{"parentbased_always_off com pai amostrado", SamplerParentBasedAlwaysOff, 0, sampledParent(), 4},
+		{"traceidratio ignora o pai", SamplerTraceIDRatio, 0, sampledParent(), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			sampler, err := NewSampler(tt.sampler, tt.arg)
+			require.NoError(t, err)
+			tp, exporter := newTestProvider(sampler)
+
+			// act
+			startSpans(tt.ctx, tp, 4)
+
+			// assert
+			assert.Len(t, exporter.GetSpans(), tt.expected)
+		})
+	}
+}
+
+func TestNewSampler_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		sampler string
+		arg     float64
+	}{
+		{"sampler desconhecido", "sometimes", 0},
+		{"ratelimited sem taxa", SamplerRateLimited, 0},
+		{"ratelimited com taxa negativa", SamplerRateLimited, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// act
+			sampler, err := NewSampler(tt.sampler, tt.arg)
+
+			// assert
+			assert.Error(t, err)
+			assert.Nil(t, sampler)
+		})
+	}
+}
+
+func TestRateLimitedSampler(t *testing.T) {
+	// arrange
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	tp, exporter := newTestProvider(RuleSampler(RateLimitedSampler(2, clock)))
+
+	// act & assert - the bucket starts full
+	startSpans(context.Background(), tp, 3)
+	assert.Len(t, exporter.GetSpans(), 2)
+
+	// act & assert - half a second refills one token
+	now = now.Add(500 * time.Millisecond)
+	startSpans(context.Background(), tp, 3)
+	assert.Len(t, exporter.GetSpans(), 3)
+
+	// act & assert - the bucket never holds more than one second of tokens
+	now = now.Add(10 * time.Second)
+	startSpans(context.Background(), tp, 5)
+	assert.Len(t, exporter.GetSpans(), 5)
+}
+
+func TestRuleSampler_ForceSampling(t *testing.T) {
+	// arrange
+	tp, exporter := newTestProvider(RuleSampler(sdktrace.NeverSample()))
+
+	// act
+	startSpans(WithForceSampling(context.Background()), tp, 1)
+	startSpans(context.Background(), tp, 1)
+
+	// assert
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.True(t, spans[0].SpanContext.IsSampled())
+}
+
+func TestRuleSampler_ErrorOverride(t *testing.T) {
+	// arrange
+	tp, exporter := newTestProvider(RuleSampler(sdktrace.NeverSample()))
+	tracer := tp.Tracer("test")
+
+	// act
+	ctx, root := tracer.Start(context.Background(), "request")
+	_, before := tracer.Start(ctx, "before")
+	before.End()
+	_, failed := tracer.Start(ctx, "failed")
+	failed.SetStatus(codes.Error, "upstream unavailable")
+	failed.End()
+	root.End()
+
+	_, ok := tracer.Start(context.Background(), "ok")
+	ok.End()
+
+	// assert
+	assert.False(t, trace.SpanContextFromContext(ctx).IsSampled(), "o traceparent propagado deve dizer que não foi amostrado")
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2, "o span com erro e seus ancestrais locais devem ser exportados")
+	assert.Equal(t, "failed", spans[0].Name)
+	assert.Equal(t, "request", spans[1].Name)
+	for _, span := range spans {
+		assert.True(t, span.SpanContext.IsSampled())
+	}
+}
+
+func TestErrorSpanProcessor_ForgetsTraceAtLocalRoot(t *testing.T) {
+	// arrange
+	tp, exporter := newTestProvider(RuleSampler(sdktrace.NeverSample()))
+	tracer := tp.Tracer("test")
+
+	ctx, root := tracer.Start(context.Background(), "request")
+	_, failed := tracer.Start(ctx, "failed")
+	failed.SetStatus(codes.Error, "upstream unavailable")
+	failed.End()
+	root.End()
+
+	// act
+	_, late := tracer.Start(ctx, "late")
+	late.End()
+
+	// assert
+	assert.Len(t, exporter.GetSpans(), 2, "spans que terminam depois da raiz local não são mais promovidos")
+}
+
+func TestDebugSampling(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		enabled  bool
+		expected int
+	}{
+		{"habilitado amostra a requisição com o cabeçalho", true, 1},
+		{"desabilitado ignora o cabeçalho", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			tp, exporter := newTestProvider(RuleSampler(sdktrace.NeverSample()))
+
+			router := gin.New()
+			router.Use(DebugSampling(tt.enabled), otelgin.Middleware("tracing-test", otelgin.WithTracerProvider(tp)))
+			router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			debugRequest := httptest.NewRequest(http.MethodGet, "/ping", nil)
+			debugRequest.Header.Set(DebugHeader, "1")
+
+			// act
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+			router.ServeHTTP(httptest.NewRecorder(), debugRequest)
+
+			// assert
+			spans := exporter.GetSpans()
+			require.Len(t, spans, tt.expected)
+			for _, span := range spans {
+				assert.Equal(t, "GET /ping", span.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/alexduzi/laboteldistributedtracing/platform/health"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/config"
+)
+
+// probeCep is a CEP known to exist, used to check that ViaCEP answers lookups
+const probeCep = "01001000"
+
+// readinessChecks returns the readiness checks of the weather-engine: configuration,
+// ViaCEP, WeatherAPI and, when traces leave the process, the exporter endpoint
+func readinessChecks(cfg *config.Config) []health.Check {
+	client := &http.Client{}
+
+	checks := []health.Check{
+		{Name: "config", Run: func(context.Context) error { return cfg.Validate() }},
+		{Name: "viacep", Run: health.HTTP(client, strings.ReplaceAll(cfg.ViaCEPBaseURL, "{cep}", probeCep))},
+		{Name: "weatherapi", Run: health.HTTP(client, cfg.WeatherBaseURL)},
+	}
+
+	switch cfg.TracesExporter {
+	case "otlp", "":
+		checks = append(checks, health.Check{Name: "exporter", Run: health.Dial(health.Address(cfg.OtelExporterURL))})
+	case "zipkin":
+		checks = append(checks, health.Check{Name: "exporter", Run: health.Dial(health.Address(cfg.ZipkinEndpoint))})
+	}
+	return checks
+}
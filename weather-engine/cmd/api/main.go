@@ -1,7 +1,131 @@
 package main
 
-import "fmt"
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/alexduzi/laboteldistributedtracing/platform/health"
+	"github.com/alexduzi/laboteldistributedtracing/platform/logging"
+	"github.com/alexduzi/laboteldistributedtracing/platform/server"
+	"github.com/alexduzi/laboteldistributedtracing/platform/telemetry"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/handler"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/metrics"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/rpc"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/service"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/tenant"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	"google.golang.org/grpc"
+)
 
 func main() {
-	fmt.Println("hello")
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fatal("Failed to load config", err)
+	}
+
+	gin.SetMode(cfg.GinMode)
+
+	level, err := logging.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		fatal("Invalid log level", err)
+	}
+
+	ctx := context.Background()
+	telemetryCfg := cfg.Telemetry()
+
+	var loggerProvider otellog.LoggerProvider
+	var shutdownLogs telemetry.ShutdownFunc
+	if cfg.LogExport {
+		shutdownLogs, err = telemetry.InitLoggerProvider(ctx, telemetryCfg)
+		if err != nil {
+			fatal("Failed to initialize logger provider", err)
+		}
+		loggerProvider = global.GetLoggerProvider()
+	}
+	slog.SetDefault(logging.New(os.Stdout, level, cfg.ServiceName, loggerProvider))
+
+	shutdown, err := telemetry.InitTracerProvider(ctx, telemetryCfg)
+	if err != nil {
+		fatal("Failed to initialize tracer provider", err)
+	}
+
+	shutdownMetrics, metricsHandler, err := telemetry.InitMeterProvider(ctx, telemetryCfg)
+	if err != nil {
+		fatal("Failed to initialize meter provider", err)
+	}
+
+	appMetrics, err := metrics.New(otel.Meter(metrics.MeterName))
+	if err != nil {
+		fatal("Failed to create metrics instruments", err)
+	}
+
+	cepClient := client.NewCepClient(cfg, appMetrics)
+	weatherClient := client.NewWeatherClient(cfg, appMetrics)
+
+	temperatureService := service.NewTemperatureService(cfg, cepClient, weatherClient, appMetrics)
+
+	limiter := tenant.NewLimiter(cfg.TenantRateLimit, cfg.TenantRateBurst)
+
+	grpcServer := rpc.NewServer(temperatureService, cfg.BatchMaxSize,
+		grpc.ChainUnaryInterceptor(tenant.UnaryServerInterceptor(limiter)),
+		grpc.ChainStreamInterceptor(tenant.StreamServerInterceptor(limiter)),
+	)
+	grpcListener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		fatal("Failed to listen on gRPC port", err)
+	}
+	go func() {
+		slog.Info("Starting weather-engine gRPC server", "port", cfg.GRPCPort)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			fatal("Failed to start gRPC server", err)
+		}
+	}()
+
+	checker := health.NewChecker(cfg.HealthCheckTTL, cfg.HealthCheckTimeout, readinessChecks(cfg)...)
+
+	router := handler.NewRouter(cfg.ServiceName, appMetrics, limiter, cfg.LegacyErrors, cfg.TraceDebugHeader, handler.Handlers{
+		Temperature: handler.NewTemperatureHandler(cfg, temperatureService),
+		Forecast:    handler.NewForecastHandler(cepClient, weatherClient),
+		History:     handler.NewHistoryHandler(cfg, cepClient, weatherClient),
+		Health:      health.NewHandler(cfg.ServiceName, checker),
+		Metrics:     metricsHandler,
+	})
+
+	listener, err := net.Listen("tcp", ":"+cfg.Port)
+	if err != nil {
+		fatal("Failed to listen on HTTP port", err)
+	}
+
+	// telemetry is flushed once the servers have drained, logs last so the shutdown logs are exported too
+	flush := []server.ShutdownFunc{server.ShutdownFunc(shutdown), server.ShutdownFunc(shutdownMetrics)}
+	if shutdownLogs != nil {
+		flush = append(flush, server.ShutdownFunc(shutdownLogs))
+	}
+
+	graceful := &server.Graceful{
+		Server:      &http.Server{Handler: router},
+		GracePeriod: cfg.ShutdownGracePeriod,
+		NotReady:    checker.Drain,
+		Drain:       []server.ShutdownFunc{rpc.GracefulStop(grpcServer)},
+		Flush:       flush,
+	}
+
+	slog.Info("Starting weather-engine", "port", cfg.Port)
+	if err := graceful.Serve(ctx, listener); err != nil {
+		fatal("Failed to shut down cleanly", err)
+	}
+}
+
+// fatal logs the error and terminates the process
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/loadgen"
+)
+
+const usage = `Usage:
+  loadgen upstreams [-addr :9090] [-slow-delay 1s]
+      Serve stand-in ViaCEP and WeatherAPI upstreams. Start the weather-engine with
+      VIA_CEP_BASE_URL=http://localhost:9090/ws/{cep}/json/
+      WEATHER_BASE_URL=http://localhost:9090/v1/current.json
+
+  loadgen run [-target http://localhost:8080] [-requests 200] [-concurrency 10]
+              [-slow-ratio 0.2] [-error-ratio 0.1] [-seed 1]
+      Send a mix of fast, slow and failing temperature requests to the gateway
+`
+
+// loadgen produces fast, slow and failing traces to verify the collector tail sampling policies
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "upstreams":
+		err = upstreams(os.Args[2:])
+	case "run":
+		err = run(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		slog.Error("loadgen failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func upstreams(args []string) error {
+	flags := flag.NewFlagSet("upstreams", flag.ExitOnError)
+	addr := flags.String("addr", ":9090", "listen address")
+	slowDelay := flags.Duration("slow-delay", time.Second, "latency of the slow scenario")
+	_ = flags.Parse(args)
+
+	slog.Info("Serving stand-in upstreams", "addr", *addr, "slow_delay", *slowDelay)
+	return http.ListenAndServe(*addr, loadgen.NewUpstreams(*slowDelay))
+}
+
+func run(args []string) error {
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	cfg := loadgen.RunConfig{}
+	flags.StringVar(&cfg.Target, "target", "http://localhost:8080", "cep-gateway base URL")
+	flags.IntVar(&cfg.Requests, "requests", 200, "total number of requests")
+	flags.IntVar(&cfg.Concurrency, "concurrency", 10, "parallel requests")
+	flags.Float64Var(&cfg.SlowRatio, "slow-ratio", 0.2, "share of slow requests")
+	flags.Float64Var(&cfg.ErrorRatio, "error-ratio", 0.1, "share of failing requests")
+	flags.Uint64Var(&cfg.Seed, "seed", 1, "seed of the request mix")
+	_ = flags.Parse(args)
+
+	if cfg.SlowRatio < 0 || cfg.ErrorRatio < 0 || cfg.SlowRatio+cfg.ErrorRatio > 1 {
+		return fmt.Errorf("slow and error ratios must be non-negative and add up to at most 1")
+	}
+
+	start := time.Now()
+	summary := loadgen.Run(context.Background(), &http.Client{Timeout: 30 * time.Second}, cfg)
+
+	fmt.Printf("Sent %d requests to %s in %s\n", cfg.Requests, cfg.Target, time.Since(start).Round(time.Millisecond))
+	for _, scenario := range []loadgen.Scenario{loadgen.ScenarioFast, loadgen.ScenarioSlow, loadgen.ScenarioFailing} {
+		result := summary[scenario]
+		statuses := make([]int, 0, len(result.Statuses))
+		for status := range result.Statuses {
+			statuses = append(statuses, status)
+		}
+		slices.Sort(statuses)
+
+		fmt.Printf("  %-8s requests=%d failures=%d", scenario, result.Requests, result.Failures)
+		for _, status := range statuses {
+			fmt.Printf(" %d=%d", status, result.Statuses[status])
+		}
+		fmt.Println()
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// maxPartitions bounds the tenants holding entries; once it is reached, partitions whose
+// entries have all expired are dropped, then the partition written the longest ago
+const maxPartitions = 1_000
+
+type entry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// Cache is a TTL cache split into partitions, one per tenant, so that a tenant
+// can neither read another tenant's entries nor evict them.
+// A nil *Cache is valid and never holds anything.
+type Cache[V any] struct {
+	mu            sync.Mutex
+	ttl           time.Duration
+	maxEntries    int
+	maxPartitions int
+	now           func() time.Time
+	partitions    map[string]map[string]entry[V]
+}
+
+// New creates a cache keeping entries for ttl with at most maxEntries per partition,
+// and at most maxPartitions partitions.
+// It returns nil, which disables caching, when ttl is not positive.
+func New[V any](ttl time.Duration, maxEntries int) *Cache[V] {
+	return newWithClock[V](ttl, maxEntries, time.Now)
+}
+
+func newWithClock[V any](ttl time.Duration, maxEntries int, now func() time.Time) *Cache[V] {
+	if ttl <= 0 {
+		return nil
+	}
+	return &Cache[V]{
+		ttl:           ttl,
+		maxEntries:    max(maxEntries, 1),
+		maxPartitions: maxPartitions,
+		now:           now,
+		partitions:    make(map[string]map[string]entry[V]),
+	}
+}
+
+// Get returns the unexpired value stored under key in the partition
+func (c *Cache[V]) Get(partition, key string) (V, bool) {
+	var zero V
+	if c == nil {
+		return zero, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.partitions[partition][key]
+	if !ok || !c.now().Before(e.expires) {
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set stores value under key in the partition. A full partition first drops its
+// expired entries, then the entry closest to expiring.
+func (c *Cache[V]) Set(partition, key string, value V) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	entries, ok := c.partitions[partition]
+	if !ok {
+		if len(c.partitions) >= c.maxPartitions {
+			c.evictPartition(now)
+		}
+		entries = make(map[string]entry[V])
+		c.partitions[partition] = entries
+	}
+
+	if _, exists := entries[key]; !exists && len(entries) >= c.maxEntries {
+		evict(entries, now, c.maxEntries)
+	}
+	entries[key] = entry[V]{value: value, expires: now.Add(c.ttl)}
+}
+
+// Len returns the number of entries held for the partition, including expired ones
+func (c *Cache[V]) Len(partition string) int {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.partitions[partition])
+}
+
+func evict[V any](entries map[string]entry[V], now time.Time, maxEntries int) {
+	for key, e := range entries {
+		if !now.Before(e.expires) {
+			delete(entries, key)
+		}
+	}
+	if len(entries) < maxEntries {
+		return
+	}
+
+	var oldest string
+	var oldestExpires time.Time
+	for key, e := range entries {
+		if oldest == "" || e.expires.Before(oldestExpires) {
+			oldest, oldestExpires = key, e.expires
+		}
+	}
+	delete(entries, oldest)
+}
+
+// evictPartition drops the partitions whose entries have all expired, and then the
+// partition written the longest ago if that freed no room
+func (c *Cache[V]) evictPartition(now time.Time) {
+	var oldest string
+	var oldestWrite time.Time
+	for partition, entries := range c.partitions {
+		var lastWrite time.Time
+		for _, e := range entries {
+			if e.expires.After(lastWrite) {
+				lastWrite = e.expires
+			}
+		}
+		if !now.Before(lastWrite) {
+			delete(c.partitions, partition)
+			continue
+		}
+		if oldest == "" || lastWrite.Before(oldestWrite) {
+			oldest, oldestWrite = partition, lastWrite
+		}
+	}
+	if len(c.partitions) >= c.maxPartitions {
+		delete(c.partitions, oldest)
+	}
+}
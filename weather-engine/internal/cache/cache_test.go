@@ -0,0 +1,158 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_GetSet(t *testing.T) {
+	// arrange
+	c := New[int](time.Minute, 10)
+
+	// act
+	c.Set("team-a", "01001000", 25)
+	value, ok := c.Get("team-a", "01001000")
+	_, missing := c.Get("team-a", "20040020")
+
+	// assert
+	assert.True(t, ok)
+	assert.Equal(t, 25, value)
+	assert.False(t, missing)
+}
+
+func TestCache_PartitionsAreIsolated(t *testing.T) {
+	// arrange
+	c := New[int](time.Minute, 10)
+	c.Set("team-a", "01001000", 25)
+
+	// act
+	_, ok := c.Get("team-b", "01001000")
+
+	// assert
+	assert.False(t, ok)
+	assert.Equal(t, 1, c.Len("team-a"))
+	assert.Equal(t, 0, c.Len("team-b"))
+}
+
+func TestCache_Expires(t *testing.T) {
+	// arrange
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	c := newWithClock[int](time.Minute, 10, func() time.Time { return now })
+	c.Set("team-a", "01001000", 25)
+
+	// act
+	now = now.Add(59 * time.Second)
+	_, beforeExpiry := c.Get("team-a", "01001000")
+	now = now.Add(time.Second)
+	_, atExpiry := c.Get("team-a", "01001000")
+
+	// assert
+	assert.True(t, beforeExpiry)
+	assert.False(t, atExpiry)
+}
+
+func TestCache_EvictsWithinPartition(t *testing.T) {
+	// arrange
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	c := newWithClock[int](time.Minute, 2, func() time.Time { return now })
+
+	c.Set("team-b", "other", 1)
+	c.Set("team-a", "first", 1)
+	now = now.Add(time.Second)
+	c.Set("team-a", "second", 2)
+	now = now.Add(time.Second)
+
+	// act
+	c.Set("team-a", "third", 3)
+
+	// assert
+	_, first := c.Get("team-a", "first")
+	_, second := c.Get("team-a", "second")
+	_, third := c.Get("team-a", "third")
+	_, other := c.Get("team-b", "other")
+	assert.False(t, first)
+	assert.True(t, second)
+	assert.True(t, third)
+	assert.True(t, other)
+	assert.Equal(t, 2, c.Len("team-a"))
+}
+
+func TestCache_EvictsExpiredFirst(t *testing.T) {
+	// arrange
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	c := newWithClock[int](time.Minute, 2, func() time.Time { return now })
+
+	c.Set("team-a", "expired", 1)
+	now = now.Add(50 * time.Second)
+	c.Set("team-a", "fresh", 2)
+	now = now.Add(20 * time.Second)
+
+	// act
+	c.Set("team-a", "new", 3)
+
+	// assert
+	_, fresh := c.Get("team-a", "fresh")
+	_, added := c.Get("team-a", "new")
+	assert.True(t, fresh)
+	assert.True(t, added)
+	assert.Equal(t, 2, c.Len("team-a"))
+}
+
+func TestCache_BoundsPartitions(t *testing.T) {
+	// arrange
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	c := newWithClock[int](time.Minute, 10, func() time.Time { return now })
+	c.maxPartitions = 2
+
+	c.Set("team-a", "01001000", 1)
+	now = now.Add(time.Second)
+	c.Set("team-b", "01001000", 2)
+	now = now.Add(time.Second)
+
+	// act
+	c.Set("team-c", "01001000", 3)
+
+	// assert
+	assert.Len(t, c.partitions, 2)
+	assert.Equal(t, 0, c.Len("team-a"), "a partição escrita há mais tempo deve sair")
+	assert.Equal(t, 1, c.Len("team-b"))
+	assert.Equal(t, 1, c.Len("team-c"))
+}
+
+func TestCache_EvictsExpiredPartitionsFirst(t *testing.T) {
+	// arrange
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	c := newWithClock[int](time.Minute, 10, func() time.Time { return now })
+	c.maxPartitions = 2
+
+	c.Set("team-a", "01001000", 1)
+	now = now.Add(50 * time.Second)
+	c.Set("team-b", "01001000", 2)
+	now = now.Add(20 * time.Second)
+	c.Set("team-a", "20040020", 3)
+	now = now.Add(45 * time.Second)
+
+	// act
+	c.Set("team-c", "01001000", 4)
+
+	// assert
+	assert.Equal(t, 0, c.Len("team-b"), "partições só com entradas expiradas saem primeiro")
+	assert.Equal(t, 2, c.Len("team-a"))
+	assert.Equal(t, 1, c.Len("team-c"))
+}
+
+func TestCache_Disabled(t *testing.T) {
+	// arrange
+	c := New[int](0, 10)
+
+	// act
+	c.Set("team-a", "01001000", 25)
+	_, ok := c.Get("team-a", "01001000")
+
+	// assert
+	assert.Nil(t, c)
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len("team-a"))
+}
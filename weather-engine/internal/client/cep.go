@@ -2,15 +2,17 @@ package client
 
 import (
 	"context"
-	"encoding/json"
-	"io"
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/alexduzi/laboteldistributedtracing/cep"
 	cErrors "github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client/error"
 	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/metrics"
 	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/model"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 type CepClientInterface interface {
@@ -18,21 +20,36 @@ type CepClientInterface interface {
 }
 
 type CepClient struct {
-	config *config.Config
-	client *http.Client
+	config  *config.Config
+	client  *http.Client
+	metrics *metrics.Metrics
 }
 
-func NewCepClient(cfg *config.Config) *CepClient {
+func NewCepClient(cfg *config.Config, m *metrics.Metrics) *CepClient {
 	return &CepClient{
-		config: cfg,
+		config:  cfg,
+		metrics: m,
 		client: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout:   10 * time.Second,
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
 		},
 	}
 }
 
-func (c CepClient) GetCep(ctx context.Context, cep string) (*model.ViacepResponse, error) {
-	cepApiUrl := strings.Replace(c.config.ViaCEPBaseURL, "{cep}", cep, 1)
+// GetCep looks the CEP up on ViaCEP. Input that does not parse as a CEP is rejected with
+// CepClientBadRequest before any request is made.
+func (c CepClient) GetCep(ctx context.Context, raw string) (res *model.ViacepResponse, err error) {
+	code, err := cep.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", cErrors.CepClientBadRequest, err)
+	}
+
+	start := time.Now()
+	defer func() {
+		c.metrics.RecordUpstream(ctx, metrics.UpstreamViaCEP, "GetCep", time.Since(start), err)
+	}()
+
+	cepApiUrl := strings.Replace(c.config.ViaCEPBaseURL, "{cep}", code.String(), 1)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", cepApiUrl, nil)
 	if err != nil {
@@ -49,13 +66,13 @@ func (c CepClient) GetCep(ctx context.Context, cep string) (*model.ViacepRespons
 		return nil, cErrors.NewCepClientHTTPError(resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := readBody(ctx, c.config.TraceInternalSpans, resp.Body)
 	if err != nil {
 		return nil, err
 	}
 
 	var cepRes model.ViacepResponse
-	err = json.Unmarshal(body, &cepRes)
+	err = unmarshal(ctx, c.config.TraceInternalSpans, body, &cepRes)
 	if err != nil {
 		return nil, err
 	}
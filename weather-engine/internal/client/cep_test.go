@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cErrors "github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client/error"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCepClient_GetCep_NormalizesCep(t *testing.T) {
+	// arrange
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		_ = json.NewEncoder(w).Encode(model.GetViacepResponseMock("01310-100"))
+	}))
+	defer server.Close()
+
+	client := NewCepClient(&config.Config{ViaCEPBaseURL: server.URL + "/ws/{cep}/json/"}, nil)
+
+	// act
+	result, err := client.GetCep(context.Background(), " 01310-100 ")
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, "São Paulo", result.Localidade)
+	assert.Equal(t, []string{"/ws/01310100/json/"}, paths)
+}
+
+func TestCepClient_GetCep_RejectsInvalidInput(t *testing.T) {
+	// arrange
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	client := NewCepClient(&config.Config{ViaCEPBaseURL: server.URL + "/ws/{cep}/json/"}, nil)
+
+	for _, input := range []string{"", "0131A100", "../../admin", "01310100?x=1", "00000000"} {
+		t.Run(input, func(t *testing.T) {
+			// act
+			result, err := client.GetCep(context.Background(), input)
+
+			// assert
+			assert.Nil(t, result)
+			assert.ErrorIs(t, err, cErrors.CepClientBadRequest)
+		})
+	}
+	assert.Zero(t, calls, "entrada inválida não deve chegar ao provedor")
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// readBody reads the whole response body. When traced is set the read runs in
+// an internal span recording the payload size; otherwise no span is created.
+func readBody(ctx context.Context, traced bool, r io.Reader) ([]byte, error) {
+	if !traced {
+		return io.ReadAll(r)
+	}
+
+	_, span := otel.Tracer(tracerName).Start(ctx, "read response body")
+	defer span.End()
+
+	body, err := io.ReadAll(r)
+	span.SetAttributes(attribute.Int("payload.size", len(body)))
+	if err != nil {
+		return nil, recordSpanError(span, err)
+	}
+	return body, nil
+}
+
+// unmarshal decodes the JSON body into v. When traced is set the decoding runs
+// in an internal span recording the payload size; otherwise no span is created.
+func unmarshal(ctx context.Context, traced bool, body []byte, v any) error {
+	if !traced {
+		return json.Unmarshal(body, v)
+	}
+
+	_, span := otel.Tracer(tracerName).Start(ctx, "decode json",
+		trace.WithAttributes(attribute.Int("payload.size", len(body))))
+	defer span.End()
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return recordSpanError(span, err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func endedSpans(spans []sdktrace.ReadOnlySpan) map[string]sdktrace.ReadOnlySpan {
+	result := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range spans {
+		result[span.Name()] = span
+	}
+	return result
+}
+
+func TestCepClient_GetCep_InternalSpans(t *testing.T) {
+	// arrange
+	recorder := setupSpanRecorder(t)
+
+	payload, err := json.Marshal(model.GetViacepResponseMock("01310-100"))
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(payload)
+	}))
+	defer server.Close()
+
+	client := NewCepClient(&config.Config{ViaCEPBaseURL: server.URL + "/{cep}", TraceInternalSpans: true}, nil)
+	ctx, request := otel.Tracer("test").Start(context.Background(), "request")
+
+	// act
+	result, err := client.GetCep(ctx, "01310100")
+	request.End()
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, "São Paulo", result.Localidade)
+
+	spans := endedSpans(recorder.Ended())
+	require.Contains(t, spans, "HTTP GET")
+	require.Contains(t, spans, "read response body")
+	require.Contains(t, spans, "decode json")
+	assert.Equal(t, request.SpanContext().SpanID(), spans["HTTP GET"].Parent().SpanID())
+	assert.Equal(t, request.SpanContext().SpanID(), spans["decode json"].Parent().SpanID(), "a decodificação deve ficar ao lado da chamada HTTP")
+	assert.Equal(t, int64(len(payload)), spanAttribute(spans["read response body"], "payload.size").AsInt64())
+	assert.Equal(t, int64(len(payload)), spanAttribute(spans["decode json"], "payload.size").AsInt64())
+}
+
+func TestWeatherClient_GetWeather_InternalSpansDisabled(t *testing.T) {
+	// arrange
+	recorder := setupSpanRecorder(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(model.GetWeatherResponseMock("São Paulo"))
+	}))
+	defer server.Close()
+
+	client := NewWeatherClient(&config.Config{WeatherBaseURL: server.URL}, nil)
+
+	// act
+	_, err := client.GetWeather(context.Background(), "São Paulo")
+
+	// assert
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1, "só a chamada HTTP deve gerar span")
+	assert.Equal(t, "HTTP GET", spans[0].Name())
+}
+
+func TestWeatherClient_GetForecast_DecodeErrorSpan(t *testing.T) {
+	// arrange
+	recorder := setupSpanRecorder(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"location":`))
+	}))
+	defer server.Close()
+
+	client := NewWeatherClient(&config.Config{WeatherForecastURL: server.URL, TraceInternalSpans: true}, nil)
+
+	// act
+	_, err := client.GetForecast(context.Background(), "São Paulo", 3)
+
+	// assert
+	require.Error(t, err)
+
+	spans := endedSpans(recorder.Ended())
+	require.Contains(t, spans, "decode json")
+	decode := spans["decode json"]
+	assert.Equal(t, codes.Error, decode.Status().Code)
+	assert.Equal(t, spans["WeatherAPI GetForecast"].SpanContext().SpanID(), decode.Parent().SpanID())
+}
@@ -1,8 +1,10 @@
 package error
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 )
 
 var (
@@ -15,8 +17,46 @@ var (
 	WeatherClientNotFound        = errors.New("Weather API returned not found")
 	WeatherClientInternalError   = errors.New("Weather API internal error")
 	WeatherClientUnexpectedError = errors.New("unexpected error from Weather API")
+	WeatherClientQuotaExceeded   = errors.New("Weather API call budget exhausted")
 )
 
+// Quota windows of a QuotaError
+const (
+	QuotaWindowMinute   = "minute"
+	QuotaWindowMonth    = "month"
+	QuotaWindowProvider = "provider"
+)
+
+// DefaultRetryAfter is the wait suggested to callers when the budget does not tell when it resets
+const DefaultRetryAfter = 30 * time.Second
+
+// QuotaError reports that a call was not made because the outbound budget of
+// Window is used up. It matches WeatherClientQuotaExceeded with errors.Is.
+type QuotaError struct {
+	Window string
+	// RetryAfter is when the budget is expected to allow calls again; zero when unknown
+	RetryAfter time.Duration
+}
+
+func (e *QuotaError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s: %s budget, retry after %s", WeatherClientQuotaExceeded, e.Window, e.RetryAfter.Round(time.Second))
+	}
+	return fmt.Sprintf("%s: %s budget", WeatherClientQuotaExceeded, e.Window)
+}
+
+// Delay returns RetryAfter, or DefaultRetryAfter when it is unknown
+func (e *QuotaError) Delay() time.Duration {
+	if e.RetryAfter > 0 {
+		return e.RetryAfter
+	}
+	return DefaultRetryAfter
+}
+
+func (e *QuotaError) Unwrap() error {
+	return WeatherClientQuotaExceeded
+}
+
 func NewCepClientHTTPError(statusCode int) error {
 	switch statusCode {
 	case 400:
@@ -36,9 +76,35 @@ func NewWeatherClientHTTPError(statusCode int) error {
 		return WeatherClientBadRequest
 	case 404:
 		return WeatherClientNotFound
+	case 429:
+		return &QuotaError{Window: QuotaWindowProvider}
 	case 500, 502, 503, 504:
 		return WeatherClientInternalError
 	default:
 		return fmt.Errorf("%w: status code %d", WeatherClientUnexpectedError, statusCode)
 	}
 }
+
+// Category returns a low-cardinality label describing an upstream error, suitable for metric attributes
+func Category(err error) string {
+	switch {
+	case err == nil:
+		return "none"
+	case errors.Is(err, CepClientBadRequest), errors.Is(err, WeatherClientBadRequest):
+		return "bad_request"
+	case errors.Is(err, CepClientNotFound), errors.Is(err, WeatherClientNotFound):
+		return "not_found"
+	case errors.Is(err, CepClientInternalError), errors.Is(err, WeatherClientInternalError):
+		return "internal_error"
+	case errors.Is(err, CepClientUnexpectedError), errors.Is(err, WeatherClientUnexpectedError):
+		return "unexpected_error"
+	case errors.Is(err, WeatherClientQuotaExceeded):
+		return "quota_exceeded"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	default:
+		return "transport"
+	}
+}
@@ -1,8 +1,11 @@
 package error
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -162,13 +165,38 @@ func TestNewWeatherClientHTTPError_UnexpectedStatusCode(t *testing.T) {
 
 func TestNewWeatherClientHTTPError_AnotherUnexpectedStatusCode(t *testing.T) {
 	// act
-	err := NewWeatherClientHTTPError(429)
+	err := NewWeatherClientHTTPError(409)
 
 	// assert
 	assert.Error(t, err)
 	assert.ErrorIs(t, err, WeatherClientUnexpectedError)
 	assert.Contains(t, err.Error(), "unexpected error from Weather API")
-	assert.Contains(t, err.Error(), "status code 429")
+	assert.Contains(t, err.Error(), "status code 409")
+}
+
+func TestNewWeatherClientHTTPError_TooManyRequests(t *testing.T) {
+	// act
+	err := NewWeatherClientHTTPError(429)
+
+	// assert
+	assert.ErrorIs(t, err, WeatherClientQuotaExceeded)
+	var quotaErr *QuotaError
+	assert.ErrorAs(t, err, &quotaErr)
+	assert.Equal(t, QuotaWindowProvider, quotaErr.Window)
+}
+
+func TestQuotaError(t *testing.T) {
+	// arrange
+	err := fmt.Errorf("GetWeather: %w", &QuotaError{Window: QuotaWindowMinute, RetryAfter: 1500 * time.Millisecond})
+
+	// assert
+	assert.ErrorIs(t, err, WeatherClientQuotaExceeded)
+	assert.NotErrorIs(t, err, WeatherClientUnexpectedError)
+	var quotaErr *QuotaError
+	assert.ErrorAs(t, err, &quotaErr)
+	assert.Equal(t, 1500*time.Millisecond, quotaErr.RetryAfter)
+	assert.Equal(t, "GetWeather: Weather API call budget exhausted: minute budget, retry after 2s", err.Error())
+	assert.Equal(t, "Weather API call budget exhausted: month budget", (&QuotaError{Window: QuotaWindowMonth}).Error())
 }
 
 func TestPredefinedErrors_AreDistinct(t *testing.T) {
@@ -191,7 +219,7 @@ func TestPredefinedErrors_AreDistinct(t *testing.T) {
 func TestErrorIs_WithWrappedErrors(t *testing.T) {
 	// arrange
 	cepErr := NewCepClientHTTPError(418)
-	weatherErr := NewWeatherClientHTTPError(429)
+	weatherErr := NewWeatherClientHTTPError(409)
 
 	// assert - verificar que errors.Is funciona corretamente com erros wrapped
 	assert.True(t, errors.Is(cepErr, CepClientUnexpectedError))
@@ -202,3 +230,33 @@ func TestErrorIs_WithWrappedErrors(t *testing.T) {
 	assert.False(t, errors.Is(weatherErr, WeatherClientBadRequest))
 	assert.False(t, errors.Is(weatherErr, CepClientUnexpectedError))
 }
+
+func TestCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"sem erro", nil, "none"},
+		{"bad request CEP", NewCepClientHTTPError(400), "bad_request"},
+		{"bad request Weather", NewWeatherClientHTTPError(400), "bad_request"},
+		{"not found", NewWeatherClientHTTPError(404), "not_found"},
+		{"erro interno", NewCepClientHTTPError(503), "internal_error"},
+		{"erro inesperado", NewWeatherClientHTTPError(409), "unexpected_error"},
+		{"cota do provedor", NewWeatherClientHTTPError(429), "quota_exceeded"},
+		{"cota local", &QuotaError{Window: QuotaWindowMonth}, "quota_exceeded"},
+		{"timeout", fmt.Errorf("request: %w", context.DeadlineExceeded), "timeout"},
+		{"cancelado", context.Canceled, "canceled"},
+		{"erro de transporte", errors.New("connection refused"), "transport"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// act
+			category := Category(tt.err)
+
+			// assert
+			assert.Equal(t, tt.expected, category)
+		})
+	}
+}
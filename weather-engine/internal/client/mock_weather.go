@@ -32,3 +32,19 @@ func (w *WeatherClientStub) GetWeather(ctx context.Context, city string) (*model
 	}
 	return args.Get(0).(*model.WeatherResponse), nil
 }
+
+func (w *WeatherClientStub) GetForecast(ctx context.Context, city string, days int) (*model.ForecastResponse, error) {
+	args := w.Called(ctx, city, days)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ForecastResponse), nil
+}
+
+func (w *WeatherClientStub) GetHistory(ctx context.Context, city string, date time.Time) (*model.ForecastResponse, error) {
+	args := w.Called(ctx, city, date)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ForecastResponse), nil
+}
@@ -0,0 +1,226 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	cErrors "github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client/error"
+	"golang.org/x/time/rate"
+)
+
+// quotaHeaders are the response headers from which the provider's own count of remaining calls is read
+var quotaHeaders = []string{"X-RateLimit-Remaining", "RateLimit-Remaining"}
+
+// resetHeaders tell when the provider's count resets, in seconds from now or as a Unix time
+var resetHeaders = []string{"X-RateLimit-Reset", "RateLimit-Reset"}
+
+const (
+	// providerTTL is how long the provider's count is trusted when it does not say when it resets
+	providerTTL = time.Minute
+	// probeInterval spaces the calls let through to find out whether an exhausted provider window has reset
+	probeInterval = 10 * time.Second
+	// unixReset is the smallest reset header value read as a Unix time rather than as seconds from now
+	unixReset = 1_000_000_000
+)
+
+// Quota enforces the outbound WeatherAPI call budget: a per-minute rate, for which
+// callers queue up to maxWait, and a monthly total counted by this process.
+// The provider's own remaining count applies to its current window only: it is
+// forgotten when that window resets, and once it reaches zero a single probe call
+// is let through every probeInterval to learn whether the window has reset early.
+// A nil *Quota is valid and allows every call.
+type Quota struct {
+	perMinute *rate.Limiter
+	maxWait   time.Duration
+	monthly   int
+	now       func() time.Time
+
+	mu    sync.Mutex
+	month time.Time
+	used  int
+	// provider is the remaining quota last reported by WeatherAPI, or -1 when unknown
+	provider int64
+	// providerUntil is when the provider's window resets and provider stops applying
+	providerUntil time.Time
+}
+
+// NewQuota returns nil, which disables the budget, when neither limit is positive
+func NewQuota(perMinute, monthly int, maxWait time.Duration) *Quota {
+	return newQuotaWithClock(perMinute, monthly, maxWait, time.Now)
+}
+
+func newQuotaWithClock(perMinute, monthly int, maxWait time.Duration, now func() time.Time) *Quota {
+	if perMinute <= 0 && monthly <= 0 {
+		return nil
+	}
+
+	q := &Quota{
+		maxWait:  maxWait,
+		monthly:  monthly,
+		now:      now,
+		provider: -1,
+	}
+	if perMinute > 0 {
+		q.perMinute = rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMinute)), perMinute)
+	}
+	return q
+}
+
+// Acquire spends one call of the budget, waiting for the per-minute rate up to maxWait.
+// It returns a *cErrors.QuotaError when the budget does not allow the call.
+func (q *Quota) Acquire(ctx context.Context) error {
+	if q == nil {
+		return nil
+	}
+
+	slot, err := q.reserveMonthly()
+	if err != nil {
+		return err
+	}
+
+	if q.perMinute != nil {
+		reservation := q.perMinute.ReserveN(q.now(), 1)
+		delay := reservation.DelayFrom(q.now())
+		if delay > q.maxWait {
+			reservation.CancelAt(q.now())
+			q.refundMonthly(slot)
+			return &cErrors.QuotaError{Window: cErrors.QuotaWindowMinute, RetryAfter: delay}
+		}
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				reservation.CancelAt(q.now())
+				q.refundMonthly(slot)
+				return ctx.Err()
+			}
+		}
+	}
+	return nil
+}
+
+// monthlySlot is a call reserved by reserveMonthly, so that it can be refunded
+type monthlySlot struct {
+	month    time.Time
+	provider bool
+}
+
+// reserveMonthly checks the monthly budget and spends one call of it under the same
+// lock, so concurrent callers can never overspend it
+func (q *Quota) reserveMonthly() (monthlySlot, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	if month := startOfMonth(now); !month.Equal(q.month) {
+		q.month, q.used = month, 0
+	}
+
+	if q.provider >= 0 && !now.Before(q.providerUntil) {
+		if q.provider > 0 {
+			q.provider = -1
+		} else {
+			// let this call through as the probe, holding back the others until the next one
+			q.providerUntil = now.Add(probeInterval)
+		}
+	} else if q.provider == 0 {
+		return monthlySlot{}, &cErrors.QuotaError{Window: cErrors.QuotaWindowProvider, RetryAfter: q.providerUntil.Sub(now)}
+	}
+	if q.monthly > 0 && q.used >= q.monthly {
+		return monthlySlot{}, &cErrors.QuotaError{Window: cErrors.QuotaWindowMonth, RetryAfter: q.month.AddDate(0, 1, 0).Sub(now)}
+	}
+
+	slot := monthlySlot{month: q.month, provider: q.provider > 0}
+	q.used++
+	if slot.provider {
+		q.provider--
+	}
+	return slot, nil
+}
+
+// refundMonthly gives back a call that was reserved but not made, unless the month
+// it was reserved in is over
+func (q *Quota) refundMonthly(slot monthlySlot) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !slot.month.Equal(q.month) {
+		return
+	}
+	q.used = max(q.used-1, 0)
+	if slot.provider && q.provider >= 0 {
+		q.provider++
+	}
+}
+
+// Observe records the remaining quota reported in the response headers, when present,
+// until the reset they announce or for providerTTL when they announce none
+func (q *Quota) Observe(header http.Header) {
+	if q == nil {
+		return
+	}
+
+	for _, name := range quotaHeaders {
+		if remaining, err := strconv.ParseInt(header.Get(name), 10, 64); err == nil && remaining >= 0 {
+			q.mu.Lock()
+			defer q.mu.Unlock()
+
+			q.provider = remaining
+			q.providerUntil = providerReset(header, q.now())
+			return
+		}
+	}
+}
+
+// providerReset returns when the provider's window resets according to the response headers
+func providerReset(header http.Header, now time.Time) time.Time {
+	for _, name := range resetHeaders {
+		value, err := strconv.ParseInt(header.Get(name), 10, 64)
+		if err != nil || value < 0 {
+			continue
+		}
+		if value < unixReset {
+			return now.Add(time.Duration(value) * time.Second)
+		}
+		if at := time.Unix(value, 0); at.After(now) {
+			return at
+		}
+	}
+	return now.Add(providerTTL)
+}
+
+// Remaining returns the calls left this month, the lower of the local budget and the
+// provider's count, and false when neither is known
+func (q *Quota) Remaining() (int64, bool) {
+	if q == nil {
+		return 0, false
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	remaining, known := q.provider, q.provider >= 0 && q.now().Before(q.providerUntil)
+	if !known {
+		remaining = 0
+	}
+	if q.monthly > 0 {
+		local := int64(q.monthly - q.used)
+		if !startOfMonth(q.now()).Equal(q.month) {
+			local = int64(q.monthly)
+		}
+		if !known || local < remaining {
+			remaining, known = local, true
+		}
+	}
+	return max(remaining, 0), known
+}
+
+func startOfMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
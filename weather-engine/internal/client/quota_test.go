@@ -0,0 +1,312 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cErrors "github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2026, time.March, 15, 12, 0, 0, 0, time.UTC)}
+}
+
+func TestNewQuota_Disabled(t *testing.T) {
+	// arrange
+	quota := NewQuota(0, 0, time.Second)
+
+	// act
+	err := quota.Acquire(context.Background())
+	remaining, known := quota.Remaining()
+
+	// assert
+	assert.Nil(t, quota)
+	assert.NoError(t, err)
+	assert.False(t, known)
+	assert.Zero(t, remaining)
+	assert.NotPanics(t, func() { quota.Observe(http.Header{"X-Ratelimit-Remaining": {"10"}}) })
+}
+
+func TestQuota_PerMinuteRejectsBeyondMaxWait(t *testing.T) {
+	// arrange
+	clock := newFakeClock()
+	quota := newQuotaWithClock(1, 0, 0, clock.Now)
+	require.NoError(t, quota.Acquire(context.Background()))
+
+	// act
+	err := quota.Acquire(context.Background())
+
+	// assert
+	var quotaErr *cErrors.QuotaError
+	require.ErrorAs(t, err, &quotaErr)
+	assert.ErrorIs(t, err, cErrors.WeatherClientQuotaExceeded)
+	assert.Equal(t, cErrors.QuotaWindowMinute, quotaErr.Window)
+	assert.Equal(t, time.Minute, quotaErr.RetryAfter)
+
+	clock.now = clock.now.Add(time.Minute)
+	assert.NoError(t, quota.Acquire(context.Background()), "o orçamento deve ser recomposto após um minuto")
+}
+
+func TestQuota_PerMinuteQueuesWithinMaxWait(t *testing.T) {
+	// arrange
+	clock := newFakeClock()
+	quota := newQuotaWithClock(1200, 0, time.Second, clock.Now)
+	for range 1200 {
+		require.NoError(t, quota.Acquire(context.Background()))
+	}
+
+	// act
+	start := time.Now()
+	err := quota.Acquire(context.Background())
+
+	// assert
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond, "a chamada deve aguardar o próximo token")
+}
+
+func TestQuota_QueuedCallerCanceled(t *testing.T) {
+	// arrange
+	clock := newFakeClock()
+	quota := newQuotaWithClock(1, 0, time.Hour, clock.Now)
+	require.NoError(t, quota.Acquire(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// act
+	err := quota.Acquire(ctx)
+
+	// assert
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestQuota_MonthlyBudget(t *testing.T) {
+	// arrange
+	clock := newFakeClock()
+	quota := newQuotaWithClock(0, 2, 0, clock.Now)
+
+	// act
+	first := quota.Acquire(context.Background())
+	second := quota.Acquire(context.Background())
+	third := quota.Acquire(context.Background())
+
+	// assert
+	require.NoError(t, first)
+	require.NoError(t, second)
+
+	var quotaErr *cErrors.QuotaError
+	require.ErrorAs(t, third, &quotaErr)
+	assert.Equal(t, cErrors.QuotaWindowMonth, quotaErr.Window)
+	assert.Equal(t, time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC).Sub(clock.now), quotaErr.RetryAfter)
+
+	remaining, known := quota.Remaining()
+	assert.True(t, known)
+	assert.Zero(t, remaining)
+
+	clock.now = time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)
+	remaining, _ = quota.Remaining()
+	assert.Equal(t, int64(2), remaining, "o orçamento deve ser renovado no início do mês")
+	assert.NoError(t, quota.Acquire(context.Background()))
+}
+
+func TestQuota_MonthlyBudgetConcurrent(t *testing.T) {
+	// arrange
+	const monthly, callers = 5, 50
+	quota := newQuotaWithClock(0, monthly, 0, newFakeClock().Now)
+
+	var allowed atomic.Int64
+	var wg sync.WaitGroup
+
+	// act
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if quota.Acquire(context.Background()) == nil {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// assert
+	assert.Equal(t, int64(monthly), allowed.Load(), "chamadas concorrentes não podem exceder o orçamento mensal")
+	quota.mu.Lock()
+	defer quota.mu.Unlock()
+	assert.LessOrEqual(t, quota.used, monthly)
+}
+
+func TestQuota_RefundsMonthlyOnRejection(t *testing.T) {
+	t.Run("Limite por minuto excedido", func(t *testing.T) {
+		// arrange
+		quota := newQuotaWithClock(1, 2, 0, newFakeClock().Now)
+		require.NoError(t, quota.Acquire(context.Background()))
+
+		// act
+		err := quota.Acquire(context.Background())
+
+		// assert
+		var quotaErr *cErrors.QuotaError
+		require.ErrorAs(t, err, &quotaErr)
+		assert.Equal(t, cErrors.QuotaWindowMinute, quotaErr.Window)
+		remaining, _ := quota.Remaining()
+		assert.Equal(t, int64(1), remaining, "a chamada rejeitada deve ser devolvida ao orçamento mensal")
+	})
+
+	t.Run("Contexto cancelado na fila", func(t *testing.T) {
+		// arrange
+		quota := newQuotaWithClock(1, 2, time.Hour, newFakeClock().Now)
+		require.NoError(t, quota.Acquire(context.Background()))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		// act
+		err := quota.Acquire(ctx)
+
+		// assert
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		remaining, _ := quota.Remaining()
+		assert.Equal(t, int64(1), remaining, "a chamada cancelada deve ser devolvida ao orçamento mensal")
+	})
+}
+
+func TestQuota_ObserveProviderHeaders(t *testing.T) {
+	testCases := []struct {
+		name     string
+		header   http.Header
+		expected int64
+		known    bool
+	}{
+		{"X-RateLimit-Remaining", http.Header{"X-Ratelimit-Remaining": {"42"}}, 42, true},
+		{"RateLimit-Remaining", http.Header{"Ratelimit-Remaining": {"7"}}, 7, true},
+		{"Sem cabeçalho", http.Header{}, 0, false},
+		{"Valor inválido", http.Header{"X-Ratelimit-Remaining": {"abc"}}, 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			quota := newQuotaWithClock(60, 0, 0, newFakeClock().Now)
+
+			// act
+			quota.Observe(tc.header)
+			remaining, known := quota.Remaining()
+
+			// assert
+			assert.Equal(t, tc.known, known)
+			assert.Equal(t, tc.expected, remaining)
+		})
+	}
+}
+
+func TestQuota_ProviderExhausted(t *testing.T) {
+	// arrange
+	quota := newQuotaWithClock(0, 100, 0, newFakeClock().Now)
+	require.NoError(t, quota.Acquire(context.Background()))
+	quota.Observe(http.Header{"X-Ratelimit-Remaining": {"1"}})
+
+	// act
+	first := quota.Acquire(context.Background())
+	second := quota.Acquire(context.Background())
+
+	// assert
+	require.NoError(t, first)
+
+	var quotaErr *cErrors.QuotaError
+	require.ErrorAs(t, second, &quotaErr)
+	assert.Equal(t, cErrors.QuotaWindowProvider, quotaErr.Window)
+
+	remaining, known := quota.Remaining()
+	assert.True(t, known)
+	assert.Zero(t, remaining, "o menor valor entre o orçamento local e o do provedor deve ser reportado")
+}
+
+func TestQuota_ProviderWindowExpires(t *testing.T) {
+	// arrange
+	clock := newFakeClock()
+	quota := newQuotaWithClock(0, 100, 0, clock.Now)
+	quota.Observe(http.Header{"X-Ratelimit-Remaining": {"0"}})
+
+	// act
+	blocked := quota.Acquire(context.Background())
+	clock.now = clock.now.Add(providerTTL)
+	probe := quota.Acquire(context.Background())
+	heldBack := quota.Acquire(context.Background())
+	quota.Observe(http.Header{"X-Ratelimit-Remaining": {"5"}})
+	afterReset := quota.Acquire(context.Background())
+
+	// assert
+	var quotaErr *cErrors.QuotaError
+	require.ErrorAs(t, blocked, &quotaErr)
+	assert.Equal(t, cErrors.QuotaWindowProvider, quotaErr.Window)
+	assert.Equal(t, providerTTL, quotaErr.RetryAfter, "sem cabeçalho de reset a janela do provedor vale por providerTTL")
+
+	require.NoError(t, probe, "uma chamada de sondagem deve passar quando a janela expira")
+
+	require.ErrorAs(t, heldBack, &quotaErr)
+	assert.Equal(t, probeInterval, quotaErr.RetryAfter, "as demais chamadas aguardam a próxima sondagem")
+
+	require.NoError(t, afterReset)
+	remaining, known := quota.Remaining()
+	assert.True(t, known)
+	assert.Equal(t, int64(4), remaining)
+}
+
+func TestQuota_ProviderResetHeaders(t *testing.T) {
+	clock := newFakeClock()
+
+	testCases := []struct {
+		name       string
+		header     http.Header
+		retryAfter time.Duration
+	}{
+		{"Segundos até o reset", http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"30"}}, 30 * time.Second},
+		{"Horário Unix do reset", http.Header{"Ratelimit-Remaining": {"0"}, "Ratelimit-Reset": {strconv.FormatInt(clock.now.Add(time.Hour).Unix(), 10)}}, time.Hour},
+		{"Reset no passado", http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {strconv.FormatInt(clock.now.Add(-time.Hour).Unix(), 10)}}, providerTTL},
+		{"Reset inválido", http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"soon"}}, providerTTL},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			quota := newQuotaWithClock(0, 100, 0, clock.Now)
+			quota.Observe(tc.header)
+
+			// act
+			err := quota.Acquire(context.Background())
+
+			// assert
+			var quotaErr *cErrors.QuotaError
+			require.ErrorAs(t, err, &quotaErr)
+			assert.Equal(t, tc.retryAfter, quotaErr.RetryAfter)
+		})
+	}
+}
+
+func TestQuota_ProviderCountForgottenAfterReset(t *testing.T) {
+	// arrange
+	clock := newFakeClock()
+	quota := newQuotaWithClock(60, 0, 0, clock.Now)
+	quota.Observe(http.Header{"X-Ratelimit-Remaining": {"3"}, "X-Ratelimit-Reset": {"60"}})
+
+	// act
+	clock.now = clock.now.Add(time.Minute)
+	_, known := quota.Remaining()
+
+	// assert
+	assert.False(t, known, "a contagem do provedor não vale depois do reset da janela")
+}
@@ -2,37 +2,61 @@ package client
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"time"
 
 	cErrors "github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client/error"
 	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/metrics"
 	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/model"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const tracerName = "github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client"
+
 type WeatherClientInterface interface {
 	GetWeather(ctx context.Context, city string) (*model.WeatherResponse, error)
+	GetForecast(ctx context.Context, city string, days int) (*model.ForecastResponse, error)
+	GetHistory(ctx context.Context, city string, date time.Time) (*model.ForecastResponse, error)
 }
 
 type WeatherClient struct {
-	config *config.Config
-	client *http.Client
+	config  *config.Config
+	client  *http.Client
+	metrics *metrics.Metrics
+	quota   *Quota
 }
 
-func NewWeatherClient(cfg *config.Config) *WeatherClient {
+func NewWeatherClient(cfg *config.Config, m *metrics.Metrics) *WeatherClient {
+	quota := NewQuota(cfg.WeatherQuotaMinute, cfg.WeatherQuotaMonthly, cfg.WeatherQuotaMaxWait)
+	if err := m.ObserveQuota(metrics.UpstreamWeatherAPI, quota.Remaining); err != nil {
+		slog.Warn("Failed to observe WeatherAPI quota", "error", err)
+	}
+
 	return &WeatherClient{
-		config: cfg,
+		config:  cfg,
+		metrics: m,
+		quota:   quota,
 		client: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout:   10 * time.Second,
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
 		},
 	}
 }
 
-func (w WeatherClient) GetWeather(ctx context.Context, city string) (*model.WeatherResponse, error) {
+func (w WeatherClient) GetWeather(ctx context.Context, city string) (res *model.WeatherResponse, err error) {
+	start := time.Now()
+	defer func() {
+		w.metrics.RecordUpstream(ctx, metrics.UpstreamWeatherAPI, "GetWeather", time.Since(start), err)
+	}()
+
 	weatherApiUrl := fmt.Sprintf("%s?key=%s&q=%s&aqi=no",
 		w.config.WeatherBaseURL,
 		w.config.WeatherAPIKey,
@@ -43,26 +67,149 @@ func (w WeatherClient) GetWeather(ctx context.Context, city string) (*model.Weat
 		return nil, err
 	}
 
+	if err := w.quota.Acquire(ctx); err != nil {
+		return nil, err
+	}
+
 	resp, err := w.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
+	w.quota.Observe(resp.Header)
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, cErrors.NewWeatherClientHTTPError(resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := readBody(ctx, w.config.TraceInternalSpans, resp.Body)
 	if err != nil {
 		return nil, err
 	}
 
 	var weatherRes model.WeatherResponse
-	err = json.Unmarshal(body, &weatherRes)
+	err = unmarshal(ctx, w.config.TraceInternalSpans, body, &weatherRes)
 	if err != nil {
 		return nil, err
 	}
 
 	return &weatherRes, nil
 }
+
+func (w WeatherClient) GetForecast(ctx context.Context, city string, days int) (res *model.ForecastResponse, err error) {
+	start := time.Now()
+	defer func() {
+		w.metrics.RecordUpstream(ctx, metrics.UpstreamWeatherAPI, "GetForecast", time.Since(start), err)
+	}()
+
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "WeatherAPI GetForecast",
+		trace.WithAttributes(
+			attribute.String("weather.city", city),
+			attribute.Int("weather.forecast.days", days),
+		))
+	defer span.End()
+
+	forecastApiUrl := fmt.Sprintf("%s?key=%s&q=%s&days=%d&aqi=no&alerts=no",
+		w.config.WeatherForecastURL,
+		w.config.WeatherAPIKey,
+		url.QueryEscape(city),
+		days)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", forecastApiUrl, nil)
+	if err != nil {
+		return nil, recordSpanError(span, err)
+	}
+
+	if err := w.quota.Acquire(ctx); err != nil {
+		return nil, recordSpanError(span, err)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, recordSpanError(span, err)
+	}
+	defer resp.Body.Close()
+	w.quota.Observe(resp.Header)
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, recordSpanError(span, cErrors.NewWeatherClientHTTPError(resp.StatusCode))
+	}
+
+	body, err := readBody(ctx, w.config.TraceInternalSpans, resp.Body)
+	if err != nil {
+		return nil, recordSpanError(span, err)
+	}
+
+	var forecastRes model.ForecastResponse
+	err = unmarshal(ctx, w.config.TraceInternalSpans, body, &forecastRes)
+	if err != nil {
+		return nil, recordSpanError(span, err)
+	}
+
+	return &forecastRes, nil
+}
+
+func (w WeatherClient) GetHistory(ctx context.Context, city string, date time.Time) (res *model.ForecastResponse, err error) {
+	start := time.Now()
+	defer func() {
+		w.metrics.RecordUpstream(ctx, metrics.UpstreamWeatherAPI, "GetHistory", time.Since(start), err)
+	}()
+
+	dt := date.Format(time.DateOnly)
+
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "WeatherAPI GetHistory",
+		trace.WithAttributes(
+			attribute.String("weather.city", city),
+			attribute.String("weather.history.date", dt),
+		))
+	defer span.End()
+
+	historyApiUrl := fmt.Sprintf("%s?key=%s&q=%s&dt=%s",
+		w.config.WeatherHistoryURL,
+		w.config.WeatherAPIKey,
+		url.QueryEscape(city),
+		dt)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", historyApiUrl, nil)
+	if err != nil {
+		return nil, recordSpanError(span, err)
+	}
+
+	if err := w.quota.Acquire(ctx); err != nil {
+		return nil, recordSpanError(span, err)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, recordSpanError(span, err)
+	}
+	defer resp.Body.Close()
+	w.quota.Observe(resp.Header)
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, recordSpanError(span, cErrors.NewWeatherClientHTTPError(resp.StatusCode))
+	}
+
+	body, err := readBody(ctx, w.config.TraceInternalSpans, resp.Body)
+	if err != nil {
+		return nil, recordSpanError(span, err)
+	}
+
+	var historyRes model.ForecastResponse
+	err = unmarshal(ctx, w.config.TraceInternalSpans, body, &historyRes)
+	if err != nil {
+		return nil, recordSpanError(span, err)
+	}
+
+	return &historyRes, nil
+}
+
+func recordSpanError(span trace.Span, err error) error {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	return err
+}
@@ -0,0 +1,217 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	cErrors "github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client/error"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func setupSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	previous, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	return recorder
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestWeatherClient_GetForecast_Success(t *testing.T) {
+	// arrange
+	recorder := setupSpanRecorder(t)
+
+	var query map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = map[string]string{
+			"key":  r.URL.Query().Get("key"),
+			"q":    r.URL.Query().Get("q"),
+			"days": r.URL.Query().Get("days"),
+		}
+		_ = json.NewEncoder(w).Encode(model.GetForecastResponseMock("Sao Paulo", 3))
+	}))
+	defer server.Close()
+
+	client := NewWeatherClient(&config.Config{
+		WeatherAPIKey:      "test-api-key",
+		WeatherForecastURL: server.URL,
+	}, nil)
+
+	// act
+	result, err := client.GetForecast(context.Background(), "São Paulo", 3)
+
+	// assert
+	require.NoError(t, err)
+	assert.Len(t, result.Forecast.Forecastday, 3)
+	assert.Equal(t, "Sao Paulo", result.Location.Name)
+	assert.Equal(t, 32.2, result.Current.TempC)
+	assert.Equal(t, map[string]string{"key": "test-api-key", "q": "São Paulo", "days": "3"}, query)
+
+	spans := endedSpans(recorder.Ended())
+	require.Len(t, spans, 2)
+	forecast, request := spans["WeatherAPI GetForecast"], spans["HTTP GET"]
+	require.NotNil(t, forecast)
+	require.NotNil(t, request)
+	assert.Equal(t, trace.SpanKindInternal, forecast.SpanKind())
+	assert.Equal(t, "São Paulo", spanAttribute(forecast, "weather.city").AsString())
+	assert.Equal(t, int64(3), spanAttribute(forecast, "weather.forecast.days").AsInt64())
+	assert.Equal(t, int64(http.StatusOK), spanAttribute(forecast, "http.response.status_code").AsInt64())
+	assert.Equal(t, trace.SpanKindClient, request.SpanKind())
+	assert.Equal(t, forecast.SpanContext().SpanID(), request.Parent().SpanID(), "a chamada HTTP deve ser filha do span da operação")
+}
+
+func TestWeatherClient_GetForecast_HTTPError(t *testing.T) {
+	// arrange
+	recorder := setupSpanRecorder(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := NewWeatherClient(&config.Config{WeatherForecastURL: server.URL}, nil)
+
+	// act
+	result, err := client.GetForecast(context.Background(), "Nowhere", 1)
+
+	// assert
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, cErrors.WeatherClientBadRequest)
+
+	spans := endedSpans(recorder.Ended())
+	require.Contains(t, spans, "WeatherAPI GetForecast")
+	assert.Equal(t, codes.Error, spans["WeatherAPI GetForecast"].Status().Code)
+	assert.NotEmpty(t, spans["WeatherAPI GetForecast"].Events())
+}
+
+func TestWeatherClient_GetForecast_InvalidJSON(t *testing.T) {
+	// arrange
+	setupSpanRecorder(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("{invalid"))
+	}))
+	defer server.Close()
+
+	client := NewWeatherClient(&config.Config{WeatherForecastURL: server.URL}, nil)
+
+	// act
+	result, err := client.GetForecast(context.Background(), "São Paulo", 1)
+
+	// assert
+	assert.Nil(t, result)
+	assert.Error(t, err)
+}
+
+func TestWeatherClient_GetHistory_Success(t *testing.T) {
+	// arrange
+	recorder := setupSpanRecorder(t)
+
+	var dt string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dt = r.URL.Query().Get("dt")
+		_ = json.NewEncoder(w).Encode(model.GetForecastResponseMock("Sao Paulo", 1))
+	}))
+	defer server.Close()
+
+	client := NewWeatherClient(&config.Config{WeatherHistoryURL: server.URL}, nil)
+
+	// act
+	result, err := client.GetHistory(context.Background(), "São Paulo", time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC))
+
+	// assert
+	require.NoError(t, err)
+	assert.Len(t, result.Forecast.Forecastday, 1)
+	assert.Equal(t, "2026-01-10", dt)
+
+	spans := endedSpans(recorder.Ended())
+	require.Contains(t, spans, "WeatherAPI GetHistory")
+	assert.Equal(t, "2026-01-10", spanAttribute(spans["WeatherAPI GetHistory"], "weather.history.date").AsString())
+}
+
+func TestWeatherClient_GetForecast_QuotaExceeded(t *testing.T) {
+	// arrange
+	recorder := setupSpanRecorder(t)
+
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(2-calls))
+		_ = json.NewEncoder(w).Encode(model.GetForecastResponseMock("Sao Paulo", 1))
+	}))
+	defer server.Close()
+
+	client := NewWeatherClient(&config.Config{WeatherForecastURL: server.URL, WeatherQuotaMonthly: 1000}, nil)
+
+	// act
+	_, first := client.GetForecast(context.Background(), "São Paulo", 1)
+	_, second := client.GetForecast(context.Background(), "São Paulo", 1)
+	_, third := client.GetForecast(context.Background(), "São Paulo", 1)
+
+	// assert
+	require.NoError(t, first)
+	require.NoError(t, second)
+	assert.ErrorIs(t, third, cErrors.WeatherClientQuotaExceeded)
+	assert.Equal(t, 2, calls, "a chamada acima da cota não deve chegar ao provedor")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 5, "três operações e duas chamadas HTTP")
+	assert.Equal(t, "WeatherAPI GetForecast", spans[4].Name())
+	assert.Equal(t, codes.Error, spans[4].Status().Code)
+}
+
+func TestWeatherClient_GetWeather_ClientSpan(t *testing.T) {
+	// arrange
+	recorder := setupSpanRecorder(t)
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		_ = json.NewEncoder(w).Encode(model.GetWeatherResponseMock("São Paulo"))
+	}))
+	defer server.Close()
+
+	client := NewWeatherClient(&config.Config{WeatherBaseURL: server.URL}, nil)
+
+	// act
+	_, err := client.GetWeather(context.Background(), "São Paulo")
+
+	// assert
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "HTTP GET", spans[0].Name())
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+	assert.Contains(t, traceparent, spans[0].SpanContext().TraceID().String(), "o contexto do trace deve seguir para o provedor")
+}
@@ -1,20 +1,62 @@
 package config
 
 import (
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
 	"os"
+	"time"
 
+	"github.com/alexduzi/laboteldistributedtracing/platform/telemetry"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Port           string
-	WeatherAPIKey  string
-	ViaCEPBaseURL  string
-	WeatherBaseURL string
-	GinMode        string
+	Port                 string
+	GRPCPort             string
+	WeatherAPIKey        string
+	ViaCEPBaseURL        string
+	WeatherBaseURL       string
+	WeatherForecastURL   string
+	WeatherHistoryURL    string
+	WeatherQuotaMinute   int
+	WeatherQuotaMonthly  int
+	WeatherQuotaMaxWait  time.Duration
+	HistoryMaxDays       int
+	HistoryConcurrency   int
+	BatchMaxSize         int
+	BatchConcurrency     int
+	TenantRateLimit      float64
+	TenantRateBurst      int
+	CacheTTL             time.Duration
+	CacheMaxEntries      int
+	CepPrefixFallback    bool
+	LegacyErrors         bool
+	HealthCheckTTL       time.Duration
+	HealthCheckTimeout   time.Duration
+	ShutdownGracePeriod  time.Duration
+	GinMode              string
+	ServiceName          string
+	OtelExporterURL      string
+	OtelExporterProtocol string
+	TracesExporter       string
+	ZipkinEndpoint       string
+	TracesSampler        string
+	TracesSamplerArg     float64
+	TraceDebugHeader     bool
+	TraceInternalSpans   bool
+	MetricsMode          string
+	LogLevel             string
+	LogExport            bool
 }
 
+// Metrics modes: push exports over OTLP, pull serves /metrics for Prometheus to scrape
+const (
+	MetricsModePush = telemetry.MetricsModePush
+	MetricsModePull = telemetry.MetricsModePull
+)
+
 var AppConfig *Config
 
 // LoadConfig loads configuration from environment variables using Viper
@@ -31,40 +73,155 @@ func LoadConfig() (*Config, error) {
 		port = "8080" // Default fallback
 	}
 
+	viper.SetDefault("GRPC_PORT", "50051")
 	viper.SetDefault("VIA_CEP_BASE_URL", "https://viacep.com.br/ws/{cep}/json/")
 	viper.SetDefault("WEATHER_BASE_URL", "http://api.weatherapi.com/v1/current.json")
-	viper.SetDefault("GIN_MODE", "debug") // debug, release, or test
+	viper.SetDefault("WEATHER_FORECAST_URL", "http://api.weatherapi.com/v1/forecast.json")
+	viper.SetDefault("WEATHER_HISTORY_URL", "http://api.weatherapi.com/v1/history.json")
+	viper.SetDefault("WEATHER_QUOTA_PER_MINUTE", 0) // outbound WeatherAPI calls, 0 disables
+	viper.SetDefault("WEATHER_QUOTA_MONTHLY", 0)    // outbound WeatherAPI calls, 0 disables
+	viper.SetDefault("WEATHER_QUOTA_MAX_WAIT", "2s")
+	viper.SetDefault("HISTORY_MAX_DAYS", 31)
+	viper.SetDefault("HISTORY_CONCURRENCY", 4)
+	viper.SetDefault("BATCH_MAX_SIZE", 250)
+	viper.SetDefault("BATCH_CONCURRENCY", 10)
+	viper.SetDefault("TENANT_RATE_LIMIT", 0) // requests per second per tenant, 0 disables
+	viper.SetDefault("TENANT_RATE_BURST", 20)
+	viper.SetDefault("CACHE_TTL", "60s") // 0 disables the temperature cache
+	viper.SetDefault("CACHE_MAX_ENTRIES", 1000)
+	viper.SetDefault("CEP_PREFIX_FALLBACK", true) // resolve the CEP offline when the providers are down
+	viper.SetDefault("LEGACY_ERRORS", false)      // render errors as {"message": ...} instead of problem+json
+	viper.SetDefault("HEALTH_CHECK_TTL", "30s")   // how long a readiness check result is reused
+	viper.SetDefault("HEALTH_CHECK_TIMEOUT", "2s")
+	viper.SetDefault("SHUTDOWN_GRACE_PERIOD", "15s") // time to drain in-flight requests, and then to flush telemetry
+	viper.SetDefault("GIN_MODE", "debug")            // debug, release, or test
+	viper.SetDefault("OTEL_SERVICE_NAME", "weather-engine")
+	viper.SetDefault("OTEL_EXPORTER_OTLP_PROTOCOL", "http/protobuf") // http/protobuf or grpc
+	viper.SetDefault("OTEL_TRACES_EXPORTER", "otlp")                 // otlp, zipkin, console, stdout, or none
+	viper.SetDefault("OTEL_EXPORTER_ZIPKIN_ENDPOINT", "http://localhost:9411/api/v2/spans")
+	viper.SetDefault("OTEL_TRACES_SAMPLER", "parentbased_always_on")
+	viper.SetDefault("OTEL_TRACES_SAMPLER_ARG", 1.0)
+	viper.SetDefault("TRACE_DEBUG_HEADER", false) // honour X-Debug-Trace, which lets any caller force sampling
+	viper.SetDefault("TRACE_INTERNAL_SPANS", true)
+	viper.SetDefault("METRICS_MODE", MetricsModePush)
+	viper.SetDefault("LOG_LEVEL", "info") // debug, info, warn, or error
+	viper.SetDefault("LOG_EXPORT_OTLP", true)
 
 	// Try to read .env file, but don't fail if it doesn't exist
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			log.Println("No .env file found, using environment variables and defaults")
+			slog.Info("No .env file found, using environment variables and defaults")
 		} else {
-			log.Printf("Error reading config file: %v", err)
+			slog.Error("Error reading config file", "error", err)
 		}
 	}
 
 	config := &Config{
-		Port:           port,
-		WeatherAPIKey:  viper.GetString("WEATHER_API_KEY"),
-		ViaCEPBaseURL:  viper.GetString("VIA_CEP_BASE_URL"),
-		WeatherBaseURL: viper.GetString("WEATHER_BASE_URL"),
-		GinMode:        viper.GetString("GIN_MODE"),
+		Port:                 port,
+		GRPCPort:             viper.GetString("GRPC_PORT"),
+		WeatherAPIKey:        viper.GetString("WEATHER_API_KEY"),
+		ViaCEPBaseURL:        viper.GetString("VIA_CEP_BASE_URL"),
+		WeatherBaseURL:       viper.GetString("WEATHER_BASE_URL"),
+		WeatherForecastURL:   viper.GetString("WEATHER_FORECAST_URL"),
+		WeatherHistoryURL:    viper.GetString("WEATHER_HISTORY_URL"),
+		WeatherQuotaMinute:   viper.GetInt("WEATHER_QUOTA_PER_MINUTE"),
+		WeatherQuotaMonthly:  viper.GetInt("WEATHER_QUOTA_MONTHLY"),
+		WeatherQuotaMaxWait:  viper.GetDuration("WEATHER_QUOTA_MAX_WAIT"),
+		HistoryMaxDays:       viper.GetInt("HISTORY_MAX_DAYS"),
+		HistoryConcurrency:   viper.GetInt("HISTORY_CONCURRENCY"),
+		BatchMaxSize:         viper.GetInt("BATCH_MAX_SIZE"),
+		BatchConcurrency:     viper.GetInt("BATCH_CONCURRENCY"),
+		TenantRateLimit:      viper.GetFloat64("TENANT_RATE_LIMIT"),
+		TenantRateBurst:      viper.GetInt("TENANT_RATE_BURST"),
+		CacheTTL:             viper.GetDuration("CACHE_TTL"),
+		CacheMaxEntries:      viper.GetInt("CACHE_MAX_ENTRIES"),
+		CepPrefixFallback:    viper.GetBool("CEP_PREFIX_FALLBACK"),
+		LegacyErrors:         viper.GetBool("LEGACY_ERRORS"),
+		HealthCheckTTL:       viper.GetDuration("HEALTH_CHECK_TTL"),
+		HealthCheckTimeout:   viper.GetDuration("HEALTH_CHECK_TIMEOUT"),
+		ShutdownGracePeriod:  viper.GetDuration("SHUTDOWN_GRACE_PERIOD"),
+		GinMode:              viper.GetString("GIN_MODE"),
+		ServiceName:          viper.GetString("OTEL_SERVICE_NAME"),
+		OtelExporterURL:      otlpEndpoint(viper.GetString("OTEL_EXPORTER_OTLP_ENDPOINT"), viper.GetString("OTEL_EXPORTER_OTLP_PROTOCOL")),
+		OtelExporterProtocol: viper.GetString("OTEL_EXPORTER_OTLP_PROTOCOL"),
+		TracesExporter:       viper.GetString("OTEL_TRACES_EXPORTER"),
+		ZipkinEndpoint:       viper.GetString("OTEL_EXPORTER_ZIPKIN_ENDPOINT"),
+		TracesSampler:        viper.GetString("OTEL_TRACES_SAMPLER"),
+		TracesSamplerArg:     viper.GetFloat64("OTEL_TRACES_SAMPLER_ARG"),
+		TraceDebugHeader:     viper.GetBool("TRACE_DEBUG_HEADER"),
+		TraceInternalSpans:   viper.GetBool("TRACE_INTERNAL_SPANS"),
+		MetricsMode:          viper.GetString("METRICS_MODE"),
+		LogLevel:             viper.GetString("LOG_LEVEL"),
+		LogExport:            viper.GetBool("LOG_EXPORT_OTLP"),
 	}
 
-	// Validate required fields
-	if config.WeatherAPIKey == "" {
-		log.Println("Warning: WEATHER_API_KEY is not set")
+	// Readiness reports invalid settings; starting anyway keeps liveness probes answering
+	if err := config.Validate(); err != nil {
+		slog.Warn("Invalid configuration", "error", err)
 	}
 
 	AppConfig = config
 	return config, nil
 }
 
+// Validate reports the first setting that prevents the weather-engine from serving requests
+func (c *Config) Validate() error {
+	if c.WeatherAPIKey == "" {
+		return errors.New("WEATHER_API_KEY is not set")
+	}
+
+	urls := []struct{ name, value string }{
+		{"VIA_CEP_BASE_URL", c.ViaCEPBaseURL},
+		{"WEATHER_BASE_URL", c.WeatherBaseURL},
+		{"WEATHER_FORECAST_URL", c.WeatherForecastURL},
+		{"WEATHER_HISTORY_URL", c.WeatherHistoryURL},
+	}
+	for _, setting := range urls {
+		if u, err := url.Parse(setting.value); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("%s is not an absolute URL: %q", setting.name, setting.value)
+		}
+	}
+
+	switch c.MetricsMode {
+	case MetricsModePush, MetricsModePull, "":
+	default:
+		return fmt.Errorf("unknown METRICS_MODE %q", c.MetricsMode)
+	}
+	return nil
+}
+
+// otlpEndpoint returns endpoint, or when it is unset the local collector port for
+// protocol: 4317 for grpc and 4318 for http/protobuf
+func otlpEndpoint(endpoint, protocol string) string {
+	switch {
+	case endpoint != "":
+		return endpoint
+	case protocol == "grpc":
+		return "http://localhost:4317"
+	default:
+		return "http://localhost:4318"
+	}
+}
+
+// Telemetry selects the exporters and sampler for the shared telemetry providers
+func (c *Config) Telemetry() telemetry.Config {
+	return telemetry.Config{
+		ServiceName:      c.ServiceName,
+		TracesExporter:   c.TracesExporter,
+		Protocol:         c.OtelExporterProtocol,
+		Endpoint:         c.OtelExporterURL,
+		ZipkinEndpoint:   c.ZipkinEndpoint,
+		TracesSampler:    c.TracesSampler,
+		TracesSamplerArg: c.TracesSamplerArg,
+		MetricsMode:      c.MetricsMode,
+	}
+}
+
 // GetConfig returns the current configuration
 func GetConfig() *Config {
 	if AppConfig == nil {
-		log.Fatal("Config not initialized. Call LoadConfig() first.")
+		slog.Error("Config not initialized. Call LoadConfig() first.")
+		os.Exit(1)
 	}
 	return AppConfig
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -19,10 +20,40 @@ func TestLoadConfig_WithDefaultValues(t *testing.T) {
 
 	// Garantir que não há variáveis de ambiente configuradas
 	os.Unsetenv("PORT")
+	os.Unsetenv("GRPC_PORT")
 	os.Unsetenv("WEATHER_API_KEY")
 	os.Unsetenv("VIA_CEP_BASE_URL")
 	os.Unsetenv("WEATHER_BASE_URL")
+	os.Unsetenv("WEATHER_FORECAST_URL")
+	os.Unsetenv("WEATHER_HISTORY_URL")
+	os.Unsetenv("HISTORY_MAX_DAYS")
+	os.Unsetenv("WEATHER_QUOTA_PER_MINUTE")
+	os.Unsetenv("WEATHER_QUOTA_MONTHLY")
+	os.Unsetenv("WEATHER_QUOTA_MAX_WAIT")
+	os.Unsetenv("HISTORY_CONCURRENCY")
+	os.Unsetenv("BATCH_MAX_SIZE")
+	os.Unsetenv("BATCH_CONCURRENCY")
+	os.Unsetenv("TENANT_RATE_LIMIT")
+	os.Unsetenv("TENANT_RATE_BURST")
+	os.Unsetenv("CACHE_TTL")
+	os.Unsetenv("CACHE_MAX_ENTRIES")
+	os.Unsetenv("CEP_PREFIX_FALLBACK")
+	os.Unsetenv("LEGACY_ERRORS")
+	os.Unsetenv("HEALTH_CHECK_TTL")
+	os.Unsetenv("HEALTH_CHECK_TIMEOUT")
+	os.Unsetenv("SHUTDOWN_GRACE_PERIOD")
 	os.Unsetenv("GIN_MODE")
+	os.Unsetenv("OTEL_SERVICE_NAME")
+	os.Unsetenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	os.Unsetenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	os.Unsetenv("OTEL_TRACES_EXPORTER")
+	os.Unsetenv("OTEL_EXPORTER_ZIPKIN_ENDPOINT")
+	os.Unsetenv("OTEL_TRACES_SAMPLER")
+	os.Unsetenv("OTEL_TRACES_SAMPLER_ARG")
+	os.Unsetenv("TRACE_INTERNAL_SPANS")
+	os.Unsetenv("METRICS_MODE")
+	os.Unsetenv("LOG_LEVEL")
+	os.Unsetenv("LOG_EXPORT_OTLP")
 
 	// act
 	config, err := LoadConfig()
@@ -31,10 +62,40 @@ func TestLoadConfig_WithDefaultValues(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, config)
 	assert.Equal(t, "8080", config.Port)
+	assert.Equal(t, "50051", config.GRPCPort)
 	assert.Equal(t, "", config.WeatherAPIKey)
 	assert.Equal(t, "https://viacep.com.br/ws/{cep}/json/", config.ViaCEPBaseURL)
 	assert.Equal(t, "http://api.weatherapi.com/v1/current.json", config.WeatherBaseURL)
+	assert.Equal(t, "http://api.weatherapi.com/v1/forecast.json", config.WeatherForecastURL)
+	assert.Equal(t, "http://api.weatherapi.com/v1/history.json", config.WeatherHistoryURL)
+	assert.Equal(t, 0, config.WeatherQuotaMinute)
+	assert.Equal(t, 0, config.WeatherQuotaMonthly)
+	assert.Equal(t, 2*time.Second, config.WeatherQuotaMaxWait)
+	assert.Equal(t, 31, config.HistoryMaxDays)
+	assert.Equal(t, 4, config.HistoryConcurrency)
+	assert.Equal(t, 250, config.BatchMaxSize)
+	assert.Equal(t, 10, config.BatchConcurrency)
+	assert.Equal(t, 0.0, config.TenantRateLimit)
+	assert.Equal(t, 20, config.TenantRateBurst)
+	assert.Equal(t, 60*time.Second, config.CacheTTL)
+	assert.Equal(t, 1000, config.CacheMaxEntries)
+	assert.True(t, config.CepPrefixFallback)
+	assert.False(t, config.LegacyErrors)
+	assert.Equal(t, 30*time.Second, config.HealthCheckTTL)
+	assert.Equal(t, 2*time.Second, config.HealthCheckTimeout)
+	assert.Equal(t, 15*time.Second, config.ShutdownGracePeriod)
 	assert.Equal(t, "debug", config.GinMode)
+	assert.Equal(t, "weather-engine", config.ServiceName)
+	assert.Equal(t, "http://localhost:4318", config.OtelExporterURL)
+	assert.Equal(t, "http/protobuf", config.OtelExporterProtocol)
+	assert.Equal(t, "otlp", config.TracesExporter)
+	assert.Equal(t, "http://localhost:9411/api/v2/spans", config.ZipkinEndpoint)
+	assert.Equal(t, "parentbased_always_on", config.TracesSampler)
+	assert.Equal(t, 1.0, config.TracesSamplerArg)
+	assert.True(t, config.TraceInternalSpans)
+	assert.Equal(t, MetricsModePush, config.MetricsMode)
+	assert.Equal(t, "info", config.LogLevel)
+	assert.True(t, config.LogExport)
 	assert.Equal(t, config, AppConfig)
 }
 
@@ -236,6 +297,48 @@ func TestConfig_StructureFields(t *testing.T) {
 	assert.Equal(t, "debug", config.GinMode)
 }
 
+func TestConfig_Validate(t *testing.T) {
+	valid := func() *Config {
+		return &Config{
+			WeatherAPIKey:      "test-api-key",
+			ViaCEPBaseURL:      "https://viacep.com.br/ws/{cep}/json/",
+			WeatherBaseURL:     "http://api.weatherapi.com/v1/current.json",
+			WeatherForecastURL: "http://api.weatherapi.com/v1/forecast.json",
+			WeatherHistoryURL:  "http://api.weatherapi.com/v1/history.json",
+			MetricsMode:        MetricsModePush,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr string
+	}{
+		{"configuração válida", func(c *Config) {}, ""},
+		{"sem chave da WeatherAPI", func(c *Config) { c.WeatherAPIKey = "" }, "WEATHER_API_KEY"},
+		{"URL relativa", func(c *Config) { c.WeatherBaseURL = "/v1/current.json" }, "WEATHER_BASE_URL"},
+		{"modo de métricas desconhecido", func(c *Config) { c.MetricsMode = "poll" }, "METRICS_MODE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			config := valid()
+			tt.mutate(config)
+
+			// act
+			err := config.Validate()
+
+			// assert
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestLoadConfig_WithDifferentPorts(t *testing.T) {
 	tests := []struct {
 		name string
@@ -287,3 +390,36 @@ func TestLoadConfig_ConfigNotNil(t *testing.T) {
 	assert.NotNil(t, config)
 	assert.IsType(t, &Config{}, config)
 }
+
+func TestLoadConfig_OTLPEndpointDefaultsByProtocol(t *testing.T) {
+	tests := []struct {
+		name     string
+		protocol string
+		endpoint string
+		expected string
+	}{
+		{"http/protobuf sem endpoint", "http/protobuf", "", "http://localhost:4318"},
+		{"grpc sem endpoint", "grpc", "", "http://localhost:4317"},
+		{"grpc com endpoint", "grpc", "http://otel-collector:4317", "http://otel-collector:4317"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			resetViperAndConfig()
+			os.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", tt.protocol)
+			os.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", tt.endpoint)
+			defer func() {
+				os.Unsetenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+				os.Unsetenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+			}()
+
+			// act
+			config, err := LoadConfig()
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, config.OtelExporterURL)
+		})
+	}
+}
@@ -7,10 +7,44 @@ import (
 )
 
 func ConvertWeatherResponse(weather model.WeatherResponse) model.TemperatureResponse {
-	kelvin := weather.Current.TempC + 273.15
-	fahrenheit := weather.Current.TempC*1.8 + 32
+	return ConvertCelsius(weather.Current.TempC)
+}
+
+// ConvertForecastResponse converts every forecast day and hour into Celsius, Fahrenheit and Kelvin
+func ConvertForecastResponse(forecast model.ForecastResponse) model.TemperatureForecastResponse {
+	days := make([]model.DailyForecast, 0, len(forecast.Forecast.Forecastday))
+	for _, fd := range forecast.Forecast.Forecastday {
+		hours := make([]model.HourlyForecast, 0, len(fd.Hour))
+		for _, h := range fd.Hour {
+			hours = append(hours, model.HourlyForecast{
+				Time:         h.Time,
+				Temperature:  ConvertCelsius(h.TempC),
+				ChanceOfRain: h.ChanceOfRain,
+			})
+		}
+
+		days = append(days, model.DailyForecast{
+			Date:         fd.Date,
+			Min:          ConvertCelsius(fd.Day.MintempC),
+			Max:          ConvertCelsius(fd.Day.MaxtempC),
+			Avg:          ConvertCelsius(fd.Day.AvgtempC),
+			ChanceOfRain: fd.Day.DailyChanceOfRain,
+			Hours:        hours,
+		})
+	}
+
+	return model.TemperatureForecastResponse{
+		City: forecast.Location.Name,
+		Days: days,
+	}
+}
+
+// ConvertCelsius converts a Celsius temperature into all supported units
+func ConvertCelsius(celsius float64) model.TemperatureResponse {
+	kelvin := celsius + 273.15
+	fahrenheit := celsius*1.8 + 32
 	return model.TemperatureResponse{
-		Celsius:    roundToTwoDecimals(weather.Current.TempC),
+		Celsius:    roundToTwoDecimals(celsius),
 		Fahrenheit: roundToTwoDecimals(fahrenheit),
 		Kelvin:     roundToTwoDecimals(kelvin),
 	}
@@ -19,3 +53,43 @@ func ConvertWeatherResponse(weather model.WeatherResponse) model.TemperatureResp
 func roundToTwoDecimals(value float64) float64 {
 	return math.Round(value*100) / 100
 }
+
+// ConvertHistoryDays aggregates the observed days into per-day and whole-range temperature statistics
+func ConvertHistoryDays(city string, from, to string, history []model.ForecastDay) model.TemperatureHistoryResponse {
+	days := make([]model.DailyTemperature, 0, len(history))
+
+	var minC, maxC, sumAvgC float64
+	for i, hd := range history {
+		days = append(days, model.DailyTemperature{
+			Date: hd.Date,
+			TemperatureStats: model.TemperatureStats{
+				Min: ConvertCelsius(hd.Day.MintempC),
+				Max: ConvertCelsius(hd.Day.MaxtempC),
+				Avg: ConvertCelsius(hd.Day.AvgtempC),
+			},
+		})
+
+		if i == 0 || hd.Day.MintempC < minC {
+			minC = hd.Day.MintempC
+		}
+		if i == 0 || hd.Day.MaxtempC > maxC {
+			maxC = hd.Day.MaxtempC
+		}
+		sumAvgC += hd.Day.AvgtempC
+	}
+
+	response := model.TemperatureHistoryResponse{
+		City: city,
+		From: from,
+		To:   to,
+		Days: days,
+	}
+	if len(history) > 0 {
+		response.Summary = model.TemperatureStats{
+			Min: ConvertCelsius(minC),
+			Max: ConvertCelsius(maxC),
+			Avg: ConvertCelsius(sumAvgC / float64(len(history))),
+		}
+	}
+	return response
+}
@@ -132,3 +132,83 @@ func TestConvertWeatherResponse_RoundingPrecision(t *testing.T) {
 	assert.Equal(t, 89.96, result.Fahrenheit) // Not 89.96000000000001
 	assert.Equal(t, 305.35, result.Kelvin)    // Not 305.34999999999997
 }
+
+func TestConvertForecastResponse_DailyStatistics(t *testing.T) {
+	// Arrange
+	forecast := model.GetForecastResponseMock("Sao Paulo", 2)
+
+	// Act
+	result := ConvertForecastResponse(*forecast)
+
+	// Assert
+	assert.Equal(t, "Sao Paulo", result.City)
+	assert.Len(t, result.Days, 2)
+	assert.Equal(t, "2026-01-10", result.Days[0].Date)
+	assert.Equal(t, 19.5, result.Days[0].Min.Celsius)
+	assert.Equal(t, 67.1, result.Days[0].Min.Fahrenheit)
+	assert.Equal(t, 292.65, result.Days[0].Min.Kelvin)
+	assert.Equal(t, 32.2, result.Days[0].Max.Celsius)
+	assert.Equal(t, 89.96, result.Days[0].Max.Fahrenheit)
+	assert.Equal(t, 305.35, result.Days[0].Max.Kelvin)
+	assert.Equal(t, 25.0, result.Days[0].Avg.Celsius)
+	assert.Equal(t, 77.0, result.Days[0].Avg.Fahrenheit)
+	assert.Equal(t, 298.15, result.Days[0].Avg.Kelvin)
+	assert.Equal(t, 87, result.Days[0].ChanceOfRain)
+}
+
+func TestConvertForecastResponse_HourlyBreakdown(t *testing.T) {
+	// Arrange
+	forecast := model.GetForecastResponseMock("Sao Paulo", 1)
+
+	// Act
+	result := ConvertForecastResponse(*forecast)
+
+	// Assert
+	assert.Len(t, result.Days[0].Hours, 24)
+	assert.Equal(t, "2026-01-10 00:00", result.Days[0].Hours[0].Time)
+	assert.Equal(t, 20.0, result.Days[0].Hours[0].Temperature.Celsius)
+	assert.Equal(t, 68.0, result.Days[0].Hours[0].Temperature.Fahrenheit)
+	assert.Equal(t, 293.15, result.Days[0].Hours[0].Temperature.Kelvin)
+	assert.Equal(t, 40, result.Days[0].Hours[0].ChanceOfRain)
+}
+
+func TestConvertForecastResponse_EmptyForecast(t *testing.T) {
+	// Arrange
+	forecast := model.ForecastResponse{}
+
+	// Act
+	result := ConvertForecastResponse(forecast)
+
+	// Assert
+	assert.NotNil(t, result.Days)
+	assert.Empty(t, result.Days)
+}
+
+func TestConvertHistoryDays_Summary(t *testing.T) {
+	// Arrange
+	history := model.GetForecastResponseMock("Sao Paulo", 2).Forecast.Forecastday
+	history[1].Day.MintempC = 15
+	history[1].Day.MaxtempC = 35
+	history[1].Day.AvgtempC = 27
+
+	// Act
+	result := ConvertHistoryDays("Sao Paulo", "2026-01-10", "2026-01-11", history)
+
+	// Assert
+	assert.Equal(t, "Sao Paulo", result.City)
+	assert.Len(t, result.Days, 2)
+	assert.Equal(t, "2026-01-11", result.Days[1].Date)
+	assert.Equal(t, 15.0, result.Summary.Min.Celsius)
+	assert.Equal(t, 35.0, result.Summary.Max.Celsius)
+	assert.Equal(t, 26.0, result.Summary.Avg.Celsius)
+	assert.Equal(t, 299.15, result.Summary.Avg.Kelvin)
+}
+
+func TestConvertHistoryDays_EmptyHistory(t *testing.T) {
+	// Act
+	result := ConvertHistoryDays("Sao Paulo", "2026-01-10", "2026-01-10", nil)
+
+	// Assert
+	assert.Empty(t, result.Days)
+	assert.Equal(t, model.TemperatureStats{}, result.Summary)
+}
@@ -0,0 +1,134 @@
+package docs
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type schema struct {
+	Properties map[string]any `json:"properties"`
+	Required   []string       `json:"required"`
+}
+
+func decodeSpec(t *testing.T) (doc map[string]any, schemas map[string]schema) {
+	t.Helper()
+	require.NoError(t, json.Unmarshal(SpecJSON(), &doc))
+
+	var components struct {
+		Components struct {
+			Schemas map[string]schema `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(SpecJSON(), &components))
+	return doc, components.Components.Schemas
+}
+
+// jsonFields returns the JSON names of the fields of t, flattening embedded structs,
+// and whether each is omitted when empty
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := map[string]bool{}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Anonymous {
+			for name, omitempty := range jsonFields(field.Type) {
+				fields[name] = omitempty
+			}
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		fields[name] = strings.Contains(options, "omitempty")
+	}
+	return fields
+}
+
+func TestSpec_SchemasMatchModels(t *testing.T) {
+	models := map[string]any{
+		"TemperatureResponse":         model.TemperatureResponse{},
+		"TemperatureForecastResponse": model.TemperatureForecastResponse{},
+		"DailyForecast":               model.DailyForecast{},
+		"HourlyForecast":              model.HourlyForecast{},
+		"TemperatureHistoryResponse":  model.TemperatureHistoryResponse{},
+		"DailyTemperature":            model.DailyTemperature{},
+		"TemperatureStats":            model.TemperatureStats{},
+		"BatchTemperatureRequest":     model.BatchTemperatureRequest{},
+		"BatchTemperatureResponse":    model.BatchTemperatureResponse{},
+		"BatchTemperatureItem":        model.BatchTemperatureItem{},
+		"StatusResponse":              model.StatusResponse{},
+		"DependencyStatus":            model.DependencyStatus{},
+		"ErrorResponse":               model.ErrorResponse{},
+		"Problem":                     model.Problem{},
+		"ErrorDetail":                 model.ErrorDetail{},
+	}
+
+	_, schemas := decodeSpec(t)
+	require.Len(t, schemas, len(models), "cada schema deve corresponder a um modelo")
+
+	for name, value := range models {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			require.Contains(t, schemas, name)
+			fields := jsonFields(reflect.TypeOf(value))
+
+			// act
+			var properties []string
+			for property := range schemas[name].Properties {
+				properties = append(properties, property)
+			}
+			var expected []string
+			for field := range fields {
+				expected = append(expected, field)
+			}
+
+			// assert
+			assert.ElementsMatch(t, expected, properties)
+			for _, required := range schemas[name].Required {
+				assert.False(t, fields[required], "campo obrigatório %s não pode ter omitempty", required)
+			}
+		})
+	}
+}
+
+func TestSpec_RefsResolve(t *testing.T) {
+	// arrange
+	doc, _ := decodeSpec(t)
+	components := doc["components"].(map[string]any)
+
+	var refs []string
+	var walk func(node any)
+	walk = func(node any) {
+		switch v := node.(type) {
+		case map[string]any:
+			for key, child := range v {
+				if ref, ok := child.(string); ok && key == "$ref" {
+					refs = append(refs, ref)
+				}
+				walk(child)
+			}
+		case []any:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+
+	// act
+	walk(doc)
+
+	// assert
+	require.NotEmpty(t, refs)
+	for _, ref := range refs {
+		kind, name, ok := strings.Cut(strings.TrimPrefix(ref, "#/components/"), "/")
+		require.True(t, ok, "referência %s", ref)
+		section, _ := components[kind].(map[string]any)
+		assert.Contains(t, section, name, "referência %s não resolvida", ref)
+	}
+}
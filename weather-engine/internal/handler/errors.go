@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/alexduzi/laboteldistributedtracing/platform/problem"
+	cErrors "github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client/error"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/model"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// Problem codes, appended to problem.TypeBase to form the problem types
+const (
+	CodeInvalidRequest  = "invalid_request"
+	CodeInvalidZipcode  = "invalid_zipcode"
+	CodeZipcodeNotFound = "zipcode_not_found"
+	CodeWeatherNotFound = "weather_not_found"
+	CodeQuotaExceeded   = "quota_exceeded"
+	CodeInternalError   = "internal_error"
+)
+
+const (
+	MsgInvalidZipcode  = "invalid zipcode"
+	MsgZipcodeNotFound = "can not find zipcode"
+	MsgWeatherNotFound = "can not find weather for location"
+	MsgInternalError   = "internal server error"
+	MsgQuotaExceeded   = "weather provider quota exceeded, try again later"
+)
+
+// validationError is a request the handlers reject before any lookup; its message is the problem detail
+type validationError struct {
+	message string
+}
+
+func (e *validationError) Error() string {
+	return e.message
+}
+
+func invalidRequest(format string, args ...any) error {
+	return &validationError{message: fmt.Sprintf(format, args...)}
+}
+
+// writeClientError maps the service, client and validation errors into HTTP responses
+func writeClientError(c *gin.Context, err error) {
+	p := problemFor(err)
+	if p.Status >= http.StatusInternalServerError {
+		slog.ErrorContext(c.Request.Context(), "request failed", "error", err)
+	}
+	var quotaErr *cErrors.QuotaError
+	if errors.As(err, &quotaErr) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(quotaErr.Delay().Seconds()))))
+	}
+	problem.Write(c, p)
+}
+
+// problemFor translates service, client and validation errors into the problem answered for them
+func problemFor(err error) model.Problem {
+	var validation *validationError
+
+	switch {
+	case errors.As(err, &validation):
+		return problem.New(http.StatusBadRequest, CodeInvalidRequest, validation.message)
+	case errors.Is(err, service.ErrInvalidZipcode), errors.Is(err, cErrors.CepClientBadRequest):
+		return problem.New(http.StatusUnprocessableEntity, CodeInvalidZipcode, MsgInvalidZipcode)
+	case errors.Is(err, service.ErrZipcodeNotFound), errors.Is(err, cErrors.CepClientNotFound):
+		return problem.New(http.StatusNotFound, CodeZipcodeNotFound, MsgZipcodeNotFound)
+	case errors.Is(err, cErrors.WeatherClientBadRequest), errors.Is(err, cErrors.WeatherClientNotFound):
+		return problem.New(http.StatusNotFound, CodeWeatherNotFound, MsgWeatherNotFound)
+	case errors.Is(err, cErrors.WeatherClientQuotaExceeded):
+		return problem.New(http.StatusServiceUnavailable, CodeQuotaExceeded, MsgQuotaExceeded)
+	default:
+		return problem.New(http.StatusInternalServerError, CodeInternalError, MsgInternalError)
+	}
+}
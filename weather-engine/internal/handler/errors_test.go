@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/laboteldistributedtracing/platform/problem"
+	cErrors "github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client/error"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/model"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// assertProblem checks that rec holds the problem of type code with detail, answered with its status
+func assertProblem(t *testing.T, rec *httptest.ResponseRecorder, code, detail string) model.Problem {
+	t.Helper()
+
+	assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+
+	var p model.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	assert.Equal(t, problem.TypeBase+code, p.Type)
+	assert.Equal(t, http.StatusText(rec.Code), p.Title)
+	assert.Equal(t, rec.Code, p.Status, "o status do corpo deve ser o da resposta")
+	assert.Equal(t, detail, p.Detail)
+	assert.Equal(t, code, p.Code)
+	return p
+}
+
+func TestProblemFor(t *testing.T) {
+	testCases := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+		detail string
+	}{
+		{"Requisição inválida", invalidRequest("batch must not exceed %d ceps", 3), http.StatusBadRequest, CodeInvalidRequest, "batch must not exceed 3 ceps"},
+		{"CEP inválido", fmt.Errorf("%w: bad format", service.ErrInvalidZipcode), http.StatusUnprocessableEntity, CodeInvalidZipcode, MsgInvalidZipcode},
+		{"CEP rejeitado pelo ViaCEP", cErrors.CepClientBadRequest, http.StatusUnprocessableEntity, CodeInvalidZipcode, MsgInvalidZipcode},
+		{"CEP não encontrado", cErrors.CepClientNotFound, http.StatusNotFound, CodeZipcodeNotFound, MsgZipcodeNotFound},
+		{"Clima não encontrado", cErrors.WeatherClientNotFound, http.StatusNotFound, CodeWeatherNotFound, MsgWeatherNotFound},
+		{"Cota esgotada", &cErrors.QuotaError{Window: cErrors.QuotaWindowMonth}, http.StatusServiceUnavailable, CodeQuotaExceeded, MsgQuotaExceeded},
+		{"Erro inesperado", assert.AnError, http.StatusInternalServerError, CodeInternalError, MsgInternalError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			p := problemFor(tc.err)
+
+			// assert
+			assert.Equal(t, model.Problem{
+				Type:   problem.TypeBase + tc.code,
+				Title:  http.StatusText(tc.status),
+				Status: tc.status,
+				Detail: tc.detail,
+				Code:   tc.code,
+			}, p)
+		})
+	}
+}
+
+func TestWriteClientError_InstanceAndTraceID(t *testing.T) {
+	// arrange
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	router, svc := setupTemperatureRouter()
+	svc.On("GetTemperature", mock.Anything, "01310100").Return(nil, service.ErrZipcodeNotFound)
+
+	// act
+	rec := doRequest(router, http.MethodGet, "/api/v1/temperature/01310100")
+
+	// assert
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	p := assertProblem(t, rec, CodeZipcodeNotFound, MsgZipcodeNotFound)
+	assert.Equal(t, "/api/v1/temperature/01310100", p.Instance)
+
+	spans := recorder.Ended()
+	require.NotEmpty(t, spans)
+	assert.Equal(t, spans[0].SpanContext().TraceID().String(), p.TraceID, "o trace_id deve ser o do span do servidor")
+}
+
+func TestWriteClientError_Legacy(t *testing.T) {
+	// arrange
+	gin.SetMode(gin.TestMode)
+	svc := service.NewTemperatureServiceStub()
+	svc.On("GetTemperature", mock.Anything, "01310100").Return(nil, service.ErrInvalidZipcode)
+
+	router := NewRouter("weather-engine-test", nil, nil, true, false, Handlers{
+		Temperature: NewTemperatureHandler(&config.Config{BatchMaxSize: 3}, svc),
+	})
+
+	// act
+	rec := doRequest(router, http.MethodGet, "/api/v1/temperature/01310100")
+
+	// assert
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"message":"invalid zipcode"}`, rec.Body.String())
+}
+
+func TestWriteClientError_QuotaRetryAfter(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"Janela com reset conhecido", &cErrors.QuotaError{Window: cErrors.QuotaWindowMonth, RetryAfter: 90 * time.Second}, "90"},
+		{"Janela do provedor sem reset conhecido", &cErrors.QuotaError{Window: cErrors.QuotaWindowProvider}, "30"},
+		{"429 do WeatherAPI", cErrors.NewWeatherClientHTTPError(http.StatusTooManyRequests), "30"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			router, svc := setupTemperatureRouter()
+			svc.On("GetTemperature", mock.Anything, "01310100").Return(nil, tc.err)
+
+			// act
+			rec := doRequest(router, http.MethodGet, "/api/v1/temperature/01310100")
+
+			// assert
+			assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+			assert.Equal(t, tc.expected, rec.Header().Get("Retry-After"), "erros de cota sempre dizem quando tentar de novo")
+			assertProblem(t, rec, CodeQuotaExceeded, MsgQuotaExceeded)
+		})
+	}
+}
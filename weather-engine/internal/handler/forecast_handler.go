@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/alexduzi/laboteldistributedtracing/cep"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/conversor"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	DefaultForecastDays = 3
+	MaxForecastDays     = 14
+
+	MsgInvalidForecastDays = "days must be a number between 1 and 14"
+)
+
+type ForecastHandler struct {
+	cepClient     client.CepClientInterface
+	weatherClient client.WeatherClientInterface
+}
+
+func NewForecastHandler(cepClient client.CepClientInterface, weatherClient client.WeatherClientInterface) *ForecastHandler {
+	return &ForecastHandler{
+		cepClient:     cepClient,
+		weatherClient: weatherClient,
+	}
+}
+
+// GetForecast returns daily min/max/avg temperatures, chance of rain and hourly breakdowns
+func (h *ForecastHandler) GetForecast(c *gin.Context) {
+	code, err := cep.Parse(c.Param("cep"))
+	if err != nil {
+		writeClientError(c, fmt.Errorf("%w: %w", service.ErrInvalidZipcode, err))
+		return
+	}
+
+	days := DefaultForecastDays
+	if raw := c.Query("days"); raw != "" {
+		d, err := strconv.Atoi(raw)
+		if err != nil || d < 1 || d > MaxForecastDays {
+			writeClientError(c, invalidRequest(MsgInvalidForecastDays))
+			return
+		}
+		days = d
+	}
+
+	ctx := c.Request.Context()
+
+	location, err := h.cepClient.GetCep(ctx, code.String())
+	if err != nil {
+		writeClientError(c, err)
+		return
+	}
+	if location.Erro != nil {
+		writeClientError(c, service.ErrZipcodeNotFound)
+		return
+	}
+
+	forecast, err := h.weatherClient.GetForecast(ctx, location.Localidade, days)
+	if err != nil {
+		writeClientError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, conversor.ConvertForecastResponse(*forecast))
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client"
+	cErrors "github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client/error"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupForecastRouter() (*gin.Engine, *client.CepClientStub, *client.WeatherClientStub) {
+	gin.SetMode(gin.TestMode)
+
+	cepClient := client.NewCepClientStub(nil)
+	weatherClient := client.NewWeatherClientStub(nil)
+
+	router := NewRouter("weather-engine-test", nil, nil, false, false, Handlers{
+		Forecast: NewForecastHandler(cepClient, weatherClient),
+	})
+	return router, cepClient, weatherClient
+}
+
+func doRequest(router http.Handler, method, target string) *httptest.ResponseRecorder {
+	req := httptest.NewRequestWithContext(context.Background(), method, target, nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGetForecast_Success(t *testing.T) {
+	// arrange
+	router, cepClient, weatherClient := setupForecastRouter()
+
+	cepClient.On("GetCep", mock.Anything, "01310100").Return(model.GetViacepResponseMock("01310-100"), nil)
+	weatherClient.On("GetForecast", mock.Anything, "São Paulo", 2).Return(model.GetForecastResponseMock("São Paulo", 2), nil)
+
+	// act
+	rec := doRequest(router, http.MethodGet, "/api/v1/forecast/01310-100?days=2")
+
+	// assert
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var body model.TemperatureForecastResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Len(t, body.Days, 2)
+	assert.Equal(t, 32.2, body.Days[0].Max.Celsius)
+	assert.Equal(t, 19.5, body.Days[0].Min.Celsius)
+	assert.Equal(t, 87, body.Days[0].ChanceOfRain)
+	assert.Len(t, body.Days[0].Hours, 24)
+
+	cepClient.AssertExpectations(t)
+	weatherClient.AssertExpectations(t)
+}
+
+func TestGetForecast_DefaultDays(t *testing.T) {
+	// arrange
+	router, cepClient, weatherClient := setupForecastRouter()
+
+	cepClient.On("GetCep", mock.Anything, "01310100").Return(model.GetViacepResponseMock("01310-100"), nil)
+	weatherClient.On("GetForecast", mock.Anything, "São Paulo", DefaultForecastDays).
+		Return(model.GetForecastResponseMock("São Paulo", DefaultForecastDays), nil)
+
+	// act
+	rec := doRequest(router, http.MethodGet, "/api/v1/forecast/01310100")
+
+	// assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	weatherClient.AssertExpectations(t)
+}
+
+func TestGetForecast_InvalidCep(t *testing.T) {
+	// arrange
+	router, cepClient, weatherClient := setupForecastRouter()
+
+	// act
+	rec := doRequest(router, http.MethodGet, "/api/v1/forecast/0131A100")
+
+	// assert
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assertProblem(t, rec, CodeInvalidZipcode, MsgInvalidZipcode)
+	cepClient.AssertNotCalled(t, "GetCep", mock.Anything, mock.Anything)
+	weatherClient.AssertNotCalled(t, "GetForecast", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetForecast_InvalidDays(t *testing.T) {
+	testCases := []struct {
+		name string
+		days string
+	}{
+		{"Não numérico", "abc"},
+		{"Zero", "0"},
+		{"Acima do limite", "15"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			router, _, _ := setupForecastRouter()
+
+			// act
+			rec := doRequest(router, http.MethodGet, "/api/v1/forecast/01310100?days="+tc.days)
+
+			// assert
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assertProblem(t, rec, CodeInvalidRequest, MsgInvalidForecastDays)
+		})
+	}
+}
+
+func TestGetForecast_CepNotFound(t *testing.T) {
+	// arrange
+	router, cepClient, _ := setupForecastRouter()
+
+	erro := "true"
+	cepClient.On("GetCep", mock.Anything, "99999999").Return(&model.ViacepResponse{Erro: &erro}, nil)
+
+	// act
+	rec := doRequest(router, http.MethodGet, "/api/v1/forecast/99999999")
+
+	// assert
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assertProblem(t, rec, CodeZipcodeNotFound, MsgZipcodeNotFound)
+}
+
+func TestGetForecast_WeatherClientError(t *testing.T) {
+	// arrange
+	router, cepClient, weatherClient := setupForecastRouter()
+
+	cepClient.On("GetCep", mock.Anything, "01310100").Return(model.GetViacepResponseMock("01310-100"), nil)
+	weatherClient.On("GetForecast", mock.Anything, "São Paulo", DefaultForecastDays).
+		Return(nil, cErrors.WeatherClientInternalError)
+
+	// act
+	rec := doRequest(router, http.MethodGet, "/api/v1/forecast/01310100")
+
+	// assert
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assertProblem(t, rec, CodeInternalError, MsgInternalError)
+}
+
+func TestGetForecast_QuotaExceeded(t *testing.T) {
+	// arrange
+	router, cepClient, weatherClient := setupForecastRouter()
+
+	cepClient.On("GetCep", mock.Anything, "01310100").Return(model.GetViacepResponseMock("01310-100"), nil)
+	weatherClient.On("GetForecast", mock.Anything, "São Paulo", DefaultForecastDays).
+		Return(nil, &cErrors.QuotaError{Window: cErrors.QuotaWindowMinute, RetryAfter: 1500 * time.Millisecond})
+
+	// act
+	rec := doRequest(router, http.MethodGet, "/api/v1/forecast/01310100")
+
+	// assert
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+	assertProblem(t, rec, CodeQuotaExceeded, MsgQuotaExceeded)
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/laboteldistributedtracing/platform/health"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupHealthRouter(checks ...health.Check) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	checker := health.NewChecker(time.Minute, time.Second, checks...)
+	return NewRouter("weather-engine-test", nil, nil, false, false, Handlers{
+		Health: health.NewHandler("weather-engine-test", checker),
+	})
+}
+
+func TestHealthLive(t *testing.T) {
+	// arrange
+	router := setupHealthRouter(health.Check{Name: "viacep", Run: func(context.Context) error {
+		return errors.New("down")
+	}})
+
+	// act
+	rec := doRequest(router, http.MethodGet, "/health/live")
+
+	// assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var body model.StatusResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, health.StatusHealthy, body.Status)
+	assert.Equal(t, "weather-engine-test", body.Service)
+	assert.Empty(t, body.Checks)
+}
+
+func TestHealthReady(t *testing.T) {
+	up := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name           string
+		weatherapi     func(context.Context) error
+		expectedCode   int
+		expectedStatus string
+	}{
+		{"todas as dependências no ar", up, http.StatusOK, health.StatusHealthy},
+		{"uma dependência fora do ar", down, http.StatusServiceUnavailable, health.StatusUnhealthy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			router := setupHealthRouter(
+				health.Check{Name: "viacep", Run: up},
+				health.Check{Name: "weatherapi", Run: tt.weatherapi},
+			)
+
+			// act
+			rec := doRequest(router, http.MethodGet, "/health/ready")
+
+			// assert
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			var body model.StatusResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tt.expectedStatus, body.Status)
+			require.Len(t, body.Checks, 2)
+			assert.Equal(t, "viacep", body.Checks[0].Name)
+			assert.Equal(t, health.StatusUp, body.Checks[0].Status)
+			assert.Equal(t, "weatherapi", body.Checks[1].Name)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/alexduzi/laboteldistributedtracing/cep"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/conversor"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/model"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	tracerName = "github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/handler"
+
+	MsgInvalidHistoryDates = "from and to must be dates in YYYY-MM-DD format"
+	MsgInvalidHistoryRange = "from must not be after to"
+)
+
+type HistoryHandler struct {
+	config        *config.Config
+	cepClient     client.CepClientInterface
+	weatherClient client.WeatherClientInterface
+}
+
+func NewHistoryHandler(cfg *config.Config, cepClient client.CepClientInterface, weatherClient client.WeatherClientInterface) *HistoryHandler {
+	return &HistoryHandler{
+		config:        cfg,
+		cepClient:     cepClient,
+		weatherClient: weatherClient,
+	}
+}
+
+// GetHistory fetches each day from the history API with bounded concurrency and returns
+// per-day and aggregated statistics
+func (h *HistoryHandler) GetHistory(c *gin.Context) {
+	code, err := cep.Parse(c.Param("cep"))
+	if err != nil {
+		writeClientError(c, fmt.Errorf("%w: %w", service.ErrInvalidZipcode, err))
+		return
+	}
+
+	from, errFrom := time.Parse(time.DateOnly, c.Query("from"))
+	to, errTo := time.Parse(time.DateOnly, c.Query("to"))
+	if errFrom != nil || errTo != nil {
+		writeClientError(c, invalidRequest(MsgInvalidHistoryDates))
+		return
+	}
+	if from.After(to) {
+		writeClientError(c, invalidRequest(MsgInvalidHistoryRange))
+		return
+	}
+
+	if dayCount(from, to) > h.config.HistoryMaxDays {
+		writeClientError(c, invalidRequest("date range must not exceed %d days", h.config.HistoryMaxDays))
+		return
+	}
+	dates := daysBetween(from, to)
+
+	ctx := c.Request.Context()
+
+	location, err := h.cepClient.GetCep(ctx, code.String())
+	if err != nil {
+		writeClientError(c, err)
+		return
+	}
+	if location.Erro != nil {
+		writeClientError(c, service.ErrZipcodeNotFound)
+		return
+	}
+
+	history, err := h.fetchHistory(ctx, location.Localidade, dates)
+	if err != nil {
+		writeClientError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, conversor.ConvertHistoryDays(
+		location.Localidade,
+		from.Format(time.DateOnly),
+		to.Format(time.DateOnly),
+		history,
+	))
+}
+
+// fetchHistory requests every day in parallel, bounded by HistoryConcurrency, keeping the results in date order
+func (h *HistoryHandler) fetchHistory(ctx context.Context, city string, dates []time.Time) ([]model.ForecastDay, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "fetch history")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("weather.city", city),
+		attribute.Int("weather.history.days", len(dates)),
+		attribute.Int("weather.history.concurrency", h.config.HistoryConcurrency),
+	)
+
+	results := make([][]model.ForecastDay, len(dates))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(h.config.HistoryConcurrency, 1))
+
+	for i, date := range dates {
+		g.Go(func() error {
+			res, err := h.weatherClient.GetHistory(gctx, city, date)
+			if err != nil {
+				return err
+			}
+			results[i] = res.Forecast.Forecastday
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	history := make([]model.ForecastDay, 0, len(dates))
+	for _, days := range results {
+		history = append(history, days...)
+	}
+	return history, nil
+}
+
+// dayCount is the number of days from from to to, both included. The dates are UTC
+// midnights, so every day is exactly 24 hours.
+func dayCount(from, to time.Time) int {
+	return int(to.Sub(from)/(24*time.Hour)) + 1
+}
+
+func daysBetween(from, to time.Time) []time.Time {
+	var dates []time.Time
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d)
+	}
+	return dates
+}
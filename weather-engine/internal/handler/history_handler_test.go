@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client"
+	cErrors "github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client/error"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func historyConfig() *config.Config {
+	return &config.Config{
+		HistoryMaxDays:     31,
+		HistoryConcurrency: 2,
+	}
+}
+
+func setupHistoryRouter(cfg *config.Config, cepClient client.CepClientInterface, weatherClient client.WeatherClientInterface) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	return NewRouter("weather-engine-test", nil, nil, false, false, Handlers{
+		History: NewHistoryHandler(cfg, cepClient, weatherClient),
+	})
+}
+
+func historyDayMock(date time.Time, minC, maxC, avgC float64) *model.ForecastResponse {
+	res := model.GetForecastResponseMock("São Paulo", 1)
+	res.Forecast.Forecastday[0].Date = date.Format(time.DateOnly)
+	res.Forecast.Forecastday[0].Day.MintempC = minC
+	res.Forecast.Forecastday[0].Day.MaxtempC = maxC
+	res.Forecast.Forecastday[0].Day.AvgtempC = avgC
+	return res
+}
+
+// concurrencyWeatherClient registra o número máximo de chamadas simultâneas
+type concurrencyWeatherClient struct {
+	*client.WeatherClientStub
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+	mu          sync.Mutex
+	dates       []string
+}
+
+func (w *concurrencyWeatherClient) GetHistory(ctx context.Context, city string, date time.Time) (*model.ForecastResponse, error) {
+	current := w.inFlight.Add(1)
+	defer w.inFlight.Add(-1)
+	for {
+		observed := w.maxInFlight.Load()
+		if current <= observed || w.maxInFlight.CompareAndSwap(observed, current) {
+			break
+		}
+	}
+
+	w.mu.Lock()
+	w.dates = append(w.dates, date.Format(time.DateOnly))
+	w.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+	return historyDayMock(date, 20, 30, 25), nil
+}
+
+func TestGetHistory_AggregatesDaysInOrder(t *testing.T) {
+	// arrange
+	cepClient := client.NewCepClientStub(nil)
+	weatherClient := client.NewWeatherClientStub(nil)
+	router := setupHistoryRouter(historyConfig(), cepClient, weatherClient)
+
+	day1 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	day3 := day1.AddDate(0, 0, 2)
+
+	cepClient.On("GetCep", mock.Anything, "01310100").Return(model.GetViacepResponseMock("01310-100"), nil)
+	weatherClient.On("GetHistory", mock.Anything, "São Paulo", day1).Return(historyDayMock(day1, 18, 28, 22), nil)
+	weatherClient.On("GetHistory", mock.Anything, "São Paulo", day2).Return(historyDayMock(day2, 16, 31, 24), nil)
+	weatherClient.On("GetHistory", mock.Anything, "São Paulo", day3).Return(historyDayMock(day3, 20, 26, 23), nil)
+
+	// act
+	rec := doRequest(router, http.MethodGet, "/api/v1/history/01310-100?from=2026-01-01&to=2026-01-03")
+
+	// assert
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var body model.TemperatureHistoryResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "São Paulo", body.City)
+	assert.Equal(t, "2026-01-01", body.From)
+	assert.Equal(t, "2026-01-03", body.To)
+	require.Len(t, body.Days, 3)
+	assert.Equal(t, "2026-01-01", body.Days[0].Date)
+	assert.Equal(t, "2026-01-02", body.Days[1].Date)
+	assert.Equal(t, "2026-01-03", body.Days[2].Date)
+	assert.Equal(t, 16.0, body.Summary.Min.Celsius)
+	assert.Equal(t, 31.0, body.Summary.Max.Celsius)
+	assert.Equal(t, 23.0, body.Summary.Avg.Celsius)
+	assert.Equal(t, 73.4, body.Summary.Avg.Fahrenheit)
+	assert.Equal(t, 296.15, body.Summary.Avg.Kelvin)
+
+	weatherClient.AssertExpectations(t)
+}
+
+func TestGetHistory_BoundedConcurrency(t *testing.T) {
+	// arrange
+	cepClient := client.NewCepClientStub(nil)
+	weatherClient := &concurrencyWeatherClient{WeatherClientStub: client.NewWeatherClientStub(nil)}
+	router := setupHistoryRouter(historyConfig(), cepClient, weatherClient)
+
+	cepClient.On("GetCep", mock.Anything, "01310100").Return(model.GetViacepResponseMock("01310-100"), nil)
+
+	// act
+	rec := doRequest(router, http.MethodGet, "/api/v1/history/01310100?from=2026-01-01&to=2026-01-10")
+
+	// assert
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, weatherClient.dates, 10)
+	assert.LessOrEqual(t, weatherClient.maxInFlight.Load(), int32(2))
+}
+
+func TestGetHistory_InvalidParameters(t *testing.T) {
+	testCases := []struct {
+		name     string
+		target   string
+		expected int
+		code     string
+		detail   string
+	}{
+		{"CEP inválido", "/api/v1/history/123?from=2026-01-01&to=2026-01-02", http.StatusUnprocessableEntity, CodeInvalidZipcode, MsgInvalidZipcode},
+		{"Sem datas", "/api/v1/history/01310100", http.StatusBadRequest, CodeInvalidRequest, MsgInvalidHistoryDates},
+		{"Data mal formatada", "/api/v1/history/01310100?from=01/01/2026&to=2026-01-02", http.StatusBadRequest, CodeInvalidRequest, MsgInvalidHistoryDates},
+		{"Intervalo invertido", "/api/v1/history/01310100?from=2026-01-05&to=2026-01-01", http.StatusBadRequest, CodeInvalidRequest, MsgInvalidHistoryRange},
+		{"Intervalo muito longo", "/api/v1/history/01310100?from=2026-01-01&to=2026-03-01", http.StatusBadRequest, CodeInvalidRequest, "date range must not exceed 31 days"},
+		{"Intervalo de milênios", "/api/v1/history/01310100?from=0001-01-01&to=9999-12-31", http.StatusBadRequest, CodeInvalidRequest, "date range must not exceed 31 days"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			cepClient := client.NewCepClientStub(nil)
+			router := setupHistoryRouter(historyConfig(), cepClient, client.NewWeatherClientStub(nil))
+
+			// act
+			rec := doRequest(router, http.MethodGet, tc.target)
+
+			// assert
+			assert.Equal(t, tc.expected, rec.Code)
+			assertProblem(t, rec, tc.code, tc.detail)
+			cepClient.AssertNotCalled(t, "GetCep", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetHistory_UpstreamError(t *testing.T) {
+	// arrange
+	cepClient := client.NewCepClientStub(nil)
+	weatherClient := client.NewWeatherClientStub(nil)
+	router := setupHistoryRouter(historyConfig(), cepClient, weatherClient)
+
+	cepClient.On("GetCep", mock.Anything, "01310100").Return(model.GetViacepResponseMock("01310-100"), nil)
+	weatherClient.On("GetHistory", mock.Anything, "São Paulo", mock.Anything).Return(nil, cErrors.WeatherClientInternalError)
+
+	// act
+	rec := doRequest(router, http.MethodGet, "/api/v1/history/01310100?from=2026-01-01&to=2026-01-02")
+
+	// assert
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestGetHistory_EachDayIsAChildSpan(t *testing.T) {
+	// arrange
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	viaCep := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(model.GetViacepResponseMock("01310-100"))
+	}))
+	defer viaCep.Close()
+
+	weatherApi := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		date, _ := time.Parse(time.DateOnly, r.URL.Query().Get("dt"))
+		_ = json.NewEncoder(w).Encode(historyDayMock(date, 20, 30, 25))
+	}))
+	defer weatherApi.Close()
+
+	cfg := historyConfig()
+	cfg.ViaCEPBaseURL = viaCep.URL + "/{cep}"
+	cfg.WeatherHistoryURL = weatherApi.URL
+
+	router := setupHistoryRouter(cfg, client.NewCepClient(cfg, nil), client.NewWeatherClient(cfg, nil))
+
+	// act
+	rec := doRequest(router, http.MethodGet, "/api/v1/history/01310100?from=2026-01-01&to=2026-01-03")
+
+	// assert
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var parent sdktrace.ReadOnlySpan
+	var children []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		switch span.Name() {
+		case "fetch history":
+			parent = span
+		case "WeatherAPI GetHistory":
+			children = append(children, span)
+		}
+	}
+
+	require.NotNil(t, parent)
+	require.Len(t, children, 3)
+	for _, child := range children {
+		assert.Equal(t, parent.SpanContext().SpanID(), child.Parent().SpanID())
+		assert.Equal(t, parent.SpanContext().TraceID(), child.SpanContext().TraceID())
+	}
+}
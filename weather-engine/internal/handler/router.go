@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/alexduzi/laboteldistributedtracing/platform/health"
+	"github.com/alexduzi/laboteldistributedtracing/platform/logging"
+	"github.com/alexduzi/laboteldistributedtracing/platform/problem"
+	"github.com/alexduzi/laboteldistributedtracing/platform/tracing"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/docs"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/metrics"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/tenant"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// Handlers groups the HTTP handlers served by the weather-engine
+type Handlers struct {
+	Temperature *TemperatureHandler
+	Forecast    *ForecastHandler
+	History     *HistoryHandler
+	Health      *health.Handler
+	// Metrics serves the Prometheus exposition format; nil when metrics are pushed
+	Metrics http.Handler
+}
+
+// NewRouter registers the weather-engine routes behind the OpenTelemetry, RED metrics and request logging middlewares.
+// API routes are also rate limited per tenant by limiter, which may be nil.
+// Errors are rendered as problem details, or in the legacy shape when legacyErrors is set.
+// The X-Debug-Trace header forces sampling only when debugTraces is set.
+// The OpenAPI document and Swagger UI are served under docs.Path.
+func NewRouter(serviceName string, m *metrics.Metrics, limiter *tenant.Limiter, legacyErrors, debugTraces bool, handlers Handlers) *gin.Engine {
+	router := gin.New()
+	router.Use(gin.Recovery(), tracing.DebugSampling(debugTraces), otelgin.Middleware(serviceName), m.Middleware(), logging.Middleware(), problem.Middleware(legacyErrors))
+
+	if handlers.Metrics != nil {
+		router.GET("/metrics", gin.WrapH(handlers.Metrics))
+	}
+
+	if handlers.Health != nil {
+		router.GET("/health/live", handlers.Health.Live)
+		router.GET("/health/ready", handlers.Health.Ready)
+	}
+
+	docs.Register(router)
+
+	v1 := router.Group("/api/v1", tenant.Middleware(limiter))
+	v1.GET("/temperature/:cep", handlers.Temperature.GetTemperature)
+	v1.POST("/temperature\\:batch", handlers.Temperature.BatchGetTemperature)
+	v1.GET("/forecast/:cep", handlers.Forecast.GetForecast)
+	v1.GET("/history/:cep", handlers.History.GetHistory)
+
+	return router
+}
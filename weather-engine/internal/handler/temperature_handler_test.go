@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/model"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTemperatureRouter() (*gin.Engine, *service.TemperatureServiceStub) {
+	gin.SetMode(gin.TestMode)
+
+	svc := service.NewTemperatureServiceStub()
+	cfg := &config.Config{BatchMaxSize: 3, BatchConcurrency: 2}
+
+	router := NewRouter("weather-engine-test", nil, nil, false, false, Handlers{
+		Temperature: NewTemperatureHandler(cfg, svc),
+	})
+	return router, svc
+}
+
+func doJSONRequest(router http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequestWithContext(context.Background(), method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGetTemperature_Success(t *testing.T) {
+	// arrange
+	router, svc := setupTemperatureRouter()
+
+	svc.On("GetTemperature", mock.Anything, "01310-100").
+		Return(&model.TemperatureResponse{Celsius: 28.5, Fahrenheit: 83.3, Kelvin: 301.65}, nil)
+
+	// act
+	rec := doRequest(router, http.MethodGet, "/api/v1/temperature/01310-100")
+
+	// assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"temp_C":28.5,"temp_F":83.3,"temp_K":301.65}`, rec.Body.String())
+}
+
+func TestGetTemperature_Errors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected int
+		code     string
+		message  string
+	}{
+		{"CEP inválido", service.ErrInvalidZipcode, http.StatusUnprocessableEntity, CodeInvalidZipcode, MsgInvalidZipcode},
+		{"CEP não encontrado", service.ErrZipcodeNotFound, http.StatusNotFound, CodeZipcodeNotFound, MsgZipcodeNotFound},
+		{"Erro inesperado", assert.AnError, http.StatusInternalServerError, CodeInternalError, MsgInternalError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			router, svc := setupTemperatureRouter()
+			svc.On("GetTemperature", mock.Anything, "01310100").Return(nil, tc.err)
+
+			// act
+			rec := doRequest(router, http.MethodGet, "/api/v1/temperature/01310100")
+
+			// assert
+			assert.Equal(t, tc.expected, rec.Code)
+			assertProblem(t, rec, tc.code, tc.message)
+		})
+	}
+}
+
+func TestBatchGetTemperature_PerItemResults(t *testing.T) {
+	// arrange
+	router, svc := setupTemperatureRouter()
+
+	svc.On("GetTemperatures", mock.Anything, []string{"01310-100", "abc"}).Return([]service.TemperatureResult{
+		{Cep: "01310100", Temperature: &model.TemperatureResponse{Celsius: 28.5, Fahrenheit: 83.3, Kelvin: 301.65}},
+		{Cep: "abc", Err: service.ErrInvalidZipcode},
+	})
+
+	// act
+	rec := doJSONRequest(router, http.MethodPost, "/api/v1/temperature:batch", `{"ceps":["01310-100","abc"]}`)
+
+	// assert
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var body model.BatchTemperatureResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.Results, 2)
+	assert.Equal(t, "01310100", body.Results[0].Cep)
+	assert.Equal(t, http.StatusOK, body.Results[0].Status)
+	assert.Equal(t, 28.5, body.Results[0].Temperature.Celsius)
+	assert.Nil(t, body.Results[0].Error)
+	assert.Equal(t, "abc", body.Results[1].Cep)
+	assert.Equal(t, http.StatusUnprocessableEntity, body.Results[1].Status)
+	assert.Nil(t, body.Results[1].Temperature)
+	assert.Equal(t, MsgInvalidZipcode, body.Results[1].Error.Message)
+}
+
+func TestBatchGetTemperature_InvalidRequest(t *testing.T) {
+	testCases := []struct {
+		name   string
+		body   string
+		detail string
+	}{
+		{"Corpo vazio", ``, MsgInvalidBatchRequest},
+		{"JSON inválido", `{"ceps":`, MsgInvalidBatchRequest},
+		{"Lista vazia", `{"ceps":[]}`, MsgInvalidBatchRequest},
+		{"Acima do limite", `{"ceps":["01310100","20040002","30130010","40010000"]}`, "batch must not exceed 3 ceps"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			router, svc := setupTemperatureRouter()
+
+			// act
+			rec := doJSONRequest(router, http.MethodPost, "/api/v1/temperature:batch", tc.body)
+
+			// assert
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assertProblem(t, rec, CodeInvalidRequest, tc.detail)
+			svc.AssertNotCalled(t, "GetTemperatures", mock.Anything, mock.Anything)
+		})
+	}
+}
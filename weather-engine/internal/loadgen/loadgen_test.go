@@ -0,0 +1,144 @@
+package loadgen
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpstreams(t *testing.T) {
+	// arrange
+	server := httptest.NewServer(NewUpstreams(50 * time.Millisecond))
+	defer server.Close()
+
+	t.Run("ViaCEP resolve a cidade do cenário", func(t *testing.T) {
+		// act
+		resp, err := http.Get(server.URL + "/ws/" + Ceps[ScenarioSlow] + "/json/")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		// assert
+		var location model.ViacepResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&location))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "Slow City", location.Localidade)
+		assert.Nil(t, location.Erro)
+	})
+
+	t.Run("ViaCEP CEP desconhecido", func(t *testing.T) {
+		// act
+		resp, err := http.Get(server.URL + "/ws/99999999/json/")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		// assert
+		var location model.ViacepResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&location))
+		assert.NotNil(t, location.Erro)
+	})
+
+	t.Run("WeatherAPI rápido", func(t *testing.T) {
+		// act
+		start := time.Now()
+		resp, err := http.Get(server.URL + "/v1/current.json?q=Fast+City")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		// assert
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Less(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("WeatherAPI lento", func(t *testing.T) {
+		// act
+		start := time.Now()
+		resp, err := http.Get(server.URL + "/v1/current.json?q=Slow+City")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		// assert
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("WeatherAPI com falha", func(t *testing.T) {
+		// act
+		resp, err := http.Get(server.URL + "/v1/current.json?q=Failing+City")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		// assert
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	})
+}
+
+func TestScenarios(t *testing.T) {
+	// arrange
+	cfg := RunConfig{Requests: 1000, SlowRatio: 0.2, ErrorRatio: 0.1, Seed: 7}
+
+	// act
+	first := Scenarios(cfg)
+	second := Scenarios(cfg)
+
+	// assert
+	assert.Equal(t, first, second)
+
+	counts := map[Scenario]int{}
+	for _, scenario := range first {
+		counts[scenario]++
+	}
+	assert.InDelta(t, 700, counts[ScenarioFast], 50)
+	assert.InDelta(t, 200, counts[ScenarioSlow], 50)
+	assert.InDelta(t, 100, counts[ScenarioFailing], 50)
+}
+
+func TestRun(t *testing.T) {
+	// arrange
+	var inFlight, peak atomic.Int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			old := peak.Load()
+			if current <= old || peak.CompareAndSwap(old, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		if strings.HasSuffix(r.URL.Path, Ceps[ScenarioFailing]) {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	cfg := RunConfig{Target: target.URL, Requests: 60, Concurrency: 4, SlowRatio: 0.3, ErrorRatio: 0.2, Seed: 1}
+
+	expected := map[Scenario]int{}
+	for _, scenario := range Scenarios(cfg) {
+		expected[scenario]++
+	}
+
+	// act
+	summary := Run(context.Background(), target.Client(), cfg)
+
+	// assert
+	for scenario, count := range expected {
+		assert.Equal(t, count, summary[scenario].Requests, scenario)
+		assert.Zero(t, summary[scenario].Failures, scenario)
+	}
+	assert.Equal(t, expected[ScenarioFailing], summary[ScenarioFailing].Statuses[http.StatusInternalServerError])
+	assert.Equal(t, expected[ScenarioFast], summary[ScenarioFast].Statuses[http.StatusOK])
+	assert.LessOrEqual(t, peak.Load(), int32(4))
+}
@@ -0,0 +1,99 @@
+package loadgen
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// RunConfig describes the traffic mix sent by Run
+type RunConfig struct {
+	Target      string
+	Requests    int
+	Concurrency int
+	SlowRatio   float64
+	ErrorRatio  float64
+	Seed        uint64
+}
+
+// Result counts the responses received for one scenario
+type Result struct {
+	Requests int
+	Statuses map[int]int
+	Failures int
+}
+
+// Summary holds the results of a run per scenario
+type Summary map[Scenario]*Result
+
+// Scenarios returns the scenario of every request of the run, drawn from the
+// configured ratios with a seeded generator so runs are reproducible
+func Scenarios(cfg RunConfig) []Scenario {
+	rng := rand.New(rand.NewPCG(cfg.Seed, cfg.Seed))
+
+	scenarios := make([]Scenario, cfg.Requests)
+	for i := range scenarios {
+		switch p := rng.Float64(); {
+		case p < cfg.ErrorRatio:
+			scenarios[i] = ScenarioFailing
+		case p < cfg.ErrorRatio+cfg.SlowRatio:
+			scenarios[i] = ScenarioSlow
+		default:
+			scenarios[i] = ScenarioFast
+		}
+	}
+	return scenarios
+}
+
+// Run sends the configured mix of fast, slow and failing temperature requests to the target
+func Run(ctx context.Context, client *http.Client, cfg RunConfig) Summary {
+	summary := Summary{}
+	for scenario := range Ceps {
+		summary[scenario] = &Result{Statuses: map[int]int{}}
+	}
+
+	var mu sync.Mutex
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(cfg.Concurrency, 1))
+
+	for _, scenario := range Scenarios(cfg) {
+		g.Go(func() error {
+			status, err := get(ctx, client, fmt.Sprintf("%s/api/v1/temperature/%s", strings.TrimSuffix(cfg.Target, "/"), Ceps[scenario]))
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			result := summary[scenario]
+			result.Requests++
+			if err != nil {
+				result.Failures++
+				return nil
+			}
+			result.Statuses[status]++
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+	return summary
+}
+
+func get(ctx context.Context, client *http.Client, url string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,77 @@
+package loadgen
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/model"
+)
+
+// Scenario is the behaviour a request triggers in the stand-in upstreams
+type Scenario string
+
+const (
+	ScenarioFast    Scenario = "fast"
+	ScenarioSlow    Scenario = "slow"
+	ScenarioFailing Scenario = "failing"
+)
+
+// Ceps maps every scenario to the CEP that triggers it
+var Ceps = map[Scenario]string{
+	ScenarioFast:    "01001000",
+	ScenarioSlow:    "02002000",
+	ScenarioFailing: "03003000",
+}
+
+var cities = map[string]string{
+	Ceps[ScenarioFast]:    "Fast City",
+	Ceps[ScenarioSlow]:    "Slow City",
+	Ceps[ScenarioFailing]: "Failing City",
+}
+
+// NewUpstreams returns a handler standing in for ViaCEP and WeatherAPI.
+// Point the weather-engine at it with VIA_CEP_BASE_URL=<addr>/ws/{cep}/json/
+// and WEATHER_BASE_URL=<addr>/v1/current.json. Slow CEPs make the weather
+// lookup take slowDelay and failing CEPs make it answer 500.
+func NewUpstreams(slowDelay time.Duration) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /ws/{cep}/json/", func(w http.ResponseWriter, r *http.Request) {
+		city, ok := cities[r.PathValue("cep")]
+		if !ok {
+			erro := "true"
+			writeJSON(w, &model.ViacepResponse{Erro: &erro})
+			return
+		}
+
+		location := model.GetViacepResponseMock(r.PathValue("cep"))
+		location.Localidade = city
+		writeJSON(w, location)
+	})
+
+	mux.HandleFunc("GET /v1/current.json", func(w http.ResponseWriter, r *http.Request) {
+		city := r.URL.Query().Get("q")
+
+		switch city {
+		case cities[Ceps[ScenarioSlow]]:
+			select {
+			case <-time.After(slowDelay):
+			case <-r.Context().Done():
+				return
+			}
+		case cities[Ceps[ScenarioFailing]]:
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, model.GetWeatherResponseMock(city))
+	})
+
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
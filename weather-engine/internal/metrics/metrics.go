@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/alexduzi/laboteldistributedtracing/platform/httpmetrics"
+	cErrors "github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client/error"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/tenant"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const MeterName = "github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/metrics"
+
+const (
+	UpstreamViaCEP     = "viacep"
+	UpstreamWeatherAPI = "weatherapi"
+)
+
+// temperatureBuckets cover the range of surface temperatures in Celsius
+var temperatureBuckets = []float64{-10, -5, 0, 5, 10, 15, 20, 25, 30, 35, 40, 45}
+
+// Metrics holds the instruments recorded by the weather-engine.
+// A nil *Metrics is valid and records nothing.
+type Metrics struct {
+	meter            metric.Meter
+	http             *httpmetrics.Instruments
+	upstreamDuration metric.Float64Histogram
+	upstreamErrors   metric.Int64Counter
+	temperature      metric.Float64Histogram
+	quotaRemaining   metric.Int64ObservableGauge
+}
+
+// New creates every instrument from the given meter
+func New(meter metric.Meter) (*Metrics, error) {
+	m := Metrics{meter: meter}
+	var err error
+
+	if m.http, err = httpmetrics.New(meter, tenant.Attribute); err != nil {
+		return nil, err
+	}
+	if m.upstreamDuration, err = meter.Float64Histogram("app.upstream.duration",
+		metric.WithDescription("Duration of calls to upstream APIs"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(httpmetrics.DurationBuckets...)); err != nil {
+		return nil, err
+	}
+	if m.upstreamErrors, err = meter.Int64Counter("app.upstream.errors",
+		metric.WithDescription("Number of failed calls to upstream APIs"),
+		metric.WithUnit("{call}")); err != nil {
+		return nil, err
+	}
+	if m.temperature, err = meter.Float64Histogram("app.temperature",
+		metric.WithDescription("Distribution of returned temperatures"),
+		metric.WithUnit("Cel"),
+		metric.WithExplicitBucketBoundaries(temperatureBuckets...)); err != nil {
+		return nil, err
+	}
+	if m.quotaRemaining, err = meter.Int64ObservableGauge("app.upstream.quota.remaining",
+		metric.WithDescription("Calls left in the outbound budget of an upstream API"),
+		metric.WithUnit("{call}")); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// Middleware records request count, error count and duration per route, method and status
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	if m == nil {
+		return (*httpmetrics.Instruments)(nil).Middleware()
+	}
+	return m.http.Middleware()
+}
+
+// RecordUpstream records the latency of an upstream call and, when it failed, its error category
+func (m *Metrics) RecordUpstream(ctx context.Context, upstream, operation string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+
+	m.upstreamDuration.Record(ctx, duration.Seconds(), metric.WithAttributes(
+		tenant.Attribute(ctx),
+		attribute.String("upstream", upstream),
+		attribute.String("operation", operation),
+		attribute.String("outcome", outcome),
+	))
+
+	if err != nil {
+		m.upstreamErrors.Add(ctx, 1, metric.WithAttributes(
+			tenant.Attribute(ctx),
+			attribute.String("upstream", upstream),
+			attribute.String("operation", operation),
+			attribute.String("error.category", cErrors.Category(err)),
+		))
+	}
+}
+
+// RecordTemperature records a temperature returned to a caller
+func (m *Metrics) RecordTemperature(ctx context.Context, celsius float64) {
+	if m == nil {
+		return
+	}
+
+	m.temperature.Record(ctx, celsius)
+}
+
+// ObserveQuota reports the budget left for upstream on every collection, whenever remaining knows it
+func (m *Metrics) ObserveQuota(upstream string, remaining func() (int64, bool)) error {
+	if m == nil {
+		return nil
+	}
+
+	attrs := metric.WithAttributes(attribute.String("upstream", upstream))
+	_, err := m.meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		if value, ok := remaining(); ok {
+			o.ObserveInt64(m.quotaRemaining, value, attrs)
+		}
+		return nil
+	}, m.quotaRemaining)
+	return err
+}
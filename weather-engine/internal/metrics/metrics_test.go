@@ -0,0 +1,190 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cErrors "github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client/error"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/tenant"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func setupMetrics(t *testing.T) (*Metrics, *sdkmetric.ManualReader) {
+	t.Helper()
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	m, err := New(provider.Meter(MeterName))
+	require.NoError(t, err)
+
+	return m, reader
+}
+
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Metrics {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	result := make(map[string]metricdata.Metrics)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			result[m.Name] = m
+		}
+	}
+	return result
+}
+
+func attributeValue(set attribute.Set, key string) string {
+	value, _ := set.Value(attribute.Key(key))
+	return value.Emit()
+}
+
+func TestRecordUpstream(t *testing.T) {
+	// arrange
+	m, reader := setupMetrics(t)
+	ctx, err := tenant.WithTenant(context.Background(), "team-a")
+	require.NoError(t, err)
+
+	// act
+	m.RecordUpstream(ctx, UpstreamViaCEP, "GetCep", 20*time.Millisecond, nil)
+	m.RecordUpstream(ctx, UpstreamWeatherAPI, "GetWeather", 30*time.Millisecond, cErrors.WeatherClientNotFound)
+	m.RecordUpstream(ctx, UpstreamWeatherAPI, "GetWeather", time.Second, context.DeadlineExceeded)
+
+	// assert
+	collected := collect(t, reader)
+
+	duration := collected["app.upstream.duration"].Data.(metricdata.Histogram[float64])
+	var total uint64
+	for _, dp := range duration.DataPoints {
+		total += dp.Count
+	}
+	assert.Equal(t, uint64(3), total)
+
+	upstreamErrors := collected["app.upstream.errors"].Data.(metricdata.Sum[int64])
+	categories := make(map[string]int64)
+	for _, dp := range upstreamErrors.DataPoints {
+		assert.Equal(t, UpstreamWeatherAPI, attributeValue(dp.Attributes, "upstream"))
+		assert.Equal(t, "team-a", attributeValue(dp.Attributes, tenant.BaggageKey))
+		categories[attributeValue(dp.Attributes, "error.category")] = dp.Value
+	}
+	assert.Equal(t, map[string]int64{"not_found": 1, "timeout": 1}, categories)
+}
+
+func TestObserveQuota(t *testing.T) {
+	// arrange
+	m, reader := setupMetrics(t)
+	remaining, known := int64(0), false
+	require.NoError(t, m.ObserveQuota(UpstreamWeatherAPI, func() (int64, bool) { return remaining, known }))
+
+	// act
+	unknown := collect(t, reader)
+	remaining, known = 950, true
+	observed := collect(t, reader)
+
+	// assert
+	_, ok := unknown["app.upstream.quota.remaining"]
+	assert.False(t, ok, "a cota desconhecida não deve ser reportada")
+
+	gauge := observed["app.upstream.quota.remaining"].Data.(metricdata.Gauge[int64])
+	require.Len(t, gauge.DataPoints, 1)
+	assert.Equal(t, int64(950), gauge.DataPoints[0].Value)
+	assert.Equal(t, UpstreamWeatherAPI, attributeValue(gauge.DataPoints[0].Attributes, "upstream"))
+}
+
+func TestRecordTemperature(t *testing.T) {
+	// arrange
+	m, reader := setupMetrics(t)
+
+	// act
+	m.RecordTemperature(context.Background(), 28.5)
+	m.RecordTemperature(context.Background(), -3)
+
+	// assert
+	collected := collect(t, reader)
+
+	temperature := collected["app.temperature"].Data.(metricdata.Histogram[float64])
+	require.Len(t, temperature.DataPoints, 1)
+	assert.Equal(t, uint64(2), temperature.DataPoints[0].Count)
+	assert.Equal(t, 25.5, temperature.DataPoints[0].Sum)
+}
+
+func TestExemplars(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		sampler   sdktrace.Sampler
+		exemplars bool
+	}{
+		{"span amostrado", sdktrace.AlwaysSample(), true},
+		{"span não amostrado", sdktrace.NeverSample(), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			m, reader := setupMetrics(t)
+			tracer := sdktrace.NewTracerProvider(sdktrace.WithSampler(tt.sampler)).Tracer("test")
+			ctx, span := tracer.Start(context.Background(), "request")
+			defer span.End()
+
+			router := gin.New()
+			router.Use(m.Middleware())
+			router.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			// act
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequestWithContext(ctx, http.MethodGet, "/ok", nil))
+			m.RecordUpstream(ctx, UpstreamViaCEP, "GetCep", 20*time.Millisecond, nil)
+
+			// assert
+			collected := collect(t, reader)
+			for _, name := range []string{"app.request.duration", "app.upstream.duration"} {
+				histogram := collected[name].Data.(metricdata.Histogram[float64])
+				require.Len(t, histogram.DataPoints, 1, name)
+
+				exemplars := histogram.DataPoints[0].Exemplars
+				if !tt.exemplars {
+					assert.Empty(t, exemplars, name)
+					continue
+				}
+				require.Len(t, exemplars, 1, name)
+				traceID := span.SpanContext().TraceID()
+				spanID := span.SpanContext().SpanID()
+				assert.Equal(t, traceID[:], exemplars[0].TraceID, name)
+				assert.Equal(t, spanID[:], exemplars[0].SpanID, name)
+			}
+		})
+	}
+}
+
+func TestNilMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// arrange
+	var m *Metrics
+	router := gin.New()
+	router.Use(m.Middleware())
+	router.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+	w := httptest.NewRecorder()
+
+	// act & assert
+	assert.NotPanics(t, func() {
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ok", nil))
+		m.RecordUpstream(context.Background(), UpstreamViaCEP, "GetCep", time.Millisecond, errors.New("boom"))
+		m.RecordTemperature(context.Background(), 20)
+		_ = m.ObserveQuota(UpstreamWeatherAPI, func() (int64, bool) { return 0, false })
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
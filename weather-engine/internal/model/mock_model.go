@@ -1,5 +1,7 @@
 package model
 
+import "fmt"
+
 func GetViacepResponseMock(zipCode string) *ViacepResponse {
 	return &ViacepResponse{
 		Erro:        nil,
@@ -67,3 +69,36 @@ func GetWeatherResponseMock(city string) *WeatherResponse {
 	response.Current.Gti = 972
 	return response
 }
+
+func GetForecastResponseMock(city string, days int) *ForecastResponse {
+	response := &ForecastResponse{WeatherResponse: *GetWeatherResponseMock(city)}
+
+	for d := 0; d < days; d++ {
+		day := ForecastDay{}
+		day.DateEpoch = 1768003200 + d*86400
+		day.Date = fmt.Sprintf("2026-01-%02d", 10+d)
+		day.Day.MaxtempC = 32.2
+		day.Day.MaxtempF = 90
+		day.Day.MintempC = 19.5
+		day.Day.MintempF = 67.1
+		day.Day.AvgtempC = 25
+		day.Day.AvgtempF = 77
+		day.Day.DailyWillItRain = 1
+		day.Day.DailyChanceOfRain = 87
+		day.Day.Condition.Text = "Patchy rain nearby"
+		day.Day.Condition.Code = 1063
+
+		for h := 0; h < 24; h++ {
+			hour := ForecastHour{}
+			hour.TimeEpoch = day.DateEpoch + h*3600
+			hour.Time = fmt.Sprintf("%s %02d:00", day.Date, h)
+			hour.TempC = 20 + float64(h)/2
+			hour.TempF = hour.TempC*1.8 + 32
+			hour.ChanceOfRain = 40
+			day.Hour = append(day.Hour, hour)
+		}
+
+		response.Forecast.Forecastday = append(response.Forecast.Forecastday, day)
+	}
+	return response
+}
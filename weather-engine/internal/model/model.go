@@ -1,6 +1,9 @@
 package model
 
-import "time"
+import (
+	"github.com/alexduzi/laboteldistributedtracing/platform/health"
+	"github.com/alexduzi/laboteldistributedtracing/platform/problem"
+)
 
 // ViacepResponse represents the response from ViaCEP API
 type ViacepResponse struct {
@@ -72,21 +75,141 @@ type WeatherResponse struct {
 	} `json:"current"`
 }
 
+// ForecastResponse represents the response from WeatherAPI forecast endpoint
+type ForecastResponse struct {
+	WeatherResponse
+	Forecast struct {
+		Forecastday []ForecastDay `json:"forecastday"`
+	} `json:"forecast"`
+}
+
+// ForecastDay represents a single day of the WeatherAPI forecast
+type ForecastDay struct {
+	Date      string `json:"date"`
+	DateEpoch int    `json:"date_epoch"`
+	Day       struct {
+		MaxtempC          float64 `json:"maxtemp_c"`
+		MaxtempF          float64 `json:"maxtemp_f"`
+		MintempC          float64 `json:"mintemp_c"`
+		MintempF          float64 `json:"mintemp_f"`
+		AvgtempC          float64 `json:"avgtemp_c"`
+		AvgtempF          float64 `json:"avgtemp_f"`
+		TotalprecipMm     float64 `json:"totalprecip_mm"`
+		Avghumidity       int     `json:"avghumidity"`
+		DailyWillItRain   int     `json:"daily_will_it_rain"`
+		DailyChanceOfRain int     `json:"daily_chance_of_rain"`
+		Condition         struct {
+			Text string `json:"text"`
+			Icon string `json:"icon"`
+			Code int    `json:"code"`
+		} `json:"condition"`
+		Uv float64 `json:"uv"`
+	} `json:"day"`
+	Hour []ForecastHour `json:"hour"`
+}
+
+// ForecastHour represents a single hour of a WeatherAPI forecast day
+type ForecastHour struct {
+	TimeEpoch    int     `json:"time_epoch"`
+	Time         string  `json:"time"`
+	TempC        float64 `json:"temp_c"`
+	TempF        float64 `json:"temp_f"`
+	IsDay        int     `json:"is_day"`
+	WillItRain   int     `json:"will_it_rain"`
+	ChanceOfRain int     `json:"chance_of_rain"`
+	Humidity     int     `json:"humidity"`
+	Condition    struct {
+		Text string `json:"text"`
+		Icon string `json:"icon"`
+		Code int    `json:"code"`
+	} `json:"condition"`
+}
+
 // TemperatureResponse represents temperature in different units
 type TemperatureResponse struct {
 	Celsius    float64 `json:"temp_C" example:"28.5"`
 	Fahrenheit float64 `json:"temp_F" example:"83.3"`
 	Kelvin     float64 `json:"temp_K" example:"301.65"`
+	// Degraded is set when the CEP providers were down and the temperature is that of the
+	// city resolved offline from the CEP range, usually the capital of its UF
+	Degraded   bool   `json:"degraded,omitempty" example:"true"`
+	Resolution string `json:"resolution,omitempty" example:"prefix"`
 }
 
-// StatusResponse represents the health/readiness status response
-type StatusResponse struct {
-	Status    string    `json:"status" example:"healthy"`
-	Timestamp time.Time `json:"timestamp" example:"2024-01-01T00:00:00Z"`
-	Service   string    `json:"service" example:"lab-cloudrun-api"`
+// TemperatureForecastResponse represents the daily temperature forecast for a location
+type TemperatureForecastResponse struct {
+	City string          `json:"city" example:"São Paulo"`
+	Days []DailyForecast `json:"days"`
+}
+
+// DailyForecast represents the temperature statistics of a forecast day
+type DailyForecast struct {
+	Date         string              `json:"date" example:"2026-01-10"`
+	Min          TemperatureResponse `json:"min"`
+	Max          TemperatureResponse `json:"max"`
+	Avg          TemperatureResponse `json:"avg"`
+	ChanceOfRain int                 `json:"chance_of_rain" example:"87"`
+	Hours        []HourlyForecast    `json:"hours"`
+}
+
+// HourlyForecast represents the temperature forecast of a single hour
+type HourlyForecast struct {
+	Time         string              `json:"time" example:"2026-01-10 14:00"`
+	Temperature  TemperatureResponse `json:"temperature"`
+	ChanceOfRain int                 `json:"chance_of_rain" example:"40"`
 }
 
-// ErrorResponse represents an error response
-type ErrorResponse struct {
-	Message string `json:"message" example:"invalid zipcode"`
+// TemperatureHistoryResponse represents the observed temperatures of a location over a date range
+type TemperatureHistoryResponse struct {
+	City    string             `json:"city" example:"São Paulo"`
+	From    string             `json:"from" example:"2026-01-01"`
+	To      string             `json:"to" example:"2026-01-07"`
+	Summary TemperatureStats   `json:"summary"`
+	Days    []DailyTemperature `json:"days"`
 }
+
+// DailyTemperature represents the temperature statistics observed on a single day
+type DailyTemperature struct {
+	Date string `json:"date" example:"2026-01-01"`
+	TemperatureStats
+}
+
+// TemperatureStats represents min, max and average temperatures in every unit
+type TemperatureStats struct {
+	Min TemperatureResponse `json:"min"`
+	Max TemperatureResponse `json:"max"`
+	Avg TemperatureResponse `json:"avg"`
+}
+
+// BatchTemperatureRequest represents a request for the temperature of several CEPs
+type BatchTemperatureRequest struct {
+	Ceps []string `json:"ceps" binding:"required" example:"01310-100,20040-002"`
+}
+
+// BatchTemperatureResponse represents the per-CEP results of a batch request, in input order
+type BatchTemperatureResponse struct {
+	Results []BatchTemperatureItem `json:"results"`
+}
+
+// BatchTemperatureItem represents the outcome of a single CEP in a batch request
+type BatchTemperatureItem struct {
+	Cep         string               `json:"cep" example:"01310-100"`
+	Status      int                  `json:"status" example:"200"`
+	Temperature *TemperatureResponse `json:"temperature,omitempty"`
+	Error       *ErrorResponse       `json:"error,omitempty"`
+}
+
+// StatusResponse represents the health/readiness status response
+type StatusResponse = health.StatusResponse
+
+// DependencyStatus represents the outcome of one readiness check
+type DependencyStatus = health.DependencyStatus
+
+// ErrorResponse represents an error in a batch item, and error responses in the legacy format
+type ErrorResponse = problem.ErrorResponse
+
+// Problem represents an RFC 7807 problem details error response
+type Problem = problem.Problem
+
+// ErrorDetail represents one validation failure of a request
+type ErrorDetail = problem.ErrorDetail
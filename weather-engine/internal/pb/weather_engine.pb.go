@@ -0,0 +1,483 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: weather_engine.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetTemperatureRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cep           string                 `protobuf:"bytes,1,opt,name=cep,proto3" json:"cep,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTemperatureRequest) Reset() {
+	*x = GetTemperatureRequest{}
+	mi := &file_weather_engine_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTemperatureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTemperatureRequest) ProtoMessage() {}
+
+func (x *GetTemperatureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_engine_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTemperatureRequest.ProtoReflect.Descriptor instead.
+func (*GetTemperatureRequest) Descriptor() ([]byte, []int) {
+	return file_weather_engine_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetTemperatureRequest) GetCep() string {
+	if x != nil {
+		return x.Cep
+	}
+	return ""
+}
+
+type GetTemperatureResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Temperature   *Temperature           `protobuf:"bytes,1,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTemperatureResponse) Reset() {
+	*x = GetTemperatureResponse{}
+	mi := &file_weather_engine_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTemperatureResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTemperatureResponse) ProtoMessage() {}
+
+func (x *GetTemperatureResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_engine_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTemperatureResponse.ProtoReflect.Descriptor instead.
+func (*GetTemperatureResponse) Descriptor() ([]byte, []int) {
+	return file_weather_engine_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetTemperatureResponse) GetTemperature() *Temperature {
+	if x != nil {
+		return x.Temperature
+	}
+	return nil
+}
+
+type BatchGetTemperatureRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ceps          []string               `protobuf:"bytes,1,rep,name=ceps,proto3" json:"ceps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetTemperatureRequest) Reset() {
+	*x = BatchGetTemperatureRequest{}
+	mi := &file_weather_engine_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetTemperatureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetTemperatureRequest) ProtoMessage() {}
+
+func (x *BatchGetTemperatureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_engine_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetTemperatureRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetTemperatureRequest) Descriptor() ([]byte, []int) {
+	return file_weather_engine_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BatchGetTemperatureRequest) GetCeps() []string {
+	if x != nil {
+		return x.Ceps
+	}
+	return nil
+}
+
+type BatchGetTemperatureResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Position of the CEP among the deduplicated input.
+	Index int32  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Cep   string `protobuf:"bytes,2,opt,name=cep,proto3" json:"cep,omitempty"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*BatchGetTemperatureResponse_Temperature
+	//	*BatchGetTemperatureResponse_Error
+	Result        isBatchGetTemperatureResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetTemperatureResponse) Reset() {
+	*x = BatchGetTemperatureResponse{}
+	mi := &file_weather_engine_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetTemperatureResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetTemperatureResponse) ProtoMessage() {}
+
+func (x *BatchGetTemperatureResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_engine_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetTemperatureResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetTemperatureResponse) Descriptor() ([]byte, []int) {
+	return file_weather_engine_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BatchGetTemperatureResponse) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BatchGetTemperatureResponse) GetCep() string {
+	if x != nil {
+		return x.Cep
+	}
+	return ""
+}
+
+func (x *BatchGetTemperatureResponse) GetResult() isBatchGetTemperatureResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *BatchGetTemperatureResponse) GetTemperature() *Temperature {
+	if x != nil {
+		if x, ok := x.Result.(*BatchGetTemperatureResponse_Temperature); ok {
+			return x.Temperature
+		}
+	}
+	return nil
+}
+
+func (x *BatchGetTemperatureResponse) GetError() *Error {
+	if x != nil {
+		if x, ok := x.Result.(*BatchGetTemperatureResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isBatchGetTemperatureResponse_Result interface {
+	isBatchGetTemperatureResponse_Result()
+}
+
+type BatchGetTemperatureResponse_Temperature struct {
+	Temperature *Temperature `protobuf:"bytes,3,opt,name=temperature,proto3,oneof"`
+}
+
+type BatchGetTemperatureResponse_Error struct {
+	Error *Error `protobuf:"bytes,4,opt,name=error,proto3,oneof"`
+}
+
+func (*BatchGetTemperatureResponse_Temperature) isBatchGetTemperatureResponse_Result() {}
+
+func (*BatchGetTemperatureResponse_Error) isBatchGetTemperatureResponse_Result() {}
+
+type Temperature struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Celsius    float64                `protobuf:"fixed64,1,opt,name=celsius,proto3" json:"celsius,omitempty"`
+	Fahrenheit float64                `protobuf:"fixed64,2,opt,name=fahrenheit,proto3" json:"fahrenheit,omitempty"`
+	Kelvin     float64                `protobuf:"fixed64,3,opt,name=kelvin,proto3" json:"kelvin,omitempty"`
+	// Set when the CEP providers were unavailable and the temperature is that of the
+	// city resolved offline from the CEP range, usually the capital of its UF.
+	Degraded bool `protobuf:"varint,4,opt,name=degraded,proto3" json:"degraded,omitempty"`
+	// How the CEP was resolved when degraded; "prefix" for the offline ranges.
+	Resolution    string `protobuf:"bytes,5,opt,name=resolution,proto3" json:"resolution,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Temperature) Reset() {
+	*x = Temperature{}
+	mi := &file_weather_engine_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Temperature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Temperature) ProtoMessage() {}
+
+func (x *Temperature) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_engine_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Temperature.ProtoReflect.Descriptor instead.
+func (*Temperature) Descriptor() ([]byte, []int) {
+	return file_weather_engine_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Temperature) GetCelsius() float64 {
+	if x != nil {
+		return x.Celsius
+	}
+	return 0
+}
+
+func (x *Temperature) GetFahrenheit() float64 {
+	if x != nil {
+		return x.Fahrenheit
+	}
+	return 0
+}
+
+func (x *Temperature) GetKelvin() float64 {
+	if x != nil {
+		return x.Kelvin
+	}
+	return 0
+}
+
+func (x *Temperature) GetDegraded() bool {
+	if x != nil {
+		return x.Degraded
+	}
+	return false
+}
+
+func (x *Temperature) GetResolution() string {
+	if x != nil {
+		return x.Resolution
+	}
+	return ""
+}
+
+type Error struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// gRPC status code of the failed lookup.
+	Code          int32  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_weather_engine_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_engine_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_weather_engine_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Error) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_weather_engine_proto protoreflect.FileDescriptor
+
+const file_weather_engine_proto_rawDesc = "" +
+	"\n" +
+	"\x14weather_engine.proto\x12\x10weatherengine.v1\")\n" +
+	"\x15GetTemperatureRequest\x12\x10\n" +
+	"\x03cep\x18\x01 \x01(\tR\x03cep\"Y\n" +
+	"\x16GetTemperatureResponse\x12?\n" +
+	"\vtemperature\x18\x01 \x01(\v2\x1d.weatherengine.v1.TemperatureR\vtemperature\"0\n" +
+	"\x1aBatchGetTemperatureRequest\x12\x12\n" +
+	"\x04ceps\x18\x01 \x03(\tR\x04ceps\"\xc3\x01\n" +
+	"\x1bBatchGetTemperatureResponse\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x10\n" +
+	"\x03cep\x18\x02 \x01(\tR\x03cep\x12A\n" +
+	"\vtemperature\x18\x03 \x01(\v2\x1d.weatherengine.v1.TemperatureH\x00R\vtemperature\x12/\n" +
+	"\x05error\x18\x04 \x01(\v2\x17.weatherengine.v1.ErrorH\x00R\x05errorB\b\n" +
+	"\x06result\"\x9b\x01\n" +
+	"\vTemperature\x12\x18\n" +
+	"\acelsius\x18\x01 \x01(\x01R\acelsius\x12\x1e\n" +
+	"\n" +
+	"fahrenheit\x18\x02 \x01(\x01R\n" +
+	"fahrenheit\x12\x16\n" +
+	"\x06kelvin\x18\x03 \x01(\x01R\x06kelvin\x12\x1a\n" +
+	"\bdegraded\x18\x04 \x01(\bR\bdegraded\x12\x1e\n" +
+	"\n" +
+	"resolution\x18\x05 \x01(\tR\n" +
+	"resolution\"5\n" +
+	"\x05Error\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage2\xea\x01\n" +
+	"\rWeatherEngine\x12c\n" +
+	"\x0eGetTemperature\x12'.weatherengine.v1.GetTemperatureRequest\x1a(.weatherengine.v1.GetTemperatureResponse\x12t\n" +
+	"\x13BatchGetTemperature\x12,.weatherengine.v1.BatchGetTemperatureRequest\x1a-.weatherengine.v1.BatchGetTemperatureResponse0\x01b\x06proto3"
+
+var (
+	file_weather_engine_proto_rawDescOnce sync.Once
+	file_weather_engine_proto_rawDescData []byte
+)
+
+func file_weather_engine_proto_rawDescGZIP() []byte {
+	file_weather_engine_proto_rawDescOnce.Do(func() {
+		file_weather_engine_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_weather_engine_proto_rawDesc), len(file_weather_engine_proto_rawDesc)))
+	})
+	return file_weather_engine_proto_rawDescData
+}
+
+var file_weather_engine_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_weather_engine_proto_goTypes = []any{
+	(*GetTemperatureRequest)(nil),       // 0: weatherengine.v1.GetTemperatureRequest
+	(*GetTemperatureResponse)(nil),      // 1: weatherengine.v1.GetTemperatureResponse
+	(*BatchGetTemperatureRequest)(nil),  // 2: weatherengine.v1.BatchGetTemperatureRequest
+	(*BatchGetTemperatureResponse)(nil), // 3: weatherengine.v1.BatchGetTemperatureResponse
+	(*Temperature)(nil),                 // 4: weatherengine.v1.Temperature
+	(*Error)(nil),                       // 5: weatherengine.v1.Error
+}
+var file_weather_engine_proto_depIdxs = []int32{
+	4, // 0: weatherengine.v1.GetTemperatureResponse.temperature:type_name -> weatherengine.v1.Temperature
+	4, // 1: weatherengine.v1.BatchGetTemperatureResponse.temperature:type_name -> weatherengine.v1.Temperature
+	5, // 2: weatherengine.v1.BatchGetTemperatureResponse.error:type_name -> weatherengine.v1.Error
+	0, // 3: weatherengine.v1.WeatherEngine.GetTemperature:input_type -> weatherengine.v1.GetTemperatureRequest
+	2, // 4: weatherengine.v1.WeatherEngine.BatchGetTemperature:input_type -> weatherengine.v1.BatchGetTemperatureRequest
+	1, // 5: weatherengine.v1.WeatherEngine.GetTemperature:output_type -> weatherengine.v1.GetTemperatureResponse
+	3, // 6: weatherengine.v1.WeatherEngine.BatchGetTemperature:output_type -> weatherengine.v1.BatchGetTemperatureResponse
+	5, // [5:7] is the sub-list for method output_type
+	3, // [3:5] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_weather_engine_proto_init() }
+func file_weather_engine_proto_init() {
+	if File_weather_engine_proto != nil {
+		return
+	}
+	file_weather_engine_proto_msgTypes[3].OneofWrappers = []any{
+		(*BatchGetTemperatureResponse_Temperature)(nil),
+		(*BatchGetTemperatureResponse_Error)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_weather_engine_proto_rawDesc), len(file_weather_engine_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_weather_engine_proto_goTypes,
+		DependencyIndexes: file_weather_engine_proto_depIdxs,
+		MessageInfos:      file_weather_engine_proto_msgTypes,
+	}.Build()
+	File_weather_engine_proto = out.File
+	file_weather_engine_proto_goTypes = nil
+	file_weather_engine_proto_depIdxs = nil
+}
@@ -0,0 +1,175 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.0
+// - protoc             (unknown)
+// source: weather_engine.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	WeatherEngine_GetTemperature_FullMethodName      = "/weatherengine.v1.WeatherEngine/GetTemperature"
+	WeatherEngine_BatchGetTemperature_FullMethodName = "/weatherengine.v1.WeatherEngine/BatchGetTemperature"
+)
+
+// WeatherEngineClient is the client API for WeatherEngine service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// WeatherEngine resolves CEPs into their current temperature.
+// Go code is generated into each service through the M import mapping (see `make proto`).
+type WeatherEngineClient interface {
+	// GetTemperature returns the current temperature for a single CEP.
+	GetTemperature(ctx context.Context, in *GetTemperatureRequest, opts ...grpc.CallOption) (*GetTemperatureResponse, error)
+	// BatchGetTemperature streams one result per distinct CEP as soon as its lookup
+	// completes; index gives the position of the CEP among the distinct ones in input order.
+	BatchGetTemperature(ctx context.Context, in *BatchGetTemperatureRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BatchGetTemperatureResponse], error)
+}
+
+type weatherEngineClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWeatherEngineClient(cc grpc.ClientConnInterface) WeatherEngineClient {
+	return &weatherEngineClient{cc}
+}
+
+func (c *weatherEngineClient) GetTemperature(ctx context.Context, in *GetTemperatureRequest, opts ...grpc.CallOption) (*GetTemperatureResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTemperatureResponse)
+	err := c.cc.Invoke(ctx, WeatherEngine_GetTemperature_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *weatherEngineClient) BatchGetTemperature(ctx context.Context, in *BatchGetTemperatureRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BatchGetTemperatureResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &WeatherEngine_ServiceDesc.Streams[0], WeatherEngine_BatchGetTemperature_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[BatchGetTemperatureRequest, BatchGetTemperatureResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WeatherEngine_BatchGetTemperatureClient = grpc.ServerStreamingClient[BatchGetTemperatureResponse]
+
+// WeatherEngineServer is the server API for WeatherEngine service.
+// All implementations must embed UnimplementedWeatherEngineServer
+// for forward compatibility.
+//
+// WeatherEngine resolves CEPs into their current temperature.
+// Go code is generated into each service through the M import mapping (see `make proto`).
+type WeatherEngineServer interface {
+	// GetTemperature returns the current temperature for a single CEP.
+	GetTemperature(context.Context, *GetTemperatureRequest) (*GetTemperatureResponse, error)
+	// BatchGetTemperature streams one result per distinct CEP as soon as its lookup
+	// completes; index gives the position of the CEP among the distinct ones in input order.
+	BatchGetTemperature(*BatchGetTemperatureRequest, grpc.ServerStreamingServer[BatchGetTemperatureResponse]) error
+	mustEmbedUnimplementedWeatherEngineServer()
+}
+
+// UnimplementedWeatherEngineServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWeatherEngineServer struct{}
+
+func (UnimplementedWeatherEngineServer) GetTemperature(context.Context, *GetTemperatureRequest) (*GetTemperatureResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTemperature not implemented")
+}
+func (UnimplementedWeatherEngineServer) BatchGetTemperature(*BatchGetTemperatureRequest, grpc.ServerStreamingServer[BatchGetTemperatureResponse]) error {
+	return status.Error(codes.Unimplemented, "method BatchGetTemperature not implemented")
+}
+func (UnimplementedWeatherEngineServer) mustEmbedUnimplementedWeatherEngineServer() {}
+func (UnimplementedWeatherEngineServer) testEmbeddedByValue()                       {}
+
+// UnsafeWeatherEngineServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WeatherEngineServer will
+// result in compilation errors.
+type UnsafeWeatherEngineServer interface {
+	mustEmbedUnimplementedWeatherEngineServer()
+}
+
+func RegisterWeatherEngineServer(s grpc.ServiceRegistrar, srv WeatherEngineServer) {
+	// If the following call panics, it indicates UnimplementedWeatherEngineServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&WeatherEngine_ServiceDesc, srv)
+}
+
+func _WeatherEngine_GetTemperature_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTemperatureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WeatherEngineServer).GetTemperature(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WeatherEngine_GetTemperature_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WeatherEngineServer).GetTemperature(ctx, req.(*GetTemperatureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WeatherEngine_BatchGetTemperature_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BatchGetTemperatureRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WeatherEngineServer).BatchGetTemperature(m, &grpc.GenericServerStream[BatchGetTemperatureRequest, BatchGetTemperatureResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WeatherEngine_BatchGetTemperatureServer = grpc.ServerStreamingServer[BatchGetTemperatureResponse]
+
+// WeatherEngine_ServiceDesc is the grpc.ServiceDesc for WeatherEngine service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WeatherEngine_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "weatherengine.v1.WeatherEngine",
+	HandlerType: (*WeatherEngineServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetTemperature",
+			Handler:    _WeatherEngine_GetTemperature_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BatchGetTemperature",
+			Handler:       _WeatherEngine_BatchGetTemperature_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "weather_engine.proto",
+}
@@ -0,0 +1,132 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alexduzi/laboteldistributedtracing/platform/server"
+	cErrors "github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client/error"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/model"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/pb"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/service"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/tenant"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WeatherEngineServer exposes the temperature service over gRPC
+type WeatherEngineServer struct {
+	pb.UnimplementedWeatherEngineServer
+	service      service.TemperatureServiceInterface
+	batchMaxSize int
+}
+
+// NewWeatherEngineServer serves temperatureService, rejecting batches of more than batchMaxSize CEPs like the HTTP API
+func NewWeatherEngineServer(temperatureService service.TemperatureServiceInterface, batchMaxSize int) *WeatherEngineServer {
+	return &WeatherEngineServer{service: temperatureService, batchMaxSize: batchMaxSize}
+}
+
+// NewServer builds a gRPC server instrumented with OpenTelemetry and registers the WeatherEngine service
+func NewServer(temperatureService service.TemperatureServiceInterface, batchMaxSize int, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{grpc.StatsHandler(otelgrpc.NewServerHandler())}, opts...)
+
+	grpcServer := grpc.NewServer(opts...)
+	pb.RegisterWeatherEngineServer(grpcServer, NewWeatherEngineServer(temperatureService, batchMaxSize))
+	return grpcServer
+}
+
+// GracefulStop drains the in-flight RPCs of s, stopping it outright when ctx is done first
+func GracefulStop(s *grpc.Server) server.ShutdownFunc {
+	return func(ctx context.Context) error {
+		stopped := make(chan struct{})
+		go func() {
+			s.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+			return nil
+		case <-ctx.Done():
+			s.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *WeatherEngineServer) GetTemperature(ctx context.Context, req *pb.GetTemperatureRequest) (*pb.GetTemperatureResponse, error) {
+	temperature, err := s.service.GetTemperature(ctx, req.GetCep())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &pb.GetTemperatureResponse{Temperature: toProto(temperature)}, nil
+}
+
+// BatchGetTemperature sends each result as soon as its lookup completes; Index tells the
+// position of its CEP among the distinct ones in input order
+func (s *WeatherEngineServer) BatchGetTemperature(req *pb.BatchGetTemperatureRequest, stream grpc.ServerStreamingServer[pb.BatchGetTemperatureResponse]) error {
+	if len(req.GetCeps()) == 0 {
+		return status.Error(codes.InvalidArgument, "ceps must not be empty")
+	}
+	if len(req.GetCeps()) > s.batchMaxSize {
+		return status.Errorf(codes.InvalidArgument, "batch must not exceed %d ceps", s.batchMaxSize)
+	}
+
+	return s.service.EachTemperature(stream.Context(), req.GetCeps(), func(index int, res service.TemperatureResult) error {
+		msg := &pb.BatchGetTemperatureResponse{Index: int32(index), Cep: res.Cep}
+		if res.Err != nil {
+			msg.Result = &pb.BatchGetTemperatureResponse_Error{Error: &pb.Error{
+				Code:    int32(statusCode(res.Err)),
+				Message: res.Err.Error(),
+			}}
+		} else {
+			msg.Result = &pb.BatchGetTemperatureResponse_Temperature{Temperature: toProto(res.Temperature)}
+		}
+		return stream.Send(msg)
+	})
+}
+
+// reasonQuotaExceeded is the ErrorInfo reason of quota errors, the problem code the HTTP API answers
+const reasonQuotaExceeded = "quota_exceeded"
+
+// statusError returns the status error answered for err, telling when to retry
+// once the weather provider quota is exhausted
+func statusError(err error) error {
+	var quotaErr *cErrors.QuotaError
+	if errors.As(err, &quotaErr) {
+		return tenant.RetryableError(codes.Unavailable, reasonQuotaExceeded, err.Error(), quotaErr.Delay())
+	}
+	return status.Error(statusCode(err), err.Error())
+}
+
+// statusCode maps the service and client errors into gRPC status codes
+func statusCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, service.ErrInvalidZipcode), errors.Is(err, cErrors.CepClientBadRequest):
+		return codes.InvalidArgument
+	case errors.Is(err, service.ErrZipcodeNotFound), errors.Is(err, cErrors.CepClientNotFound),
+		errors.Is(err, cErrors.WeatherClientBadRequest), errors.Is(err, cErrors.WeatherClientNotFound):
+		return codes.NotFound
+	case errors.Is(err, cErrors.WeatherClientQuotaExceeded):
+		return codes.Unavailable
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
+
+func toProto(temperature *model.TemperatureResponse) *pb.Temperature {
+	return &pb.Temperature{
+		Celsius:    temperature.Celsius,
+		Fahrenheit: temperature.Fahrenheit,
+		Kelvin:     temperature.Kelvin,
+		Degraded:   temperature.Degraded,
+		Resolution: temperature.Resolution,
+	}
+}
@@ -0,0 +1,243 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	cErrors "github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client/error"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/model"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/pb"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testBatchMaxSize = 3
+
+func setupBufconn(t *testing.T) (pb.WeatherEngineClient, *service.TemperatureServiceStub) {
+	svc := service.NewTemperatureServiceStub()
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := NewServer(svc, testBatchMaxSize)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return pb.NewWeatherEngineClient(conn), svc
+}
+
+func TestGetTemperature_Success(t *testing.T) {
+	// arrange
+	client, svc := setupBufconn(t)
+
+	svc.On("GetTemperature", mock.Anything, "01310100").
+		Return(&model.TemperatureResponse{Celsius: 28.5, Fahrenheit: 83.3, Kelvin: 301.65}, nil)
+
+	// act
+	res, err := client.GetTemperature(context.Background(), &pb.GetTemperatureRequest{Cep: "01310100"})
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, 28.5, res.GetTemperature().GetCelsius())
+	assert.Equal(t, 83.3, res.GetTemperature().GetFahrenheit())
+	assert.Equal(t, 301.65, res.GetTemperature().GetKelvin())
+}
+
+func TestGetTemperature_Degraded(t *testing.T) {
+	// arrange
+	client, svc := setupBufconn(t)
+
+	svc.On("GetTemperature", mock.Anything, "17500000").
+		Return(&model.TemperatureResponse{Celsius: 28.5, Fahrenheit: 83.3, Kelvin: 301.65, Degraded: true, Resolution: "prefix"}, nil)
+
+	// act
+	res, err := client.GetTemperature(context.Background(), &pb.GetTemperatureRequest{Cep: "17500000"})
+
+	// assert
+	require.NoError(t, err)
+	assert.True(t, res.GetTemperature().GetDegraded())
+	assert.Equal(t, "prefix", res.GetTemperature().GetResolution())
+}
+
+func TestGetTemperature_ErrorCodes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected codes.Code
+	}{
+		{"CEP inválido", service.ErrInvalidZipcode, codes.InvalidArgument},
+		{"CEP não encontrado", service.ErrZipcodeNotFound, codes.NotFound},
+		{"Clima não encontrado", cErrors.WeatherClientNotFound, codes.NotFound},
+		{"Cota do provedor esgotada", &cErrors.QuotaError{Window: cErrors.QuotaWindowMinute}, codes.Unavailable},
+		{"Erro interno", cErrors.WeatherClientInternalError, codes.Internal},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			client, svc := setupBufconn(t)
+			svc.On("GetTemperature", mock.Anything, "01310100").Return(nil, tc.err)
+
+			// act
+			res, err := client.GetTemperature(context.Background(), &pb.GetTemperatureRequest{Cep: "01310100"})
+
+			// assert
+			assert.Nil(t, res)
+			assert.Equal(t, tc.expected, status.Code(err))
+		})
+	}
+}
+
+func TestGetTemperature_QuotaRetryInfo(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected time.Duration
+	}{
+		{"Janela com reset conhecido", &cErrors.QuotaError{Window: cErrors.QuotaWindowMinute, RetryAfter: 45 * time.Second}, 45 * time.Second},
+		{"Janela do provedor sem reset conhecido", &cErrors.QuotaError{Window: cErrors.QuotaWindowProvider}, cErrors.DefaultRetryAfter},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			client, svc := setupBufconn(t)
+			svc.On("GetTemperature", mock.Anything, "01310100").Return(nil, tc.err)
+
+			// act
+			_, err := client.GetTemperature(context.Background(), &pb.GetTemperatureRequest{Cep: "01310100"})
+
+			// assert
+			assert.Equal(t, codes.Unavailable, status.Code(err))
+			details := status.Convert(err).Details()
+			require.Len(t, details, 2)
+			info, ok := details[0].(*errdetails.ErrorInfo)
+			require.True(t, ok)
+			assert.Equal(t, "quota_exceeded", info.GetReason())
+			retry, ok := details[1].(*errdetails.RetryInfo)
+			require.True(t, ok)
+			assert.Equal(t, tc.expected, retry.GetRetryDelay().AsDuration())
+		})
+	}
+}
+
+func TestBatchGetTemperature_StreamsResults(t *testing.T) {
+	// arrange
+	client, svc := setupBufconn(t)
+
+	svc.On("EachTemperature", mock.Anything, []string{"01310100", "abc", "99999999"}).Return([]service.TemperatureResult{
+		{Cep: "01310100", Temperature: &model.TemperatureResponse{Celsius: 28.5}},
+		{Cep: "abc", Err: service.ErrInvalidZipcode},
+		{Cep: "99999999", Err: service.ErrZipcodeNotFound},
+	})
+
+	// act
+	stream, err := client.BatchGetTemperature(context.Background(), &pb.BatchGetTemperatureRequest{
+		Ceps: []string{"01310100", "abc", "99999999"},
+	})
+	require.NoError(t, err)
+
+	var results []*pb.BatchGetTemperatureResponse
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		results = append(results, res)
+	}
+
+	// assert
+	require.Len(t, results, 3)
+	assert.Equal(t, int32(0), results[0].GetIndex())
+	assert.Equal(t, 28.5, results[0].GetTemperature().GetCelsius())
+	assert.Equal(t, "abc", results[1].GetCep())
+	assert.Equal(t, int32(codes.InvalidArgument), results[1].GetError().GetCode())
+	assert.Equal(t, int32(codes.NotFound), results[2].GetError().GetCode())
+}
+
+func TestBatchGetTemperature_EmptyRequest(t *testing.T) {
+	// arrange
+	client, _ := setupBufconn(t)
+
+	// act
+	stream, err := client.BatchGetTemperature(context.Background(), &pb.BatchGetTemperatureRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+
+	// assert
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestBatchGetTemperature_OversizeRequest(t *testing.T) {
+	// arrange
+	client, svc := setupBufconn(t)
+
+	// act
+	stream, err := client.BatchGetTemperature(context.Background(), &pb.BatchGetTemperatureRequest{
+		Ceps: []string{"01310100", "20040020", "30130010", "40020000"},
+	})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+
+	// assert
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "batch must not exceed 3 ceps", status.Convert(err).Message())
+	svc.AssertNotCalled(t, "EachTemperature", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetTemperature_ContinuesIncomingTrace(t *testing.T) {
+	// arrange
+	recorder := tracetest.NewSpanRecorder()
+	previousProvider := otel.GetTracerProvider()
+	previousPropagator := otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	client, svc := setupBufconn(t)
+	svc.On("GetTemperature", mock.Anything, "01310100").Return(&model.TemperatureResponse{}, nil)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := metadata.AppendToOutgoingContext(context.Background(),
+		"traceparent", "00-"+traceID.String()+"-"+spanID.String()+"-01")
+
+	// act
+	_, err := client.GetTemperature(ctx, &pb.GetTemperatureRequest{Cep: "01310100"})
+
+	// assert
+	require.NoError(t, err)
+	require.NotEmpty(t, recorder.Ended())
+	serverSpan := recorder.Ended()[0]
+	assert.Equal(t, trace.SpanKindServer, serverSpan.SpanKind())
+	assert.Equal(t, traceID, serverSpan.SpanContext().TraceID())
+	assert.Equal(t, spanID, serverSpan.Parent().SpanID())
+}
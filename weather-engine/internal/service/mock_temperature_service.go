@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type TemperatureServiceStub struct {
+	mock.Mock
+}
+
+func NewTemperatureServiceStub() *TemperatureServiceStub {
+	return &TemperatureServiceStub{}
+}
+
+func (s *TemperatureServiceStub) GetTemperature(ctx context.Context, cep string) (*model.TemperatureResponse, error) {
+	args := s.Called(ctx, cep)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TemperatureResponse), nil
+}
+
+func (s *TemperatureServiceStub) GetTemperatures(ctx context.Context, ceps []string) []TemperatureResult {
+	args := s.Called(ctx, ceps)
+	return args.Get(0).([]TemperatureResult)
+}
+
+// EachTemperature calls fn with the results configured for GetTemperatures, in their order
+func (s *TemperatureServiceStub) EachTemperature(ctx context.Context, ceps []string, fn func(index int, res TemperatureResult) error) error {
+	args := s.Called(ctx, ceps)
+	for i, res := range args.Get(0).([]TemperatureResult) {
+		if err := fn(i, res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
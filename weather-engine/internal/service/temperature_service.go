@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/alexduzi/laboteldistributedtracing/cep"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/cache"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client"
+	cErrors "github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client/error"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/conversor"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/metrics"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/model"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/tenant"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
+)
+
+const tracerName = "github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/service"
+
+// Resolutions of a CEP, recorded as the cep.resolution span attribute
+const (
+	ResolutionProvider = "provider"
+	ResolutionPrefix   = "prefix"
+)
+
+var (
+	ErrInvalidZipcode  = errors.New("invalid zipcode")
+	ErrZipcodeNotFound = errors.New("can not find zipcode")
+)
+
+type TemperatureServiceInterface interface {
+	GetTemperature(ctx context.Context, cep string) (*model.TemperatureResponse, error)
+	GetTemperatures(ctx context.Context, ceps []string) []TemperatureResult
+	EachTemperature(ctx context.Context, ceps []string, fn func(index int, res TemperatureResult) error) error
+}
+
+// TemperatureResult holds the outcome of one CEP of a batch lookup
+type TemperatureResult struct {
+	Cep         string
+	Temperature *model.TemperatureResponse
+	Err         error
+}
+
+type TemperatureService struct {
+	config        *config.Config
+	cepClient     client.CepClientInterface
+	weatherClient client.WeatherClientInterface
+	metrics       *metrics.Metrics
+	cache         *cache.Cache[model.TemperatureResponse]
+}
+
+func NewTemperatureService(cfg *config.Config, cepClient client.CepClientInterface, weatherClient client.WeatherClientInterface, m *metrics.Metrics) *TemperatureService {
+	return &TemperatureService{
+		config:        cfg,
+		cepClient:     cepClient,
+		weatherClient: weatherClient,
+		metrics:       m,
+		cache:         cache.New[model.TemperatureResponse](cfg.CacheTTL, cfg.CacheMaxEntries),
+	}
+}
+
+// GetTemperature resolves the CEP into its city and returns the current temperature in every unit.
+// Results are cached in the partition of the tenant found in the context baggage, except for
+// degraded ones, answered for the city resolved offline while the CEP provider is down.
+func (s *TemperatureService) GetTemperature(ctx context.Context, raw string) (*model.TemperatureResponse, error) {
+	code, err := cep.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidZipcode, err)
+	}
+	normalized := code.String()
+
+	tenantID := tenant.FromContext(ctx)
+	cached, hit := s.cache.Get(tenantID, normalized)
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String(tenant.BaggageKey, tenantID),
+		attribute.Bool("cache.hit", hit),
+	)
+	if hit {
+		return &cached, nil
+	}
+
+	city, resolution, err := s.resolveCity(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("cep.resolution", resolution))
+
+	weather, err := s.weatherClient.GetWeather(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+
+	temperature := s.convert(ctx, weather)
+	s.metrics.RecordTemperature(ctx, temperature.Celsius)
+
+	if resolution == ResolutionPrefix {
+		// not cached, so the next request goes back to the providers
+		temperature.Degraded, temperature.Resolution = true, resolution
+		return &temperature, nil
+	}
+	s.cache.Set(tenantID, normalized, temperature)
+	return &temperature, nil
+}
+
+// resolveCity finds the city of the CEP on the CEP provider. When the provider is unavailable
+// and the fallback is enabled, the city is resolved offline from the CEP range instead.
+func (s *TemperatureService) resolveCity(ctx context.Context, code cep.CEP) (city, resolution string, err error) {
+	location, err := s.cepClient.GetCep(ctx, code.String())
+	if err == nil {
+		if location.Erro != nil {
+			return "", "", ErrZipcodeNotFound
+		}
+		return location.Localidade, ResolutionProvider, nil
+	}
+
+	if !s.config.CepPrefixFallback || !providerUnavailable(ctx, err) {
+		return "", "", err
+	}
+	fallback, ok := cep.Locate(code)
+	if !ok {
+		return "", "", err
+	}
+
+	trace.SpanFromContext(ctx).AddEvent("cep resolved offline", trace.WithAttributes(
+		attribute.String("cep.uf", fallback.UF),
+		attribute.String("cep.city", fallback.City),
+		attribute.String("error", err.Error()),
+	))
+	return fallback.City, ResolutionPrefix, nil
+}
+
+// providerUnavailable reports whether the CEP provider failed to answer, as opposed to
+// rejecting the CEP or the caller giving up on the request
+func providerUnavailable(ctx context.Context, err error) bool {
+	return ctx.Err() == nil &&
+		!errors.Is(err, cErrors.CepClientBadRequest) &&
+		!errors.Is(err, cErrors.CepClientNotFound)
+}
+
+// convert turns the weather response into every temperature unit, inside an
+// internal span when fine-grained instrumentation is enabled
+func (s *TemperatureService) convert(ctx context.Context, weather *model.WeatherResponse) model.TemperatureResponse {
+	if !s.config.TraceInternalSpans {
+		return conversor.ConvertWeatherResponse(*weather)
+	}
+
+	_, span := otel.Tracer(tracerName).Start(ctx, "convert temperature")
+	defer span.End()
+
+	return conversor.ConvertWeatherResponse(*weather)
+}
+
+// GetTemperatures looks up every distinct CEP through a bounded worker pool.
+// Results follow the order in which each CEP first appears in the input.
+func (s *TemperatureService) GetTemperatures(ctx context.Context, ceps []string) []TemperatureResult {
+	results := make([]TemperatureResult, len(cep.Deduplicate(ceps)))
+	_ = s.EachTemperature(ctx, ceps, func(index int, res TemperatureResult) error {
+		results[index] = res
+		return nil
+	})
+	return results
+}
+
+// EachTemperature looks up every distinct CEP through a bounded worker pool, calling fn
+// with each result as soon as its lookup completes, along with the position of the CEP
+// among the distinct ones in input order. fn is never called concurrently; once it fails
+// the pending lookups are canceled and its error is returned.
+func (s *TemperatureService) EachTemperature(ctx context.Context, ceps []string, fn func(index int, res TemperatureResult) error) error {
+	unique := cep.Deduplicate(ceps)
+
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "batch temperature")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("batch.size", len(ceps)),
+		attribute.Int("batch.unique", len(unique)),
+		attribute.Int("batch.concurrency", s.config.BatchConcurrency),
+	)
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(s.config.BatchConcurrency, 1))
+
+	var (
+		mu      sync.Mutex
+		failed  int
+		stopped bool
+	)
+	for i, cep := range unique {
+		g.Go(func() error {
+			res := s.lookup(gctx, cep)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if stopped {
+				return nil
+			}
+			if res.Err != nil {
+				failed++
+			}
+			if err := fn(i, res); err != nil {
+				stopped = true
+				return err
+			}
+			return nil
+		})
+	}
+	err := g.Wait()
+	span.SetAttributes(attribute.Int("batch.failed", failed))
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+func (s *TemperatureService) lookup(ctx context.Context, cep string) TemperatureResult {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "temperature lookup")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("cep", cep))
+
+	temperature, err := s.GetTemperature(ctx, cep)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return TemperatureResult{Cep: cep, Temperature: temperature, Err: err}
+}
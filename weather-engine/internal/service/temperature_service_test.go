@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client"
+	cErrors "github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/client/error"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/config"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/model"
+	"github.com/alexduzi/laboteldistributedtracing/weatherengine/internal/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTestService() (*TemperatureService, *client.CepClientStub, *client.WeatherClientStub) {
+	cepClient := client.NewCepClientStub(nil)
+	weatherClient := client.NewWeatherClientStub(nil)
+	cfg := &config.Config{BatchMaxSize: 250, BatchConcurrency: 4}
+	return NewTemperatureService(cfg, cepClient, weatherClient, nil), cepClient, weatherClient
+}
+
+func TestGetTemperature_Success(t *testing.T) {
+	// arrange
+	svc, cepClient, weatherClient := newTestService()
+
+	cepClient.On("GetCep", mock.Anything, "01310100").Return(model.GetViacepResponseMock("01310-100"), nil)
+	weatherClient.On("GetWeather", mock.Anything, "São Paulo").Return(model.GetWeatherResponseMock("São Paulo"), nil)
+
+	// act
+	result, err := svc.GetTemperature(context.Background(), "01310-100")
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, &model.TemperatureResponse{Celsius: 32.2, Fahrenheit: 89.96, Kelvin: 305.35}, result)
+}
+
+func TestGetTemperature_InvalidZipcode(t *testing.T) {
+	for _, input := range []string{"1234", "0131A100", "00000000"} {
+		t.Run(input, func(t *testing.T) {
+			// arrange
+			svc, cepClient, _ := newTestService()
+
+			// act
+			result, err := svc.GetTemperature(context.Background(), input)
+
+			// assert
+			assert.Nil(t, result)
+			assert.ErrorIs(t, err, ErrInvalidZipcode)
+			cepClient.AssertNotCalled(t, "GetCep", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetTemperature_ZipcodeNotFound(t *testing.T) {
+	// arrange
+	svc, cepClient, _ := newTestService()
+
+	erro := "true"
+	cepClient.On("GetCep", mock.Anything, "99999999").Return(&model.ViacepResponse{Erro: &erro}, nil)
+
+	// act
+	result, err := svc.GetTemperature(context.Background(), "99999999")
+
+	// assert
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrZipcodeNotFound)
+}
+
+func TestGetTemperature_PrefixFallback(t *testing.T) {
+	// arrange
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	cepClient := client.NewCepClientStub(nil)
+	weatherClient := client.NewWeatherClientStub(nil)
+	cfg := &config.Config{CepPrefixFallback: true, CacheTTL: time.Minute, CacheMaxEntries: 10}
+	svc := NewTemperatureService(cfg, cepClient, weatherClient, nil)
+
+	cepClient.On("GetCep", mock.Anything, "17500000").Return(nil, cErrors.CepClientInternalError)
+	weatherClient.On("GetWeather", mock.Anything, "São Paulo").Return(model.GetWeatherResponseMock("São Paulo"), nil)
+
+	ctx, span := otel.Tracer("test").Start(context.Background(), "request")
+
+	// act
+	result, err := svc.GetTemperature(ctx, "17500-000")
+	_, again := svc.GetTemperature(ctx, "17500-000")
+	span.End()
+
+	// assert
+	require.NoError(t, err)
+	require.NoError(t, again)
+	assert.Equal(t, &model.TemperatureResponse{Celsius: 32.2, Fahrenheit: 89.96, Kelvin: 305.35, Degraded: true, Resolution: ResolutionPrefix}, result)
+	cepClient.AssertNumberOfCalls(t, "GetCep", 2)
+
+	ended := recorder.Ended()
+	require.Len(t, ended, 1)
+	assert.Contains(t, ended[0].Attributes(), attribute.String("cep.resolution", ResolutionPrefix))
+	require.NotEmpty(t, ended[0].Events())
+	assert.Equal(t, "cep resolved offline", ended[0].Events()[0].Name)
+}
+
+func TestGetTemperature_PrefixFallbackNotApplied(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	testCases := []struct {
+		name     string
+		enabled  bool
+		ctx      context.Context
+		input    string
+		cepErr   error
+		expected error
+	}{
+		{"Fallback desligado", false, context.Background(), "01310100", cErrors.CepClientInternalError, cErrors.CepClientInternalError},
+		{"CEP inexistente no provedor", true, context.Background(), "01310100", cErrors.CepClientNotFound, cErrors.CepClientNotFound},
+		{"Requisição inválida no provedor", true, context.Background(), "01310100", cErrors.CepClientBadRequest, cErrors.CepClientBadRequest},
+		{"Requisição cancelada", true, canceled, "01310100", context.Canceled, context.Canceled},
+		{"CEP fora das faixas conhecidas", true, context.Background(), "78900000", cErrors.CepClientInternalError, cErrors.CepClientInternalError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			cepClient := client.NewCepClientStub(nil)
+			weatherClient := client.NewWeatherClientStub(nil)
+			svc := NewTemperatureService(&config.Config{CepPrefixFallback: tc.enabled}, cepClient, weatherClient, nil)
+
+			cepClient.On("GetCep", mock.Anything, tc.input).Return(nil, tc.cepErr)
+
+			// act
+			result, err := svc.GetTemperature(tc.ctx, tc.input)
+
+			// assert
+			assert.Nil(t, result)
+			assert.ErrorIs(t, err, tc.expected)
+			weatherClient.AssertNotCalled(t, "GetWeather", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetTemperatures_DeduplicatesAndKeepsInputOrder(t *testing.T) {
+	// arrange
+	svc, cepClient, weatherClient := newTestService()
+
+	cepClient.On("GetCep", mock.Anything, "01310100").Return(model.GetViacepResponseMock("01310-100"), nil)
+	cepClient.On("GetCep", mock.Anything, "20040002").Return(nil, cErrors.CepClientInternalError)
+	weatherClient.On("GetWeather", mock.Anything, "São Paulo").Return(model.GetWeatherResponseMock("São Paulo"), nil)
+
+	// act
+	results := svc.GetTemperatures(context.Background(), []string{"01310-100", "abc", "20040002", "01310100", " 01310-100 "})
+
+	// assert
+	require.Len(t, results, 3)
+	assert.Equal(t, "01310100", results[0].Cep)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, 32.2, results[0].Temperature.Celsius)
+	assert.Equal(t, "abc", results[1].Cep)
+	assert.ErrorIs(t, results[1].Err, ErrInvalidZipcode)
+	assert.Equal(t, "20040002", results[2].Cep)
+	assert.ErrorIs(t, results[2].Err, cErrors.CepClientInternalError)
+
+	cepClient.AssertNumberOfCalls(t, "GetCep", 2)
+}
+
+func TestEachTemperature_DeliversResultsAsTheyComplete(t *testing.T) {
+	// arrange
+	svc, cepClient, weatherClient := newTestService()
+
+	cepClient.On("GetCep", mock.Anything, "01310100").After(50*time.Millisecond).Return(model.GetViacepResponseMock("01310-100"), nil)
+	cepClient.On("GetCep", mock.Anything, "20040002").Return(nil, cErrors.CepClientInternalError)
+	weatherClient.On("GetWeather", mock.Anything, "São Paulo").Return(model.GetWeatherResponseMock("São Paulo"), nil)
+
+	// act
+	var order []int
+	results := make(map[int]TemperatureResult)
+	err := svc.EachTemperature(context.Background(), []string{"01310-100", "abc", "20040002"}, func(index int, res TemperatureResult) error {
+		order = append(order, index)
+		results[index] = res
+		return nil
+	})
+
+	// assert
+	require.NoError(t, err)
+	require.Len(t, order, 3)
+	assert.Equal(t, 0, order[2], "o CEP mais lento deve ser entregue por último")
+	assert.Equal(t, "01310100", results[0].Cep)
+	assert.Equal(t, 32.2, results[0].Temperature.Celsius)
+	assert.ErrorIs(t, results[1].Err, ErrInvalidZipcode)
+	assert.ErrorIs(t, results[2].Err, cErrors.CepClientInternalError)
+}
+
+func TestEachTemperature_StopsWhenCallbackFails(t *testing.T) {
+	// arrange
+	svc, cepClient, _ := newTestService()
+	cepClient.On("GetCep", mock.Anything, mock.Anything).Return(nil, cErrors.CepClientInternalError)
+
+	// act
+	calls := 0
+	err := svc.EachTemperature(context.Background(), []string{"01310100", "20040002", "30130010"}, func(int, TemperatureResult) error {
+		calls++
+		return context.Canceled
+	})
+
+	// assert
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls, "nenhum resultado deve ser entregue depois da falha")
+}
+
+func TestGetTemperatures_OneChildSpanPerCep(t *testing.T) {
+	// arrange
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	svc, cepClient, weatherClient := newTestService()
+
+	cepClient.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("01310-100"), nil)
+	weatherClient.On("GetWeather", mock.Anything, "São Paulo").Return(model.GetWeatherResponseMock("São Paulo"), nil)
+
+	// act
+	svc.GetTemperatures(context.Background(), []string{"01310100", "20040002", "30130010", "20040-002"})
+
+	// assert
+	var parent sdktrace.ReadOnlySpan
+	var children []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		switch span.Name() {
+		case "batch temperature":
+			parent = span
+		case "temperature lookup":
+			children = append(children, span)
+		}
+	}
+
+	require.NotNil(t, parent)
+	require.Len(t, children, 3)
+	for _, child := range children {
+		assert.Equal(t, parent.SpanContext().SpanID(), child.Parent().SpanID())
+	}
+}
+
+func TestGetTemperature_ConversionSpan(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		spans   int
+	}{
+		{"instrumentação interna ligada", true, 1},
+		{"instrumentação interna desligada", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			recorder := tracetest.NewSpanRecorder()
+			previous := otel.GetTracerProvider()
+			otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+			t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+			cepClient := client.NewCepClientStub(nil)
+			weatherClient := client.NewWeatherClientStub(nil)
+			svc := NewTemperatureService(&config.Config{TraceInternalSpans: tt.enabled}, cepClient, weatherClient, nil)
+
+			cepClient.On("GetCep", mock.Anything, "01310100").Return(model.GetViacepResponseMock("01310-100"), nil)
+			weatherClient.On("GetWeather", mock.Anything, "São Paulo").Return(model.GetWeatherResponseMock("São Paulo"), nil)
+
+			// act
+			_, err := svc.GetTemperature(context.Background(), "01310100")
+
+			// assert
+			require.NoError(t, err)
+			var conversions int
+			for _, span := range recorder.Ended() {
+				if span.Name() == "convert temperature" {
+					conversions++
+				}
+			}
+			assert.Equal(t, tt.spans, conversions)
+		})
+	}
+}
+
+func TestGetTemperature_CachePartitionedByTenant(t *testing.T) {
+	// arrange
+	cepClient := client.NewCepClientStub(nil)
+	weatherClient := client.NewWeatherClientStub(nil)
+	cfg := &config.Config{CacheTTL: time.Minute, CacheMaxEntries: 10}
+	svc := NewTemperatureService(cfg, cepClient, weatherClient, nil)
+
+	cepClient.On("GetCep", mock.Anything, "01310100").Return(model.GetViacepResponseMock("01310-100"), nil)
+	weatherClient.On("GetWeather", mock.Anything, "São Paulo").Return(model.GetWeatherResponseMock("São Paulo"), nil)
+
+	teamA, err := tenant.WithTenant(context.Background(), "team-a")
+	require.NoError(t, err)
+	teamB, err := tenant.WithTenant(context.Background(), "team-b")
+	require.NoError(t, err)
+
+	// act
+	first, err := svc.GetTemperature(teamA, "01310100")
+	require.NoError(t, err)
+	cached, err := svc.GetTemperature(teamA, "01310-100")
+	require.NoError(t, err)
+	_, err = svc.GetTemperature(teamB, "01310100")
+	require.NoError(t, err)
+
+	// assert
+	assert.Equal(t, first, cached)
+	cepClient.AssertNumberOfCalls(t, "GetCep", 2)
+	weatherClient.AssertNumberOfCalls(t, "GetWeather", 2)
+}
@@ -0,0 +1,109 @@
+package tenant
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// maxTenants bounds the buckets kept; once it is reached, full buckets are dropped,
+// then the bucket idle the longest
+const maxTenants = 10_000
+
+type bucket struct {
+	limiter *rate.Limiter
+	seen    time.Time
+}
+
+// Limiter keeps one token bucket per tenant.
+// A nil *Limiter is valid and allows every request.
+type Limiter struct {
+	mu         sync.Mutex
+	limit      rate.Limit
+	burst      int
+	maxTenants int
+	now        func() time.Time
+	tenants    map[string]*bucket
+}
+
+// NewLimiter allows each tenant perSecond requests with bursts of up to burst.
+// It returns nil, which disables limiting, when perSecond is not positive.
+func NewLimiter(perSecond float64, burst int) *Limiter {
+	return newLimiterWithClock(perSecond, burst, time.Now)
+}
+
+func newLimiterWithClock(perSecond float64, burst int, now func() time.Time) *Limiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &Limiter{
+		limit:      rate.Limit(perSecond),
+		burst:      max(burst, 1),
+		maxTenants: maxTenants,
+		now:        now,
+		tenants:    make(map[string]*bucket),
+	}
+}
+
+// Allow reports whether the tenant may make a request now, consuming a token if so
+func (l *Limiter) Allow(tenant string) bool {
+	if l == nil {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b, ok := l.tenants[tenant]
+	if !ok {
+		if len(l.tenants) >= l.maxTenants {
+			l.evict(now)
+		}
+		b = &bucket{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.tenants[tenant] = b
+	}
+	b.seen = now
+	return b.limiter.AllowN(now, 1)
+}
+
+// RetryAfter returns how long until the tenant's bucket holds a token again, or zero
+// when it has one now
+func (l *Limiter) RetryAfter(tenant string) time.Duration {
+	if l == nil {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.tenants[tenant]
+	if !ok {
+		return 0
+	}
+	missing := 1 - b.limiter.TokensAt(l.now())
+	if missing <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(missing / float64(l.limit) * float64(time.Second)))
+}
+
+// evict drops the buckets that have refilled completely, as they hold no state, and
+// then the bucket idle the longest if that freed no room
+func (l *Limiter) evict(now time.Time) {
+	var idlest string
+	for tenant, b := range l.tenants {
+		if b.limiter.TokensAt(now) >= float64(l.burst) {
+			delete(l.tenants, tenant)
+			continue
+		}
+		if idlest == "" || b.seen.Before(l.tenants[idlest].seen) {
+			idlest = tenant
+		}
+	}
+	if len(l.tenants) >= l.maxTenants {
+		delete(l.tenants, idlest)
+	}
+}
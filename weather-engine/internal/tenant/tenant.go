@@ -0,0 +1,132 @@
+package tenant
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alexduzi/laboteldistributedtracing/platform/problem"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// BaggageKey is the baggage member carrying the tenant set by the cep-gateway
+const BaggageKey = "tenant.id"
+
+// Anonymous identifies callers that did not send a tenant
+const Anonymous = "anonymous"
+
+// ErrorDomain is the domain of the ErrorInfo details of the gRPC errors
+const ErrorDomain = "weather-engine"
+
+const (
+	CodeRateLimited = "rate_limited"
+	MsgRateLimited  = "rate limit exceeded"
+)
+
+// FromContext returns the tenant carried in the baggage of ctx, or Anonymous
+func FromContext(ctx context.Context) string {
+	if id := baggage.FromContext(ctx).Member(BaggageKey).Value(); id != "" {
+		return id
+	}
+	return Anonymous
+}
+
+// WithTenant returns a copy of ctx whose baggage carries the tenant
+func WithTenant(ctx context.Context, id string) (context.Context, error) {
+	member, err := baggage.NewMemberRaw(BaggageKey, id)
+	if err != nil {
+		return ctx, err
+	}
+	bag, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return ctx, err
+	}
+	return baggage.ContextWithBaggage(ctx, bag), nil
+}
+
+// Attribute returns the tenant of ctx as a span or metric attribute
+func Attribute(ctx context.Context) attribute.KeyValue {
+	return attribute.String(BaggageKey, FromContext(ctx))
+}
+
+// Middleware tags the server span with the tenant found in the propagated baggage
+// and answers 429 with Retry-After once the tenant exceeds its rate limit. It must run after otelgin,
+// which extracts the baggage from the request headers.
+func Middleware(limiter *Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		id := FromContext(ctx)
+
+		span := trace.SpanFromContext(ctx)
+		span.SetAttributes(attribute.String(BaggageKey, id))
+
+		if !limiter.Allow(id) {
+			span.AddEvent("tenant rate limited")
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(limiter.RetryAfter(id).Seconds()))))
+			problem.Abort(c, problem.New(http.StatusTooManyRequests, CodeRateLimited, MsgRateLimited))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// UnaryServerInterceptor applies the same tagging and rate limiting to unary gRPC calls
+func UnaryServerInterceptor(limiter *Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := admit(ctx, limiter); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor applies the same tagging and rate limiting to streaming gRPC calls
+func StreamServerInterceptor(limiter *Limiter) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := admit(ss.Context(), limiter); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func admit(ctx context.Context, limiter *Limiter) error {
+	id := FromContext(ctx)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String(BaggageKey, id))
+
+	if !limiter.Allow(id) {
+		span.AddEvent("tenant rate limited")
+		return RetryableError(codes.ResourceExhausted, CodeRateLimited, MsgRateLimited, limiter.RetryAfter(id))
+	}
+	return nil
+}
+
+// RetryableError returns a status error with code whose ErrorInfo detail carries reason,
+// the problem code of the HTTP API, and whose RetryInfo detail tells the caller to retry
+// after retryAfter; the RetryInfo is left out when retryAfter is zero
+func RetryableError(code codes.Code, reason, msg string, retryAfter time.Duration) error {
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: reason, Domain: ErrorDomain}}
+	if retryAfter > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+	}
+
+	st := status.New(code, msg)
+	if detailed, err := st.WithDetails(details...); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
@@ -0,0 +1,220 @@
+package tenant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func setupRouter(limiter *Limiter) (*gin.Engine, *tracetest.SpanRecorder, *[]string) {
+	gin.SetMode(gin.TestMode)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	var seen []string
+	router := gin.New()
+	router.Use(otelgin.Middleware("weather-engine-test",
+		otelgin.WithTracerProvider(tp),
+		otelgin.WithPropagators(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))),
+		Middleware(limiter))
+	router.GET("/ping", func(c *gin.Context) {
+		seen = append(seen, FromContext(c.Request.Context()))
+		c.Status(http.StatusOK)
+	})
+
+	return router, recorder, &seen
+}
+
+func TestWithTenant(t *testing.T) {
+	// act
+	ctx, err := WithTenant(context.Background(), "team-a")
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, "team-a", FromContext(ctx))
+	assert.Equal(t, Anonymous, FromContext(context.Background()))
+	assert.Equal(t, attribute.String(BaggageKey, "team-a"), Attribute(ctx))
+}
+
+func TestMiddleware_ReadsBaggage(t *testing.T) {
+	// arrange
+	router, recorder, seen := setupRouter(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set("baggage", "tenant.id=team-a")
+
+	// act
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"team-a", Anonymous}, *seen)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "team-a", spanAttribute(spans[0], BaggageKey).AsString())
+	assert.Equal(t, Anonymous, spanAttribute(spans[1], BaggageKey).AsString())
+}
+
+func TestMiddleware_RateLimitsPerTenant(t *testing.T) {
+	// arrange
+	router, recorder, _ := setupRouter(NewLimiter(0.001, 2))
+
+	var retryAfter []string
+	request := func(id string) int {
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set("baggage", "tenant.id="+id)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		retryAfter = append(retryAfter, w.Header().Get("Retry-After"))
+		return w.Code
+	}
+
+	// act
+	statuses := []int{request("team-a"), request("team-a"), request("team-a"), request("team-b")}
+
+	// assert
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests, http.StatusOK}, statuses)
+	assert.Equal(t, []string{"", "", "1000", ""}, retryAfter, "a recusa deve dizer quando tentar de novo")
+
+	limited := recorder.Ended()[2]
+	require.Len(t, limited.Events(), 1)
+	assert.Equal(t, "tenant rate limited", limited.Events()[0].Name)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	// arrange
+	interceptor := UnaryServerInterceptor(NewLimiter(0.001, 1))
+	ctx, err := WithTenant(context.Background(), "team-a")
+	require.NoError(t, err)
+
+	var calls int
+	handler := func(ctx context.Context, req any) (any, error) {
+		calls++
+		return "ok", nil
+	}
+
+	// act
+	first, firstErr := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+	_, secondErr := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+
+	// assert
+	assert.NoError(t, firstErr)
+	assert.Equal(t, "ok", first)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(secondErr))
+	assert.Equal(t, 1, calls)
+
+	details := status.Convert(secondErr).Details()
+	require.Len(t, details, 2)
+	info, ok := details[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, CodeRateLimited, info.GetReason())
+	retry, ok := details[1].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	assert.InDelta(t, 1000, retry.GetRetryDelay().AsDuration().Seconds(), 1)
+}
+
+func TestLimiter(t *testing.T) {
+	t.Run("limitador nulo permite tudo", func(t *testing.T) {
+		// arrange
+		var limiter *Limiter
+
+		// act & assert
+		for range 100 {
+			assert.True(t, limiter.Allow("team-a"))
+		}
+	})
+
+	t.Run("taxa zero desativa o limite", func(t *testing.T) {
+		// act & assert
+		assert.Nil(t, NewLimiter(0, 10))
+	})
+
+	t.Run("buckets independentes por tenant", func(t *testing.T) {
+		// arrange
+		limiter := NewLimiter(0.001, 3)
+
+		// act
+		var allowedA, allowedB int
+		for range 5 {
+			if limiter.Allow("team-a") {
+				allowedA++
+			}
+		}
+		if limiter.Allow("team-b") {
+			allowedB++
+		}
+
+		// assert
+		assert.Equal(t, 3, allowedA)
+		assert.Equal(t, 1, allowedB)
+	})
+
+	t.Run("limita a quantidade de buckets", func(t *testing.T) {
+		// arrange
+		now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+		limiter := newLimiterWithClock(1, 2, func() time.Time { return now })
+		limiter.maxTenants = 2
+
+		// act
+		limiter.Allow("team-a")
+		limiter.Allow("team-a")
+		now = now.Add(100 * time.Millisecond)
+		limiter.Allow("team-b")
+		limiter.Allow("team-b")
+		now = now.Add(100 * time.Millisecond)
+		limiter.Allow("team-c")
+
+		// assert
+		assert.Len(t, limiter.tenants, 2)
+		assert.NotContains(t, limiter.tenants, "team-a", "o bucket ocioso há mais tempo deve sair")
+		assert.False(t, limiter.Allow("team-b"), "buckets mantidos não podem ser zerados")
+	})
+
+	t.Run("descarta primeiro os buckets cheios", func(t *testing.T) {
+		// arrange
+		now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+		limiter := newLimiterWithClock(1, 2, func() time.Time { return now })
+		limiter.maxTenants = 2
+
+		limiter.Allow("team-a")
+		limiter.Allow("team-b")
+		limiter.Allow("team-b")
+		now = now.Add(time.Second)
+
+		// act
+		limiter.Allow("team-c")
+
+		// assert
+		assert.Len(t, limiter.tenants, 2)
+		assert.NotContains(t, limiter.tenants, "team-a")
+		assert.Contains(t, limiter.tenants, "team-b")
+	})
+}